@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const hoursPerDay = 24
+
+func runBlame(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("blame", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	repo := flags.String("repo", ".", "git checkout the coverage paths are relative to")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	attribution, err := gocovparser.AttributeUncovered(items, *repo)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	fmt.Fprintf(
+		stdout, "uncovered: %d lines, %d statements\n", attribution.Total.Lines, attribution.Total.Statements,
+	)
+
+	// buckets are exclusive, each one holds the lines older than the previous bucket
+	from := 0
+
+	for _, age := range attribution.ByAge {
+		label := fmt.Sprintf("older than %d days", from-1)
+
+		switch {
+		case age.MaxAge > 0:
+			to := int(age.MaxAge / (hoursPerDay * time.Hour))
+			label = fmt.Sprintf("%d-%d days", from, to)
+			from = to + 1
+		case from == 0:
+			label = "any age"
+		}
+
+		fmt.Fprintf(
+			stdout, "%s: %d lines (%.2f%%), %d statements\n", label, age.Lines, age.Percent*100, age.Statements,
+		)
+	}
+
+	authors := make([]string, 0, len(attribution.ByAuthor))
+	for author := range attribution.ByAuthor {
+		authors = append(authors, author)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		first, second := attribution.ByAuthor[authors[i]], attribution.ByAuthor[authors[j]]
+		if first.Lines != second.Lines {
+			return first.Lines > second.Lines
+		}
+
+		return authors[i] < authors[j]
+	})
+
+	fmt.Fprintln(stdout, "by author:")
+
+	for _, author := range authors {
+		count := attribution.ByAuthor[author]
+		fmt.Fprintf(stdout, "  %s\t%d lines, %d statements\n", author, count.Lines, count.Statements)
+	}
+
+	return exitOK
+}
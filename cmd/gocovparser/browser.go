@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBold   = "\x1b[1m"
+	ansiSelect = "\x1b[7m"
+
+	treePaneRatio = 0.4
+	tabWidth      = 4
+	lowCoverage   = 0.5
+	highCoverage  = 0.8
+)
+
+// key is a key press understood by the browser.
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyTab
+	keySort
+	keyQuit
+)
+
+// treeRow is a visible row of the package tree.
+type treeRow struct {
+	node  *gocovparser.CoverageNode
+	depth int
+}
+
+// browser holds the state of the interactive coverage browser, independently of the terminal.
+type browser struct {
+	root       *gocovparser.CoverageNode
+	files      map[string]gocovparser.Coverage
+	lines      map[string]gocovparser.FileLineCoverage
+	sourceRoot string
+	sources    map[string][]string
+
+	expanded       map[string]bool
+	sortByCoverage bool
+	rows           []treeRow
+	selected       int
+	treeOffset     int
+
+	// focusFile is set when keys scroll the file view instead of moving in the tree.
+	focusFile  bool
+	fileOffset int
+}
+
+func newBrowser(items []gocovparser.Coverage, sourceRoot string) *browser {
+	b := &browser{
+		root:       gocovparser.BuildCoverageTree(items),
+		files:      make(map[string]gocovparser.Coverage, len(items)),
+		lines:      gocovparser.GetLineCoverage(items),
+		sourceRoot: sourceRoot,
+		sources:    map[string][]string{},
+		expanded:   map[string]bool{},
+	}
+
+	for _, cov := range items {
+		b.files[path.Join(cov.Host, cov.Owner, cov.Repo, cov.Path)] = cov
+	}
+
+	// Open the chain of single children, e.g. the repository of a single module profile.
+	for node := b.root; len(node.Children) == 1 && !node.Children[0].IsFile; node = node.Children[0] {
+		b.expanded[node.Children[0].Path] = true
+	}
+
+	b.refresh()
+
+	return b
+}
+
+// handle applies a key press and returns whether the browser should quit.
+func (b *browser) handle(k key) bool {
+	switch k {
+	case keyQuit:
+		return true
+	case keySort:
+		b.sortByCoverage = !b.sortByCoverage
+		b.refresh()
+	case keyTab:
+		b.focusFile = !b.focusFile && b.current() != nil && b.current().IsFile
+	case keyUp, keyDown, keyPageUp, keyPageDown:
+		b.move(k)
+	case keyRight:
+		b.open()
+	case keyLeft:
+		b.close()
+	case keyNone:
+	}
+
+	return false
+}
+
+func (b *browser) move(k key) {
+	step := 1
+	if k == keyPageUp || k == keyPageDown {
+		step = 10
+	}
+
+	if k == keyUp || k == keyPageUp {
+		step = -step
+	}
+
+	if b.focusFile {
+		b.fileOffset = clamp(b.fileOffset+step, 0, len(b.source(b.current()))-1)
+
+		return
+	}
+
+	b.selected = clamp(b.selected+step, 0, len(b.rows)-1)
+	b.fileOffset = 0
+}
+
+// open expands the selected directory, or focuses the file view on the selected file.
+func (b *browser) open() {
+	node := b.current()
+	if node == nil {
+		return
+	}
+
+	if node.IsFile {
+		b.focusFile = true
+
+		return
+	}
+
+	b.expanded[node.Path] = true
+	b.refresh()
+}
+
+// close leaves the file view, or collapses the selected directory (or its parent).
+func (b *browser) close() {
+	if b.focusFile {
+		b.focusFile = false
+
+		return
+	}
+
+	if b.selected >= len(b.rows) {
+		return
+	}
+
+	row := b.rows[b.selected]
+	if !row.node.IsFile && b.expanded[row.node.Path] {
+		b.expanded[row.node.Path] = false
+		b.refresh()
+
+		return
+	}
+
+	for index := b.selected - 1; index >= 0; index-- {
+		if b.rows[index].depth < row.depth {
+			b.selected = index
+			b.expanded[b.rows[index].node.Path] = false
+			b.refresh()
+
+			return
+		}
+	}
+}
+
+func (b *browser) current() *gocovparser.CoverageNode {
+	if b.selected >= len(b.rows) {
+		return nil
+	}
+
+	return b.rows[b.selected].node
+}
+
+// refresh rebuilds the visible rows, keeping the selected node selected when it is still visible.
+func (b *browser) refresh() {
+	current := b.current()
+	b.rows = b.rows[:0]
+	b.appendRows(b.root, 0)
+	b.selected = 0
+
+	for index, row := range b.rows {
+		if row.node == current {
+			b.selected = index
+		}
+	}
+}
+
+func (b *browser) appendRows(node *gocovparser.CoverageNode, depth int) {
+	for _, child := range b.ordered(node.Children) {
+		b.rows = append(b.rows, treeRow{node: child, depth: depth})
+
+		if b.expanded[child.Path] {
+			b.appendRows(child, depth+1)
+		}
+	}
+}
+
+// ordered returns the nodes sorted by name, or by lowest coverage first when sorting by coverage.
+func (b *browser) ordered(nodes []*gocovparser.CoverageNode) []*gocovparser.CoverageNode {
+	if !b.sortByCoverage {
+		return nodes
+	}
+
+	sorted := make([]*gocovparser.CoverageNode, len(nodes))
+	copy(sorted, nodes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Detail.Percent < sorted[j].Detail.Percent
+	})
+
+	return sorted
+}
+
+// source returns the lines of the file of node, read from the source root once.
+func (b *browser) source(node *gocovparser.CoverageNode) []string {
+	if node == nil || !node.IsFile {
+		return nil
+	}
+
+	if lines, found := b.sources[node.Path]; found {
+		return lines
+	}
+
+	var lines []string
+
+	contents, err := os.ReadFile(gocovparser.SourcePath(b.files[node.Path], b.sourceRoot))
+	if err == nil {
+		lines = strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	}
+
+	b.sources[node.Path] = lines
+
+	return lines
+}
+
+// render returns the screen lines of the browser for a terminal of the given size.
+func (b *browser) render(width, height int) []string {
+	bodyHeight := height - 1
+	treeWidth := int(float64(width) * treePaneRatio)
+	fileWidth := width - treeWidth - 1
+
+	if b.selected < b.treeOffset {
+		b.treeOffset = b.selected
+	}
+
+	if b.selected >= b.treeOffset+bodyHeight {
+		b.treeOffset = b.selected - bodyHeight + 1
+	}
+
+	tree := b.renderTree(treeWidth, bodyHeight)
+	file := b.renderFile(fileWidth, bodyHeight)
+
+	screen := make([]string, 0, height)
+	for index := 0; index < bodyHeight; index++ {
+		screen = append(screen, tree[index]+"│"+file[index])
+	}
+
+	sortName := "name"
+	if b.sortByCoverage {
+		sortName = "coverage"
+	}
+
+	status := fmt.Sprintf(
+		"total %s │ sort: %s │ ↑↓ move  → open  ← close  tab switch  s sort  q quit",
+		formatRatio(b.root.Detail), sortName,
+	)
+
+	return append(screen, fit(status, width))
+}
+
+func (b *browser) renderTree(width, height int) []string {
+	lines := make([]string, height)
+
+	for index := range lines {
+		rowIndex := b.treeOffset + index
+		if rowIndex >= len(b.rows) {
+			lines[index] = fit("", width)
+
+			continue
+		}
+
+		row := b.rows[rowIndex]
+		marker := "  "
+
+		switch {
+		case row.node.IsFile:
+		case b.expanded[row.node.Path]:
+			marker = "▾ "
+		default:
+			marker = "▸ "
+		}
+
+		percent := fmt.Sprintf(" %6.2f%%", row.node.Detail.Percent*100)
+		name := fit(strings.Repeat("  ", row.depth)+marker+row.node.Name, width-utf8.RuneCountInString(percent))
+		line := name + coverageColor(row.node.Detail.Percent) + percent + ansiReset
+
+		if rowIndex == b.selected {
+			line = ansiSelect + name + percent + ansiReset
+		}
+
+		lines[index] = line
+	}
+
+	return lines
+}
+
+func (b *browser) renderFile(width, height int) []string {
+	lines := make([]string, height)
+	for index := range lines {
+		lines[index] = fit("", width)
+	}
+
+	node := b.current()
+	if node == nil || height == 0 {
+		return lines
+	}
+
+	lines[0] = ansiBold + fit(" "+node.Path+" "+formatRatio(node.Detail), width) + ansiReset
+
+	if !node.IsFile {
+		return lines
+	}
+
+	source := b.source(node)
+	if source == nil {
+		if height > 1 {
+			lines[1] = fit(" source not found under "+b.sourceRoot, width)
+		}
+
+		return lines
+	}
+
+	coverage := b.lines[b.files[node.Path].FileName]
+
+	for index := 1; index < height; index++ {
+		lineNumber := b.fileOffset + index
+		if lineNumber > len(source) {
+			break
+		}
+
+		lines[index] = annotateLine(lineNumber, source[lineNumber-1], coverage, width)
+	}
+
+	return lines
+}
+
+// annotateLine formats a source line with its number, hit count and coverage color.
+func annotateLine(number int, text string, coverage gocovparser.FileLineCoverage, width int) string {
+	hits := ""
+	color := ""
+
+	if line, found := coverage[number]; found {
+		hits = fmt.Sprintf("%dx", line.Hits)
+
+		switch line.Status {
+		case gocovparser.LineCovered:
+			color = ansiGreen
+		case gocovparser.LinePartial:
+			color = ansiYellow
+		case gocovparser.LineUncovered:
+			color = ansiRed
+		}
+	}
+
+	text = strings.ReplaceAll(text, "\t", strings.Repeat(" ", tabWidth))
+	line := fit(fmt.Sprintf("%5d %6s  %s", number, hits, text), width)
+
+	if color == "" {
+		return line
+	}
+
+	return color + line + ansiReset
+}
+
+func coverageColor(percent float64) string {
+	switch {
+	case percent < lowCoverage:
+		return ansiRed
+	case percent < highCoverage:
+		return ansiYellow
+	default:
+		return ansiGreen
+	}
+}
+
+func formatRatio(detail gocovparser.GroupDetail) string {
+	return fmt.Sprintf("%.2f%% (%d/%d)", detail.Percent*100, detail.Covered, detail.Total)
+}
+
+// fit truncates or pads text with spaces to exactly width runes.
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	count := utf8.RuneCountInString(text)
+	if count <= width {
+		return text + strings.Repeat(" ", width-count)
+	}
+
+	runes := []rune(text)
+
+	return string(runes[:width-1]) + "…"
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+
+	if value < low {
+		value = low
+	}
+
+	return value
+}
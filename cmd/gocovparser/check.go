@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("check", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	minTotal := flags.Float64("min-total", 0, "minimum total coverage percentage (0-100)")
+	minPackage := flags.Float64("min-package", 0, "minimum coverage percentage (0-100) of every package")
+	maxUncovered := flags.Int("max-package-uncovered", 0, "maximum number of uncovered statements of every package")
+	minStatements := flags.Int("min-package-statements", 0, "ignore packages with fewer statements in package checks")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, classify.options(false)...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	result, err := gocovparser.GroupCoverageDetailed(
+		items, []gocovparser.ParseGroup{gocovparser.PackageParseGroup, gocovparser.TotalParseGroup},
+	)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	policy := gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{gocovparser.MinimumTotal(*minTotal / 100)},
+	}
+
+	if *minPackage > 0 || *maxUncovered > 0 {
+		policy.Rules = append(policy.Rules, gocovparser.PolicyRule{
+			Group:         gocovparser.PackageParseGroup.Name,
+			Minimum:       *minPackage / 100,
+			MaxUncovered:  *maxUncovered,
+			MinStatements: *minStatements,
+		})
+	}
+
+	violations := gocovparser.CheckPolicyDetailed(result, policy)
+	for _, violation := range violations {
+		fmt.Fprintln(stdout, violation.String())
+	}
+
+	if len(violations) > 0 {
+		return exitFailed
+	}
+
+	fmt.Fprintf(stdout, "coverage check passed: total %.2f%%\n", result["total"]["total"].Percent*100)
+
+	return exitOK
+}
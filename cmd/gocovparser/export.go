@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+)
+
+// optionsFlag collects repeated `--option key=value` flags.
+type optionsFlag export.Options
+
+func (o optionsFlag) String() string {
+	return fmt.Sprint(export.Options(o))
+}
+
+func (o optionsFlag) Set(value string) error {
+	key, optionValue, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+
+	o[key] = optionValue
+
+	return nil
+}
+
+func runExport(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("export", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	format := flags.String("format", "lcov", "export format: "+strings.Join(export.Names(), ", "))
+	output := flags.String("output", "", "file to write to (defaults to stdout)")
+	options := optionsFlag{}
+	flags.Var(options, "option", "export option as key=value, may be repeated (e.g. --option=level=warning)")
+	verbose := flags.Bool("verbose", false, "log the parsing and exporting progress to stderr")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	exporter, err := export.Lookup(*format)
+	if errors.Is(err, export.ErrUnknownFormat) {
+		return fail(stderr, fmt.Errorf("unknown export format %q", *format))
+	}
+
+	parseOpts := classify.options(false)
+
+	if *verbose {
+		logger := newTextLogger(stderr)
+		parseOpts = append(parseOpts, gocovparser.WithLogger(logger))
+		exporter = export.Instrument(exporter, logger, nil)
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, parseOpts...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	w := stdout
+
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fail(stderr, err)
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	if err := exporter.Write(w, items, export.Options(options)); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
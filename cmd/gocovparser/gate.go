@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/gate"
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/pkg/errors"
+)
+
+func runGate(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("gate", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	branch := flags.String("branch", "main", "base branch the coverage is compared to")
+	maxDrop := flags.Float64("max-drop", 0, "largest drop of the total coverage percentage allowed")
+	maxPackageDrop := flags.Float64("max-package-drop", -1, "largest drop of the coverage percentage of each package")
+	requireBaseline := flags.Bool("require-baseline", false, "fail when the base branch has no baseline")
+	historyFile := flags.String("history", "", "JSON lines history store holding the baseline")
+	githubArtifact := flags.String("github-artifact", "", "GitHub Actions artifact holding the baseline snapshot")
+	gitlabArtifact := flags.String("gitlab-artifact", "", "path of the baseline snapshot in GitLab CI job artifacts")
+	gitlabJob := flags.String("gitlab-job", "", "GitLab CI job whose artifacts hold the baseline snapshot")
+	snapshot := flags.String("snapshot", "", "write the snapshot of the coverage to this file, e.g. to upload it")
+	commit := flags.String("commit", "", "commit recorded in the snapshot")
+	snapshotBranch := flags.String("snapshot-branch", "", "branch recorded in the snapshot")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, classify.options(false)...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	opts := gate.Options{Branch: *branch, MaxDrop: *maxDrop / 100, RequireBaseline: *requireBaseline}
+	if *maxPackageDrop >= 0 {
+		opts.Groups = []gocovparser.ParseGroup{gocovparser.PackageParseGroup}
+		opts.MaxGroupDrop = *maxPackageDrop / 100
+	}
+
+	if *snapshot != "" {
+		if err := writeSnapshot(*snapshot, *commit, *snapshotBranch, items); err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	switch {
+	case *historyFile != "":
+		opts.Baseline = gate.HistoryBaseline(history.NewJSONLinesStore(*historyFile))
+	case *githubArtifact != "":
+		opts.Baseline = gate.NewGitHubArtifactBaseline(gate.GitHubOptions{
+			Repository: os.Getenv("GITHUB_REPOSITORY"),
+			Name:       *githubArtifact,
+			Token:      os.Getenv("GITHUB_TOKEN"),
+			Endpoint:   os.Getenv("GITHUB_API_URL"),
+		})
+	case *gitlabArtifact != "":
+		opts.Baseline = gate.NewGitLabArtifactBaseline(gate.GitLabOptions{
+			Project:  os.Getenv("CI_PROJECT_ID"),
+			Job:      *gitlabJob,
+			Path:     *gitlabArtifact,
+			Token:    os.Getenv("CI_JOB_TOKEN"),
+			JobToken: true,
+			Endpoint: os.Getenv("CI_SERVER_URL"),
+		})
+	case *snapshot != "":
+		// only recording the snapshot, e.g. on the base branch
+		return exitOK
+	default:
+		return fail(stderr, errors.New("one of --history, --github-artifact or --gitlab-artifact is required"))
+	}
+
+	result, err := gate.Check(context.Background(), items, opts)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	fmt.Fprint(stdout, result.Explanation)
+
+	if !result.Passed {
+		return exitFailed
+	}
+
+	return exitOK
+}
+
+// writeSnapshot writes the snapshot of the coverage, grouped by package, as the baseline of later gates.
+func writeSnapshot(path, commit, branch string, items []gocovparser.Coverage) error {
+	snapshot, err := history.NewSnapshot(commit, items, gocovparser.PackageParseGroup)
+	if err != nil {
+		return err
+	}
+
+	snapshot.Branch = branch
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize snapshot")
+	}
+
+	return errors.Wrapf(os.WriteFile(path, data, 0o644), "failed to write snapshot %q", path)
+}
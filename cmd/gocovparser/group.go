@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+func runGroup(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("group", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	by := flags.String("by", "package", "group by package, file, repo, owner, directory, codeowner, classification or total")
+	depth := flags.Int("depth", 1, "number of path segments used when grouping by directory")
+	codeowners := flags.String("codeowners", ".github/CODEOWNERS", "CODEOWNERS file used when grouping by codeowner")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	group, err := gocovparser.ParseGroupByName(*by, *depth, *codeowners)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, classify.options(*by == gocovparser.ClassificationParseGroupName)...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	details := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return group.Key(cov)
+	})
+
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		detail := details[key]
+		fmt.Fprintf(stdout, "%s\t%.2f%%\t%d/%d\n", key, detail.Percent*100, detail.Covered, detail.Total)
+	}
+
+	return exitOK
+}
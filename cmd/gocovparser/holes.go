@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const defaultHolesLimit = 10
+
+func runHoles(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("holes", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	limit := flags.Int("limit", defaultHolesLimit, "number of holes listed (per group with --by), 0 for all")
+	by := flags.String("by", "", "list the holes per package, file, repo, owner, directory or codeowner")
+	depth := flags.Int("depth", 1, "number of path segments used when grouping by directory")
+	codeowners := flags.String("codeowners", ".github/CODEOWNERS", "CODEOWNERS file used when grouping by codeowner")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	var group gocovparser.ParseGroup
+
+	if *by != "" {
+		var err error
+
+		group, err = gocovparser.ParseGroupByName(*by, *depth, *codeowners)
+		if err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if *by == "" {
+		printHoles(stdout, gocovparser.GetCoverageHoles(items, *limit), "")
+
+		return exitOK
+	}
+
+	grouped := gocovparser.GroupCoverageHoles(items, group, *limit)
+
+	keys := make([]string, 0, len(grouped))
+	for key := range grouped {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(stdout, "%s\n", key)
+		printHoles(stdout, grouped[key], "  ")
+	}
+
+	return exitOK
+}
+
+func printHoles(w io.Writer, holes []gocovparser.CoverageHole, indent string) {
+	for _, hole := range holes {
+		fmt.Fprintf(w, "%s%s:%s\t%d statements\n", indent, hole.Path, hole.Lines, hole.Statements)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+// textLogger writes logs as `level=DEBUG msg="parsed coverage" files=28` lines, for the --verbose flags.
+type textLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+var _ gocovparser.Logger = (*textLogger)(nil)
+
+func newTextLogger(w io.Writer) *textLogger {
+	return &textLogger{w: w}
+}
+
+func (l *textLogger) Debug(msg string, args ...any) {
+	l.log("DEBUG", msg, args)
+}
+
+func (l *textLogger) Info(msg string, args ...any) {
+	l.log("INFO", msg, args)
+}
+
+func (l *textLogger) log(level, msg string, args []any) {
+	var line strings.Builder
+
+	fmt.Fprintf(&line, "level=%s msg=%s", level, logValue(msg))
+
+	for i := 0; i < len(args); i += 2 {
+		key := fmt.Sprint(args[i])
+
+		var value any = "!MISSING"
+		if i+1 < len(args) {
+			value = args[i+1]
+		}
+
+		fmt.Fprintf(&line, " %s=%s", key, logValue(fmt.Sprint(value)))
+	}
+
+	line.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = io.WriteString(l.w, line.String())
+}
+
+// logValue quotes values that are empty or contain spaces, quotes or equal signs.
+func logValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
@@ -0,0 +1,190 @@
+// Command gocovparser parses, groups, exports and checks go coverage profiles.
+//
+// Usage:
+//
+//	gocovparser total [--distribution] [--packages=./internal/...] [--exclude-generated] [--exclude-missing] [coverage.out]
+//	gocovparser group --by=package|classification [--source-root=dir] [coverage.out]
+//	gocovparser export [--verbose] --format=lcov|cobertura|codecov|sonar|github|gitlab|bitbucket|protobuf|prometheus|coverage-gutters|editor-json|json [--option=key=value] [--output=file] [coverage.out]
+//	gocovparser check --min-total=80 [--min-package=70] [coverage.out]
+//	gocovparser gate --branch=main --history=file|--github-artifact=name|--gitlab-artifact=path [--max-drop=0.5] [--snapshot=file] [coverage.out]
+//	gocovparser uncovered [coverage.out]
+//	gocovparser holes [--limit=10] [--by=package] [coverage.out]
+//	gocovparser risk [--limit=10] [--source-root=dir] [--exclude-generated] [coverage.out]
+//	gocovparser blame [--repo=.] [coverage.out]
+//	gocovparser tui [--source-root=dir] [coverage.out]
+//	gocovparser run [--config=gocovparser.yaml]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitError  = 2
+
+	defaultCoverageFile = "coverage.out"
+)
+
+// command is a gocovparser subcommand.
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdout, stderr io.Writer) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "total", description: "print the total coverage", run: runTotal},
+		{name: "group", description: "print the coverage grouped by package, file, repo, owner, directory, codeowner or classification", run: runGroup},
+		{name: "export", description: "export the coverage in one of the registered formats", run: runExport},
+		{name: "check", description: "fail if the coverage is below the minimums", run: runCheck},
+		{name: "gate", description: "fail if the coverage dropped from the baseline of the base branch", run: runGate},
+		{name: "uncovered", description: "list the uncovered line ranges of each file", run: runUncovered},
+		{name: "holes", description: "rank the largest contiguous uncovered regions", run: runHoles},
+		{name: "risk", description: "rank functions by complexity weighted by their uncovered statements", run: runRisk},
+		{name: "blame", description: "attribute uncovered lines to authors and commit ages with git blame", run: runBlame},
+		{name: "tui", description: "browse the coverage tree and annotated sources in the terminal", run: runTUI},
+		{name: "run", description: "parse, check and export the coverage as described by gocovparser.yaml", run: runConfig},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+
+		return exitError
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	usage(stderr)
+
+	return exitError
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gocovparser <command> [flags] [coverage.out]")
+	fmt.Fprintln(w, "\nCommands:")
+
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+}
+
+// newFlagSet returns a flag set for the command that writes its errors to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	return flags
+}
+
+// parseCoverage parses the coverage file given as the single positional argument, or coverage.out.
+func parseCoverage(
+	flags *flag.FlagSet, moduleRoot string, opts ...gocovparser.ParseOption,
+) ([]gocovparser.Coverage, error) {
+	path := defaultCoverageFile
+
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		path = flags.Arg(0)
+	default:
+		return nil, fmt.Errorf("expected a single coverage file, got %d", flags.NArg())
+	}
+
+	if moduleRoot != "" {
+		opts = append(opts, gocovparser.WithModuleRoot(moduleRoot))
+	}
+
+	return gocovparser.ParseFile(path, opts...)
+}
+
+// packagesFlag adds the --packages flag, restricting the coverage to comma separated package prefixes.
+func packagesFlag(flags *flag.FlagSet) *string {
+	return flags.String("packages", "", "comma separated package prefixes to restrict the coverage to (e.g. ./internal/payment/...)")
+}
+
+// selectPackages returns the coverage of the packages of the --packages flag, or every item if it is blank.
+func selectPackages(items []gocovparser.Coverage, packages string) []gocovparser.Coverage {
+	if packages == "" {
+		return items
+	}
+
+	return gocovparser.SelectPackages(items, strings.Split(packages, ",")...)
+}
+
+// classification holds the flags classifying generated files and files missing from the source tree.
+type classification struct {
+	sourceRoot       *string
+	excludeGenerated *bool
+	excludeMissing   *bool
+}
+
+// classificationFlags adds the --source-root, --exclude-generated and --exclude-missing flags.
+func classificationFlags(flags *flag.FlagSet) classification {
+	return classification{
+		sourceRoot:       flags.String("source-root", ".", "directory coverage paths are relative to, used to classify files"),
+		excludeGenerated: flags.Bool("exclude-generated", false, "exclude generated files and cgo shims"),
+		excludeMissing:   flags.Bool("exclude-missing", false, "exclude files missing from the source root"),
+	}
+}
+
+// options classifies the coverage when any file is excluded, or when required (e.g. grouping by classification).
+func (c classification) options(required bool) []gocovparser.ParseOption {
+	if !required && !*c.excludeGenerated && !*c.excludeMissing {
+		return nil
+	}
+
+	return []gocovparser.ParseOption{gocovparser.WithClassification(*c.sourceRoot)}
+}
+
+// exclude drops the files excluded by the flags.
+func (c classification) exclude(items []gocovparser.Coverage) []gocovparser.Coverage {
+	filters := []gocovparser.Filter{}
+
+	if *c.excludeGenerated {
+		filters = append(filters, gocovparser.GeneratedCodeExcludeFilter(*c.sourceRoot))
+	}
+
+	if *c.excludeMissing {
+		filters = append(filters, gocovparser.MissingFileExcludeFilter())
+	}
+
+	if len(filters) == 0 {
+		return items
+	}
+
+	items, _ = gocovparser.FilterCoverage(items, filters...)
+
+	return items
+}
+
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "error: %v\n", err)
+
+	return exitError
+}
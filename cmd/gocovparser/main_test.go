@@ -0,0 +1,361 @@
+package main
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixture = "../../gocovparser/coverage-fixture4.out"
+
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestTotalCommand(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "total", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "total: 77.38% (667/862 statements in 28 files)\n", stdout)
+}
+
+func TestTotalCommandWithDistribution(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "total", "--distribution", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "files: 28 (0 at 0%, 7 at 100%)\np10: 51.23%  median: 82.84%  p90: 100.00%")
+	assert.Contains(t, stdout, " 90%-100%\t12\n")
+}
+
+func TestTotalCommandForPackages(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "total", "--packages=./internal/config/...", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "total: 60.58% (63/104 statements in 5 files)\n", stdout)
+}
+
+func TestGroupCommand(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "group", "--by=directory", "--depth=4", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "github.cbhq.net/risk/data-tracker-backend/internal\t70.02%\t404/577\n")
+}
+
+func TestGroupCommandByClassification(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "group", "--by=classification", "--source-root="+t.TempDir(), fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "missing\t77.38%\t667/862\n", stdout)
+}
+
+func TestTotalCommandExcludingMissingFiles(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "total", "--exclude-missing", "--source-root="+t.TempDir(), fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "total: 0.00% (0/0 statements in 0 files)\n", stdout)
+}
+
+func TestGroupCommandByCodeOwner(t *testing.T) {
+	codeowners := filepath.Join(t.TempDir(), "CODEOWNERS")
+	require.NoError(t, os.WriteFile(codeowners, []byte("/internal/ @org/backend\n"), 0o600))
+
+	// ACT
+	code, stdout, _ := runCommand(t, "group", "--by=codeowner", "--codeowners="+codeowners, fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "@org/backend\t70.02%\t404/577\n")
+}
+
+func TestGroupCommandFailsForUnknownGroup(t *testing.T) {
+	// ACT
+	code, _, stderr := runCommand(t, "group", "--by=unknown", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, `unknown group "unknown"`)
+}
+
+func TestExportCommand(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "lcov.info")
+
+	for _, format := range []string{"lcov", "cobertura", "codecov", "sonar", "github", "coverage-gutters", "editor-json", "json"} {
+		t.Run(format, func(t *testing.T) {
+			// ACT
+			code, _, stderr := runCommand(t, "export", "--format="+format, "--output="+output, fixture)
+
+			// ASSERT
+			require.Equal(t, exitOK, code, stderr)
+
+			contents, err := os.ReadFile(output)
+			require.NoError(t, err)
+			assert.NotEmpty(t, contents)
+		})
+	}
+
+	code, _, stderr := runCommand(t, "export", "--format=unknown", fixture)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, `unknown export format "unknown"`)
+}
+
+func TestExportCommandWithOptions(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "export", "--format=github", "--option=level=warning", "--option=path-prefix=api", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "::warning file=api/internal/config/config.go,line=19,endLine=20,")
+}
+
+func TestCheckCommand(t *testing.T) {
+	code, stdout, _ := runCommand(t, "check", "--min-total=70", fixture)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "coverage check passed: total 77.38%\n", stdout)
+
+	code, stdout, _ = runCommand(t, "check", "--min-total=80", "--min-package=50", fixture)
+	assert.Equal(t, exitFailed, code)
+	assert.Contains(t, stdout, `total "total": coverage 77.38% is below minimum 80.00%`)
+	assert.Contains(t, stdout, `package "github.cbhq.net/risk/data-tracker-backend/internal/consumer": coverage 42.42% is below minimum 50.00%`)
+
+	code, stdout, _ = runCommand(t, "check", "--max-package-uncovered=50", "--min-package-statements=10", fixture)
+	assert.Equal(t, exitFailed, code)
+	assert.Equal(
+		t,
+		"package \"github.cbhq.net/risk/data-tracker-backend/internal/loan_service\": 54 uncovered statements exceed maximum 50\n",
+		stdout,
+	)
+}
+
+func TestGateCommand(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+
+	code, stdout, _ := runCommand(t, "gate", "--snapshot="+snapshot, "--commit=abc", "--snapshot-branch=main", fixture)
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+
+	// a snapshot is a single line, so it is a valid history store
+	code, stdout, _ = runCommand(t, "gate", "--history="+snapshot, "--max-package-drop=0", fixture)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "Total coverage is 77.38% (+0.00% from `main` at `abc`)")
+
+	code, stdout, _ = runCommand(t, "gate", "--history="+snapshot, "--branch=dev", "--require-baseline", fixture)
+	assert.Equal(t, exitFailed, code)
+	assert.Contains(t, stdout, `no baseline found for branch "dev"`)
+}
+
+func TestRiskCommand(t *testing.T) {
+	root := t.TempDir()
+	source := "package pkg\n\nfunc Check(a int) bool {\n\tif a > 0 {\n\t\treturn true\n\t}\n\n\treturn false\n}\n"
+	profile := "mode: set\ngithub.com/owner/repo/pkg/check.go:3.25,4.11 1 1\ngithub.com/owner/repo/pkg/check.go:4.11,6.3 1 0\n"
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "check.go"), []byte(source), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "coverage.out"), []byte(profile), 0o600))
+
+	// ACT
+	code, stdout, _ := runCommand(t, "risk", "--source-root="+root, filepath.Join(root, "coverage.out"))
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Equal(
+		t,
+		"coverage: 50.00%  risk-adjusted coverage: 50.00%\n"+
+			"github.com/owner/repo/pkg.Check\tpkg/check.go:3-9\tcomplexity 2\t50.00%\trisk 1.00\n",
+		stdout,
+	)
+}
+
+func TestUncoveredCommand(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "uncovered", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "internal/config/config.go: 19-20, 62-63\n")
+}
+
+func TestHolesCommand(t *testing.T) {
+	// ACT
+	code, stdout, _ := runCommand(t, "holes", "--limit=3", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 3)
+	assert.True(t, strings.HasPrefix(stdout, "internal/config/consumer_config.go:56-110\t25 statements\n"))
+
+	code, stdout, _ = runCommand(t, "holes", "--limit=1", "--by=package", fixture)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "github.cbhq.net/risk/data-tracker-backend/internal/consumer\n  internal/consumer/consumer.go:89-116\t15 statements\n")
+}
+
+func TestRunCommand(t *testing.T) {
+	dir := t.TempDir()
+	coverage, err := filepath.Abs(fixture)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gocovparser.yaml"), []byte(
+		"coverage: "+coverage+"\nthresholds: [{group: total, minimum: 80}]\nexports: [{format: lcov, output: lcov.info}]\n",
+	), 0o600))
+
+	// ACT
+	code, stdout, _ := runCommand(t, "run", "--config="+dir)
+
+	// ASSERT
+	assert.Equal(t, exitFailed, code)
+	assert.Contains(t, stdout, `total "total": coverage 77.38% is below minimum 80.00%`)
+	assert.FileExists(t, filepath.Join(dir, "lcov.info"))
+}
+
+func TestTUICommandRequiresTerminal(t *testing.T) {
+	// ACT
+	code, _, stderr := runCommand(t, "tui", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "tui requires an interactive terminal")
+}
+
+func TestBrowserNavigatesTreeAndFile(t *testing.T) {
+	sourceRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceRoot, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(sourceRoot, "pkg", "a.go"),
+		[]byte("package pkg\n\nfunc A() {\n\tb()\n}\n"),
+		0o600,
+	))
+
+	items, err := gocovparser.Parse(
+		"mode: set\n" +
+			"github.com/owner/repo/pkg/a.go:3.10,5.2 1 0\n" +
+			"github.com/owner/repo/pkg/b.go:3.10,5.2 3 1\n",
+	)
+	require.NoError(t, err)
+
+	b := newBrowser(items, sourceRoot)
+
+	// ACT
+	b.handle(keyDown)
+	b.handle(keyDown)
+	b.handle(keyRight)
+	screen := strings.Join(b.render(80, 10), "\n")
+
+	// ASSERT
+	assert.True(t, b.focusFile)
+	assert.Contains(t, screen, "▾ pkg")
+	assert.Contains(t, screen, "github.com/owner/repo/pkg/a.go 0.00% (0/1)")
+	assert.Contains(t, screen, ansiRed+"    4     0x      b()")
+	assert.Contains(t, screen, "total 75.00% (3/4)")
+
+	b.handle(keyLeft)
+	b.handle(keyLeft)
+	assert.False(t, b.focusFile)
+	assert.Len(t, b.rows, 2)
+	assert.True(t, b.handle(keyQuit))
+}
+
+func TestBrowserSortsByLowestCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(
+		"mode: set\n" +
+			"github.com/owner/repo/a.go:3.10,5.2 1 1\n" +
+			"github.com/owner/repo/b.go:3.10,5.2 1 0\n",
+	)
+	require.NoError(t, err)
+
+	b := newBrowser(items, t.TempDir())
+	names := func() []string {
+		result := []string{}
+		for _, row := range b.rows {
+			result = append(result, row.node.Name)
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"github.com/owner/repo", "a.go", "b.go"}, names())
+
+	// ACT
+	b.handle(decodeKey([]byte("s")))
+
+	// ASSERT
+	assert.Equal(t, []string{"github.com/owner/repo", "b.go", "a.go"}, names())
+	assert.Contains(t, strings.Join(b.render(60, 5), "\n"), "sort: coverage")
+}
+
+func TestUnknownCommandAndUsage(t *testing.T) {
+	code, _, stderr := runCommand(t, "unknown")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "Usage: gocovparser")
+
+	code, stdout, _ := runCommand(t, "help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "export")
+
+	code, _, stderr = runCommand(t, "total", "missing.out")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "error:")
+}
+
+func TestBlameCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "pkg", "core.go"), []byte("package pkg\n\nfunc A() {\n}\n"), 0o600))
+
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "initial"}} {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=ada@test"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	profile := filepath.Join(t.TempDir(), "coverage.out")
+	require.NoError(t, os.WriteFile(profile, []byte("mode: set\ngithub.com/a/b/pkg/core.go:3.10,4.2 1 0\n"), 0o600))
+
+	// ACT
+	code, stdout, _ := runCommand(t, "blame", "--repo="+repo, profile)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "uncovered: 2 lines, 1 statements\n0-7 days: 2 lines (100.00%), 1 statements\n")
+	assert.Contains(t, stdout, "8-30 days: 0 lines (0.00%), 0 statements\n")
+	assert.Contains(t, stdout, "older than 365 days: 0 lines (0.00%), 0 statements\n")
+	assert.Contains(t, stdout, "by author:\n  ada@test\t2 lines, 1 statements\n")
+}
+
+func TestExportCommandVerbose(t *testing.T) {
+	// ACT
+	code, _, stderr := runCommand(t, "export", "--verbose", "--format=lcov", fixture)
+
+	// ASSERT
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stderr, `msg="parsed coverage" files=28`)
+	assert.Contains(t, stderr, `msg="exported coverage" format=lcov files=28`)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const defaultRiskLimit = 10
+
+func runRisk(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("risk", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	limit := flags.Int("limit", defaultRiskLimit, "number of functions listed, 0 for all")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, classify.options(false)...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	report, err := gocovparser.GetRiskReport(items, *classify.sourceRoot, *limit)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	fmt.Fprintf(
+		stdout, "coverage: %.2f%%  risk-adjusted coverage: %.2f%%\n",
+		report.Coverage*100, report.RiskAdjustedCoverage*100,
+	)
+
+	for _, fn := range report.Functions {
+		fmt.Fprintf(
+			stdout, "%s\t%s:%s\tcomplexity %d\t%.2f%%\trisk %.2f\n",
+			fn.Function, fn.Path, fn.Lines, fn.Complexity, fn.Coverage*100, fn.Risk,
+		)
+	}
+
+	return exitOK
+}
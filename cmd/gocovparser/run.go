@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/config"
+)
+
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("run", stderr)
+	configPath := flags.String("config", ".", "config file, or directory holding gocovparser.yaml or .gocovrc")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	result, err := config.RunFromConfig(context.Background(), *configPath)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	for _, violation := range result.Violations {
+		fmt.Fprintln(stdout, violation.String())
+	}
+
+	if !result.Passed() {
+		return exitFailed
+	}
+
+	fmt.Fprintf(stdout, "coverage check passed: total %.2f%%\n", result.Groups["total"]["total"]*100)
+
+	return exitOK
+}
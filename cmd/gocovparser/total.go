@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+func runTotal(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("total", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	packages := packagesFlag(flags)
+	classify := classificationFlags(flags)
+	distribution := flags.Bool("distribution", false, "also print the distribution of the per-file coverage")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot, classify.options(false)...)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	items = classify.exclude(selectPackages(items, *packages))
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+
+	fmt.Fprintf(
+		stdout,
+		"total: %.2f%% (%d/%d statements in %d files)\n",
+		breakdown.Coverage*100, breakdown.CoveredStatements, breakdown.Statements, breakdown.Files,
+	)
+
+	if *distribution {
+		printDistribution(stdout, gocovparser.GetCoverageDistribution(items))
+	}
+
+	return exitOK
+}
+
+func printDistribution(w io.Writer, distribution gocovparser.CoverageDistribution) {
+	fmt.Fprintf(
+		w,
+		"files: %d (%d at 0%%, %d at 100%%)\np10: %.2f%%  median: %.2f%%  p90: %.2f%%  mean: %.2f%%\n",
+		distribution.Files, distribution.Uncovered, distribution.FullyCovered,
+		distribution.P10*100, distribution.Median*100, distribution.P90*100, distribution.Mean*100,
+	)
+
+	for _, bucket := range distribution.Buckets {
+		fmt.Fprintf(w, "%3.0f%%-%3.0f%%\t%d\n", bucket.Min*100, bucket.Max*100, bucket.Files)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	escClearScreen = "\x1b[H\x1b[2J"
+	escEnterScreen = "\x1b[?1049h\x1b[?25l"
+	escLeaveScreen = "\x1b[?25h\x1b[?1049l"
+
+	keyBufferSize = 8
+)
+
+var errNotTerminal = errors.New("tui requires an interactive terminal")
+
+func runTUI(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("tui", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+	sourceRoot := flags.String("source-root", "", "directory the coverage paths are relative to (defaults to --module-root or .)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	out, isFile := stdout.(*os.File)
+	if !isFile || !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return fail(stderr, errNotTerminal)
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	root := *sourceRoot
+	if root == "" {
+		root = *moduleRoot
+	}
+
+	if root == "" {
+		root = "."
+	}
+
+	if err := browse(newBrowser(items, root), os.Stdin, out); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// browse runs the browser in the alternate screen of the terminal until it quits.
+func browse(b *browser, in, out *os.File) error {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(int(in.Fd()), state) }()
+
+	fmt.Fprint(out, escEnterScreen)
+	defer fmt.Fprint(out, escLeaveScreen)
+
+	buffer := make([]byte, keyBufferSize)
+
+	for {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to read the terminal size: %w", err)
+		}
+
+		fmt.Fprint(out, escClearScreen+strings.Join(b.render(width, height), "\r\n"))
+
+		count, err := in.Read(buffer)
+		if err != nil {
+			return fmt.Errorf("failed to read the terminal input: %w", err)
+		}
+
+		if b.handle(decodeKey(buffer[:count])) {
+			return nil
+		}
+	}
+}
+
+// decodeKey maps the bytes of a key press, including arrow escape sequences, to a browser key.
+func decodeKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "k":
+		return keyUp
+	case "\x1b[B", "j":
+		return keyDown
+	case "\x1b[C", "l", "\r":
+		return keyRight
+	case "\x1b[D", "h":
+		return keyLeft
+	case "\x1b[5~":
+		return keyPageUp
+	case "\x1b[6~", " ":
+		return keyPageDown
+	case "\t":
+		return keyTab
+	case "s":
+		return keySort
+	case "q", "\x03", "\x1b":
+		return keyQuit
+	default:
+		return keyNone
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+func runUncovered(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("uncovered", stderr)
+	moduleRoot := flags.String("module-root", "", "directory of the go.mod the coverage paths are relative to")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	items, err := parseCoverage(flags, *moduleRoot)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ranges := gocovparser.GetUncoveredRanges(items)
+
+	for _, breakdown := range gocovparser.GetFileBreakdowns(items) {
+		if missing, found := ranges[breakdown.FileName]; found {
+			fmt.Fprintf(stdout, "%s: %s\n", breakdown.Path, gocovparser.FormatLineRanges(missing))
+		}
+	}
+
+	return exitOK
+}
@@ -0,0 +1,82 @@
+package gocovparser
+
+import (
+	"path"
+	"strings"
+)
+
+// GetAPIBreakdown splits the coverage of each package between its exported API and its unexported functions,
+// reading the source files from their SourcePath under sourceRoot.
+// Exported functions and methods of exported types are part of the API, except in main packages and in packages
+// under an internal directory, which can't be imported by other modules. Statements outside of functions are ignored.
+func GetAPIBreakdown(items []Coverage, sourceRoot string, opts ...PercentOption) (APIBreakdown, error) {
+	options := newPercentOptions(opts)
+	result := APIBreakdown{
+		Packages: make(map[string]APIDetail),
+	}
+
+	for _, cov := range items {
+		funcs, err := findFuncs(SourcePath(cov, sourceRoot))
+		if err != nil {
+			return APIBreakdown{}, err
+		}
+
+		pkg := path.Dir(cov.FileName)
+		internal := isInternalPackage(pkg)
+		detail := result.Packages[pkg]
+
+		for _, b := range cov.Blocks {
+			for _, fn := range funcs {
+				if !fn.contains(b) {
+					continue
+				}
+
+				if fn.exported && !internal {
+					detail.Exported = addBlock(detail.Exported, b.NumStmt, b.Count)
+				} else {
+					detail.Unexported = addBlock(detail.Unexported, b.NumStmt, b.Count)
+				}
+
+				break
+			}
+		}
+
+		result.Packages[pkg] = detail
+	}
+
+	for pkg, detail := range result.Packages {
+		result.Total.Exported = addDetail(result.Total.Exported, detail.Exported)
+		result.Total.Unexported = addDetail(result.Total.Unexported, detail.Unexported)
+
+		detail.Exported.Percent = options.percentOf(detail.Exported.Covered, detail.Exported.Total)
+		detail.Unexported.Percent = options.percentOf(detail.Unexported.Covered, detail.Unexported.Total)
+		result.Packages[pkg] = detail
+	}
+
+	result.Total.Exported.Percent = options.percentOf(result.Total.Exported.Covered, result.Total.Exported.Total)
+	result.Total.Unexported.Percent = options.percentOf(result.Total.Unexported.Covered, result.Total.Unexported.Total)
+
+	return result, nil
+}
+
+func isInternalPackage(pkg string) bool {
+	return strings.HasPrefix(pkg, "internal/") || strings.Contains(pkg, "/internal/") ||
+		pkg == "internal" || strings.HasSuffix(pkg, "/internal")
+}
+
+func addBlock(detail GroupDetail, statements, count int) GroupDetail {
+	detail.Total += statements
+
+	if count > 0 { // is covered
+		detail.Covered += statements
+	}
+
+	return detail
+}
+
+func addDetail(detail, other GroupDetail) GroupDetail {
+	detail.Covered += other.Covered
+	detail.Total += other.Total
+
+	return detail
+}
@@ -0,0 +1,84 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const apiSource = `package pkg
+
+type Thing struct{}
+
+type hidden struct{}
+
+func Exported() {
+	println("exported")
+}
+
+func unexported() {
+	println("unexported")
+}
+
+func (t *Thing) Method() {
+	println("method")
+}
+
+func (h hidden) Method() {
+	println("hidden")
+}
+`
+
+func TestGetAPIBreakdown(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{"pkg", "internal/pkg"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "thing.go"), []byte(apiSource), 0o600))
+	}
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/owner/repo/pkg/thing.go:7.17,9.2 1 1
+github.com/owner/repo/pkg/thing.go:11.19,13.2 1 0
+github.com/owner/repo/pkg/thing.go:15.26,17.2 2 0
+github.com/owner/repo/pkg/thing.go:19.25,21.2 1 1
+github.com/owner/repo/internal/pkg/thing.go:7.17,9.2 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GetAPIBreakdown(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got.Packages, 2)
+
+	pkg := got.Packages["github.com/owner/repo/pkg"]
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 1, Total: 3, Percent: 1.0 / 3}, pkg.Exported)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 1, Total: 2, Percent: 0.5}, pkg.Unexported)
+
+	internal := got.Packages["github.com/owner/repo/internal/pkg"]
+	assert.Equal(t, gocovparser.GroupDetail{}, internal.Exported)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 1, Total: 1, Percent: 1}, internal.Unexported)
+
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 1, Total: 3, Percent: 1.0 / 3}, got.Total.Exported)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 2, Total: 3, Percent: 2.0 / 3}, got.Total.Unexported)
+}
+
+func TestGetAPIBreakdownFailsIfSourceIsMissing(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	_, err = gocovparser.GetAPIBreakdown(items, t.TempDir())
+
+	// ASSERT
+	require.Error(t, err)
+}
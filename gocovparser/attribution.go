@@ -0,0 +1,342 @@
+package gocovparser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// testProfileExt is the extension of the per-test coverage profiles indexed by BuildTestIndex.
+const testProfileExt = ".out"
+
+// TestIndex is a reverse index of the source lines executed by each test, built from per-test coverage profiles
+// (e.g. `go test -run '^TestName$' -coverprofile=TestName.out`). Files are looked up by coverage FileName or Path.
+// Tests are labeled as TestCase.String does, so tests of the same name in different packages are told apart.
+type TestIndex struct {
+	tests map[string]bool
+
+	// lines maps file names to the tests executing each line.
+	lines map[string]map[int]map[string]bool
+
+	// fileNames maps coverage paths to file names.
+	fileNames map[string]string
+}
+
+// NewTestIndex returns an empty test index.
+func NewTestIndex() *TestIndex {
+	return &TestIndex{
+		tests:     map[string]bool{},
+		lines:     map[string]map[int]map[string]bool{},
+		fileNames: map[string]string{},
+	}
+}
+
+// BuildTestIndex indexes every `*.out` coverage profile under dir, named after its test and stored in the directory
+// of its package import path (e.g. `dir/github.com/owner/repo/pkg/TestA.out`). Tests are labeled by package and
+// name, see TestCase; profiles directly in dir are labeled by name only.
+func BuildTestIndex(dir string, opts ...ParseOption) (*TestIndex, error) {
+	index := NewTestIndex()
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != testProfileExt {
+			return err
+		}
+
+		pkg, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve the package of %q", path)
+		}
+
+		if pkg == "." {
+			pkg = ""
+		}
+
+		test := TestCase{Package: filepath.ToSlash(pkg), Name: strings.TrimSuffix(entry.Name(), testProfileExt)}
+
+		return index.addProfile(test, path, opts)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to index coverage profiles in %q", dir)
+	}
+
+	return index, nil
+}
+
+// BuildTestIndexFromEvents indexes the top level tests reported by `go test -json` in r (see ParseTestEvents),
+// labeled by package and name, with their coverage profiles stored under dir as BuildTestIndex expects them.
+// Tests without a profile are indexed as executing no lines.
+func BuildTestIndexFromEvents(r io.Reader, dir string, opts ...ParseOption) (*TestIndex, error) {
+	tests, err := ParseTestEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	index := NewTestIndex()
+
+	for _, test := range tests {
+		path := filepath.Join(dir, filepath.FromSlash(test.Package), test.Name+testProfileExt)
+
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			index.Add(test.String(), nil)
+
+			continue
+		}
+
+		if err := index.addProfile(test, path, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+// addProfile indexes the lines executed by test in the coverage profile at path.
+func (idx *TestIndex) addProfile(test TestCase, path string, opts []ParseOption) error {
+	items, err := ParseFile(path, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse coverage profile %q", path)
+	}
+
+	idx.Add(test.String(), items)
+
+	return nil
+}
+
+// Add indexes the lines executed by test in its coverage profile.
+func (idx *TestIndex) Add(test string, items []Coverage) {
+	idx.tests[test] = true
+
+	for _, cov := range items {
+		idx.fileNames[cov.Path] = cov.FileName
+
+		lines, found := idx.lines[cov.FileName]
+		if !found {
+			lines = map[int]map[string]bool{}
+			idx.lines[cov.FileName] = lines
+		}
+
+		for _, b := range cov.Blocks {
+			if b.Count == 0 || b.NumStmt == 0 {
+				continue
+			}
+
+			for line := b.StartLine; line <= b.EndLine; line++ {
+				if lines[line] == nil {
+					lines[line] = map[string]bool{}
+				}
+
+				lines[line][test] = true
+			}
+		}
+	}
+}
+
+// Tests returns the sorted names of the indexed tests.
+func (idx *TestIndex) Tests() []string {
+	return sortedKeys(idx.tests)
+}
+
+// TestsForFile returns the sorted names of the tests executing any line of the file.
+func (idx *TestIndex) TestsForFile(file string) []string {
+	tests := map[string]bool{}
+
+	for _, lineTests := range idx.fileLines(file) {
+		for test := range lineTests {
+			tests[test] = true
+		}
+	}
+
+	return sortedKeys(tests)
+}
+
+// TestsForLine returns the sorted names of the tests executing the line of the file.
+func (idx *TestIndex) TestsForLine(file string, line int) []string {
+	return sortedKeys(idx.fileLines(file)[line])
+}
+
+// LinesForFile returns the sorted lines of the file executed by any test.
+func (idx *TestIndex) LinesForFile(file string) []int {
+	lines := idx.fileLines(file)
+
+	result := make([]int, 0, len(lines))
+	for line := range lines {
+		result = append(result, line)
+	}
+
+	sort.Ints(result)
+
+	return result
+}
+
+// TestsForChanges returns the sorted names of the tests executing any of the changed lines, as returned by
+// ChangedLines or ParseUnifiedDiff. These are the tests to run to check a change.
+func (idx *TestIndex) TestsForChanges(changes map[string][]LineRange) []string {
+	tests := map[string]bool{}
+
+	for file, ranges := range changes {
+		lines := idx.fileLines(file)
+
+		for _, r := range ranges {
+			for line := r.Start; line <= r.End; line++ {
+				for test := range lines[line] {
+					tests[test] = true
+				}
+			}
+		}
+	}
+
+	return sortedKeys(tests)
+}
+
+// CoverageIndex answers which tests execute a line of a file, e.g. a TestIndex.
+type CoverageIndex interface {
+	// LinesForFile returns the lines of the file executed by any test.
+	LinesForFile(file string) []int
+
+	// TestsForLine returns the names of the tests executing the line of the file.
+	TestsForLine(file string, line int) []string
+}
+
+var _ CoverageIndex = (*TestIndex)(nil)
+
+// RecommendTests returns the sorted names of a small set of tests that together execute every indexed line of the
+// changed files, for CI pipelines that only run the tests affected by a change. Files are coverage FileNames or Paths.
+// Use RecommendTestsForChanges to only cover the changed lines.
+func RecommendTests(changedFiles []string, index CoverageIndex) []string {
+	changes := make(map[string][]LineRange, len(changedFiles))
+
+	for _, file := range changedFiles {
+		for _, line := range index.LinesForFile(file) {
+			changes[file] = append(changes[file], LineRange{Start: line, End: line})
+		}
+	}
+
+	return RecommendTestsForChanges(changes, index)
+}
+
+// RecommendTestsForChanges returns the sorted names of a small set of tests that together execute every changed
+// line, as returned by ChangedLines or ParseUnifiedDiff. Changed lines no test executes are ignored. The set is
+// chosen greedily, picking the test executing the most changed lines not executed by the tests already picked,
+// which approximates the minimal set.
+func RecommendTestsForChanges(changes map[string][]LineRange, index CoverageIndex) []string {
+	remaining := map[string]map[string]bool{} // test -> "file:line" it executes
+
+	for file, ranges := range changes {
+		for _, r := range ranges {
+			for line := r.Start; line <= r.End; line++ {
+				key := fmt.Sprintf("%s:%d", file, line)
+
+				for _, test := range index.TestsForLine(file, line) {
+					if remaining[test] == nil {
+						remaining[test] = map[string]bool{}
+					}
+
+					remaining[test][key] = true
+				}
+			}
+		}
+	}
+
+	picked := map[string]bool{}
+
+	for {
+		best := ""
+
+		for _, test := range sortedKeys(setOf(remaining)) {
+			if len(remaining[test]) > len(remaining[best]) {
+				best = test
+			}
+		}
+
+		if best == "" {
+			return sortedKeys(picked)
+		}
+
+		picked[best] = true
+
+		for line := range remaining[best] {
+			for _, lines := range remaining {
+				delete(lines, line)
+			}
+		}
+	}
+}
+
+func setOf(values map[string]map[string]bool) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for key := range values {
+		set[key] = true
+	}
+
+	return set
+}
+
+func (idx *TestIndex) fileLines(file string) map[int]map[string]bool {
+	if lines, found := idx.lines[file]; found {
+		return lines
+	}
+
+	return idx.lines[idx.fileNames[file]]
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// testEvent is an event of the `go test -json` output.
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+}
+
+// ParseTestEvents reads the output of `go test -json` and returns the top level tests that passed or failed,
+// in the order they finished. Lines that are not JSON objects (e.g. build output) are ignored.
+func ParseTestEvents(r io.Reader) ([]TestCase, error) {
+	tests := []TestCase{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		event := testEvent{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse test event on line %d", lineNumber)
+		}
+
+		if event.Test == "" || strings.Contains(event.Test, "/") || (event.Action != "pass" && event.Action != "fail") {
+			continue
+		}
+
+		tests = append(tests, TestCase{Package: event.Package, Name: event.Test})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read test events")
+	}
+
+	return tests, nil
+}
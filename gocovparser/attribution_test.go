@@ -0,0 +1,200 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testProfileA = `mode: set
+github.com/owner/repo/pkg/a.go:3.10,5.2 1 1
+github.com/owner/repo/pkg/a.go:7.10,9.2 1 0
+`
+	testProfileB = `mode: set
+github.com/owner/repo/pkg/a.go:3.10,5.2 1 1
+github.com/owner/repo/pkg/a.go:7.10,9.2 1 1
+github.com/owner/repo/pkg/b.go:1.10,2.2 1 0
+`
+)
+
+func TestTestIndexAnswersWhichTestsCover(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TestA.out"), []byte(testProfileA), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TestB.out"), []byte(testProfileB), 0o600))
+
+	// ACT
+	index, err := gocovparser.BuildTestIndex(dir)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestA", "TestB"}, index.Tests())
+	assert.Equal(t, []string{"TestA", "TestB"}, index.TestsForFile("github.com/owner/repo/pkg/a.go"))
+	assert.Equal(t, []string{"TestA", "TestB"}, index.TestsForLine("pkg/a.go", 4))
+	assert.Equal(t, []string{"TestB"}, index.TestsForLine("github.com/owner/repo/pkg/a.go", 8))
+	assert.Empty(t, index.TestsForLine("github.com/owner/repo/pkg/a.go", 6))
+	assert.Empty(t, index.TestsForFile("pkg/b.go"))
+	assert.Empty(t, index.TestsForFile("pkg/missing.go"))
+
+	changes := map[string][]gocovparser.LineRange{"pkg/a.go": {{Start: 6, End: 7}}}
+	assert.Equal(t, []string{"TestB"}, index.TestsForChanges(changes))
+}
+
+func TestBuildTestIndexFailsForInvalidProfile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TestA.out"), []byte("invalid"), 0o600))
+
+	// ACT
+	_, err := gocovparser.BuildTestIndex(dir)
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
+
+func TestBuildTestIndexLabelsTestsByPackage(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, pkg := range []string{"github.com/owner/repo/pkg", "github.com/owner/repo/other"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.FromSlash(pkg)), 0o755))
+	}
+
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "github.com", "owner", "repo", "pkg", "TestA.out"), []byte(testProfileA), 0o600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "github.com", "owner", "repo", "other", "TestA.out"), []byte(testProfileB), 0o600,
+	))
+
+	// ACT
+	index, err := gocovparser.BuildTestIndex(dir)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com/owner/repo/other.TestA", "github.com/owner/repo/pkg.TestA"}, index.Tests())
+	assert.Equal(t, []string{"github.com/owner/repo/other.TestA"}, index.TestsForLine("pkg/a.go", 8))
+}
+
+func TestBuildTestIndexFromEvents(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "github.com", "owner", "repo", "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "github.com", "owner", "repo", "pkg", "TestB.out"), []byte(testProfileB), 0o600,
+	))
+
+	events := `{"Action":"pass","Package":"github.com/owner/repo/pkg","Test":"TestA"}
+{"Action":"pass","Package":"github.com/owner/repo/pkg","Test":"TestB"}
+`
+
+	// ACT
+	index, err := gocovparser.BuildTestIndexFromEvents(strings.NewReader(events), dir)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com/owner/repo/pkg.TestA", "github.com/owner/repo/pkg.TestB"}, index.Tests())
+	assert.Equal(t, []string{"github.com/owner/repo/pkg.TestB"}, index.TestsForFile("pkg/a.go"))
+}
+
+func TestParseTestEvents(t *testing.T) {
+	output := `# github.com/owner/repo/pkg
+{"Action":"run","Package":"github.com/owner/repo/pkg","Test":"TestA"}
+{"Action":"pass","Package":"github.com/owner/repo/pkg","Test":"TestA/sub"}
+{"Action":"pass","Package":"github.com/owner/repo/pkg","Test":"TestA"}
+{"Action":"skip","Package":"github.com/owner/repo/pkg","Test":"TestSkipped"}
+{"Action":"fail","Package":"github.com/owner/repo/pkg","Test":"TestB"}
+{"Action":"pass","Package":"github.com/owner/repo/pkg"}
+`
+
+	// ACT
+	got, err := gocovparser.ParseTestEvents(strings.NewReader(output))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []gocovparser.TestCase{
+		{Package: "github.com/owner/repo/pkg", Name: "TestA"},
+		{Package: "github.com/owner/repo/pkg", Name: "TestB"},
+	}, got)
+
+	_, err = gocovparser.ParseTestEvents(strings.NewReader("{invalid\n"))
+	assert.Error(t, err)
+}
+
+func TestParseTestEventsReadsLongOutput(t *testing.T) {
+	output := `{"Action":"output","Package":"github.com/owner/repo/pkg","Test":"TestA","Output":"` +
+		strings.Repeat("x", 100000) + `\n"}
+{"Action":"pass","Package":"github.com/owner/repo/pkg","Test":"TestA"}
+`
+
+	// ACT
+	got, err := gocovparser.ParseTestEvents(strings.NewReader(output))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []gocovparser.TestCase{{Package: "github.com/owner/repo/pkg", Name: "TestA"}}, got)
+}
+
+func recommendationIndex(t *testing.T) *gocovparser.TestIndex {
+	t.Helper()
+
+	index := gocovparser.NewTestIndex()
+
+	for test, profile := range map[string]string{
+		"TestA": testProfileA,
+		"TestB": testProfileB,
+		"TestC": "mode: set\ngithub.com/owner/repo/pkg/a.go:3.10,5.2 1 1\ngithub.com/owner/repo/pkg/c.go:1.10,2.2 1 1\n",
+	} {
+		items, err := gocovparser.Parse(profile)
+		require.NoError(t, err)
+
+		index.Add(test, items)
+	}
+
+	return index
+}
+
+func TestRecommendTestsCoversChangedFiles(t *testing.T) {
+	index := recommendationIndex(t)
+
+	// ACT
+	single := gocovparser.RecommendTests([]string{"github.com/owner/repo/pkg/a.go"}, index)
+	several := gocovparser.RecommendTests([]string{"pkg/a.go", "pkg/c.go"}, index)
+	none := gocovparser.RecommendTests([]string{"pkg/b.go", "pkg/missing.go"}, index)
+
+	// ASSERT
+	assert.Equal(t, []string{"TestB"}, single)
+	assert.Equal(t, []string{"TestB", "TestC"}, several)
+	assert.Empty(t, none)
+}
+
+func TestRecommendTestsForChangesPicksSmallCoveringSet(t *testing.T) {
+	index := recommendationIndex(t)
+
+	// ACT
+	single := gocovparser.RecommendTestsForChanges(
+		map[string][]gocovparser.LineRange{"github.com/owner/repo/pkg/a.go": {{Start: 1, End: 10}}}, index,
+	)
+	shared := gocovparser.RecommendTestsForChanges(
+		map[string][]gocovparser.LineRange{"pkg/a.go": {{Start: 3, End: 4}}}, index,
+	)
+	several := gocovparser.RecommendTestsForChanges(map[string][]gocovparser.LineRange{
+		"pkg/a.go": {{Start: 8, End: 8}},
+		"pkg/c.go": {{Start: 1, End: 1}},
+	}, index)
+	none := gocovparser.RecommendTestsForChanges(map[string][]gocovparser.LineRange{
+		"pkg/a.go":       {{Start: 6, End: 6}},
+		"pkg/b.go":       {{Start: 1, End: 2}},
+		"pkg/missing.go": {{Start: 1, End: 2}},
+	}, index)
+
+	// ASSERT
+	assert.Equal(t, []string{"TestB"}, single)
+	assert.Equal(t, []string{"TestA"}, shared)
+	assert.Equal(t, []string{"TestB", "TestC"}, several)
+	assert.Empty(t, none)
+}
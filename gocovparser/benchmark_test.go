@@ -0,0 +1,117 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+)
+
+// benchmarkFiles and benchmarkBlocks make the 100k blocks profile of the documented performance targets.
+const (
+	benchmarkFiles  = 5000
+	benchmarkBlocks = 20
+)
+
+func largeProfile(files, blocks int) string {
+	var builder strings.Builder
+
+	builder.WriteString("mode: count\n")
+
+	for f := 0; f < files; f++ {
+		for b := 0; b < blocks; b++ {
+			fmt.Fprintf(&builder, "github.com/org/repo/pkg%d/file%d.go:%d.2,%d.10 2 %d\n", f%100, f, b*2+1, b*2+2, b%3)
+		}
+	}
+
+	return builder.String()
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := largeProfile(benchmarkFiles, benchmarkBlocks)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = gocovparser.Parse(data)
+	}
+}
+
+func BenchmarkGroupCoverageByPackage(b *testing.B) {
+	items := largeCoverage(benchmarkFiles, benchmarkBlocks)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = gocovparser.GroupCoverage(items, gocovparser.ByPackage(), gocovparser.ByFile(), gocovparser.TotalParseGroup)
+	}
+}
+
+func BenchmarkGroupCoverageDetailed(b *testing.B) {
+	items := largeCoverage(benchmarkFiles, benchmarkBlocks)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = gocovparser.GroupCoverageDetailed(
+			items, []gocovparser.ParseGroup{gocovparser.ByPackage(), gocovparser.TotalParseGroup},
+		)
+	}
+}
+
+func BenchmarkGetTotalCoverageBreakdown(b *testing.B) {
+	items := largeCoverage(benchmarkFiles, benchmarkBlocks)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = gocovparser.GetTotalCoverageBreakdown(items)
+	}
+}
+
+func BenchmarkGetLineCoverage(b *testing.B) {
+	items := largeCoverage(benchmarkFiles, benchmarkBlocks)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = gocovparser.GetLineCoverage(items)
+	}
+}
+
+// maxAllocsPerItem is the documented allocation target of parsing and grouping, per block and per file.
+const maxAllocsPerItem = 3
+
+func TestParseAllocationsPerBlock(t *testing.T) {
+	data := largeProfile(100, benchmarkBlocks)
+
+	// ACT
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = gocovparser.Parse(data)
+	})
+
+	// ASSERT
+	assert.Less(t, allocs/float64(100*benchmarkBlocks), float64(maxAllocsPerItem))
+}
+
+func TestGroupCoverageAllocationsPerFile(t *testing.T) {
+	items := largeCoverage(100, benchmarkBlocks)
+
+	// ACT
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = gocovparser.GroupCoverage(items, gocovparser.ByPackage(), gocovparser.ByFile(), gocovparser.TotalParseGroup)
+	})
+
+	// ASSERT
+	assert.Less(t, allocs/float64(len(items)), float64(maxAllocsPerItem))
+}
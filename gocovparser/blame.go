@@ -0,0 +1,296 @@
+package gocovparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	blameHeaderFields  = 3
+	blameFinalPosition = 2
+	sha1HexLength      = 40
+	sha256HexLength    = 64
+
+	day     = 24 * time.Hour
+	week    = 7 * day
+	month   = 30 * day
+	quarter = 90 * day
+	year    = 365 * day
+)
+
+// DefaultBlameAges are the age buckets of AttributeUncovered: a week, 30 days, 90 days and a year.
+var DefaultBlameAges = []time.Duration{week, month, quarter, year}
+
+// BlameOption configures AttributeUncovered.
+type BlameOption func(*blameOptions)
+
+type blameOptions struct {
+	now  time.Time
+	ages []time.Duration
+}
+
+// WithBlameAges sets the age buckets of the attribution. Defaults to DefaultBlameAges.
+func WithBlameAges(ages ...time.Duration) BlameOption {
+	return func(opts *blameOptions) {
+		opts.ages = ages
+	}
+}
+
+// WithBlameTime sets the time ages are computed from. Defaults to the current time.
+func WithBlameTime(now time.Time) BlameOption {
+	return func(opts *blameOptions) {
+		opts.now = now
+	}
+}
+
+// AttributeUncovered runs `git blame` on the files of the coverage found in repoPath (by LocalPath if set, by Path
+// otherwise) and attributes their uncovered lines to the commits that last changed them. The statements of an
+// uncovered block are attributed to the most recent commit changing any of its lines. Files missing from repoPath
+// are ignored.
+func AttributeUncovered(items []Coverage, repoPath string, opts ...BlameOption) (BlameAttribution, error) {
+	options := blameOptions{
+		now:  time.Now(),
+		ages: DefaultBlameAges,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ages := append([]time.Duration(nil), options.ages...)
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+
+	result := BlameAttribution{
+		ByAuthor: map[string]BlameCount{},
+		ByCommit: map[string]BlameCount{},
+		ByAge:    make([]BlameAge, len(ages)+1),
+	}
+
+	for index, age := range ages {
+		result.ByAge[index].MaxAge = age
+	}
+
+	attribute := func(line BlameLine, lines, statements int) {
+		author := line.AuthorMail
+		if author == "" {
+			author = line.Author
+		}
+
+		result.Total.Lines += lines
+		result.Total.Statements += statements
+		result.ByAuthor[author] = result.ByAuthor[author].add(lines, statements)
+		result.ByCommit[line.Commit] = result.ByCommit[line.Commit].add(lines, statements)
+
+		bucket := &result.ByAge[len(ages)]
+
+		for index, age := range ages {
+			if options.now.Sub(line.AuthorTime) <= age {
+				bucket = &result.ByAge[index]
+
+				break
+			}
+		}
+
+		bucket.BlameCount = bucket.BlameCount.add(lines, statements)
+	}
+
+	lineCoverage := GetLineCoverage(items)
+	blamed := map[string]map[int]BlameLine{}
+
+	for _, cov := range SortCoverage(items) {
+		path, err := blamePath(cov, repoPath)
+		if err != nil {
+			return BlameAttribution{}, err
+		}
+
+		if _, err := os.Stat(filepath.Join(repoPath, path)); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		blame, found := blamed[cov.FileName]
+		if !found {
+			blame, err = BlameFile(repoPath, path)
+			if err != nil {
+				return BlameAttribution{}, err
+			}
+
+			blamed[cov.FileName] = blame
+
+			// uncovered lines are attributed once per file, blocks are attributed per item below
+			for number, line := range lineCoverage[cov.FileName] {
+				if commit, found := blame[number]; found && line.Status == LineUncovered {
+					attribute(commit, 1, 0)
+				}
+			}
+		}
+
+		for _, block := range cov.Blocks {
+			if block.Count > 0 || block.NumStmt == 0 {
+				continue
+			}
+
+			if commit, found := latestCommit(blame, block.StartLine, block.EndLine); found {
+				attribute(commit, 0, block.NumStmt)
+			}
+		}
+	}
+
+	for index := range result.ByAge {
+		result.ByAge[index].Percent = percentOf(result.ByAge[index].Lines, result.Total.Lines)
+	}
+
+	return result, nil
+}
+
+// blamePath returns the path of the coverage file relative to repoPath, as `git -C repoPath blame` resolves it.
+func blamePath(cov Coverage, repoPath string) (string, error) {
+	if cov.LocalPath == "" {
+		return filepath.FromSlash(cov.Path), nil
+	}
+
+	repo, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve repository %q", repoPath)
+	}
+
+	local, err := filepath.Abs(cov.LocalPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve %q", cov.LocalPath)
+	}
+
+	path, err := filepath.Rel(repo, local)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve %q in repository %q", cov.LocalPath, repoPath)
+	}
+
+	return path, nil
+}
+
+func (c BlameCount) add(lines, statements int) BlameCount {
+	return BlameCount{Lines: c.Lines + lines, Statements: c.Statements + statements}
+}
+
+// latestCommit returns the most recent commit changing any of the lines.
+func latestCommit(blame map[int]BlameLine, start, end int) (BlameLine, bool) {
+	latest, found := BlameLine{}, false
+
+	for number := start; number <= end; number++ {
+		line, ok := blame[number]
+		if ok && (!found || line.AuthorTime.After(latest.AuthorTime)) {
+			latest, found = line, true
+		}
+	}
+
+	return latest, found
+}
+
+// BlameFile runs `git blame --porcelain` on the file at path, relative to repoPath, and returns the commit of each
+// line by number.
+func BlameFile(repoPath, path string) (map[int]BlameLine, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", "-C", repoPath, "blame", "--porcelain", "--", path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run git blame on %q: %s", path, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseBlamePorcelain(&stdout)
+}
+
+// ParseBlamePorcelain reads the output of `git blame --porcelain` and returns the commit of each line by number.
+func ParseBlamePorcelain(r io.Reader) (map[int]BlameLine, error) {
+	result := map[int]BlameLine{}
+	commits := map[string]*BlameLine{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+
+	var (
+		current *BlameLine
+		final   int
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\t") {
+			if current == nil {
+				return nil, errors.Wrapf(ErrInvalidBlame, "line %d without commit", final)
+			}
+
+			result[final] = *current
+
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) >= blameHeaderFields && isCommitHash(fields[0]) {
+			number, err := strconv.Atoi(fields[blameFinalPosition])
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidBlame, "invalid header %q", line)
+			}
+
+			commit, found := commits[fields[0]]
+			if !found {
+				commit = &BlameLine{Commit: fields[0]}
+				commits[fields[0]] = commit
+			}
+
+			current, final = commit, number
+
+			continue
+		}
+
+		if current == nil {
+			return nil, errors.Wrapf(ErrInvalidBlame, "%q before any commit", line)
+		}
+
+		// other commit details, e.g. committer or summary, are ignored
+		key, value, _ := strings.Cut(line, " ")
+
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorMail = strings.Trim(value, "<>")
+		case "author-time":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidBlame, "invalid author time %q", value)
+			}
+
+			current.AuthorTime = time.Unix(seconds, 0)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidBlame, err.Error())
+	}
+
+	return result, nil
+}
+
+func isCommitHash(value string) bool {
+	if len(value) != sha1HexLength && len(value) != sha256HexLength {
+		return false
+	}
+
+	for _, char := range value {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+
+	return true
+}
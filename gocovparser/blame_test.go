@@ -0,0 +1,153 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const blamePorcelain = `1111111111111111111111111111111111111111 1 1 2
+author Ada
+author-mail <ada@example.com>
+author-time 1700000000
+author-tz +0000
+committer Ada
+summary initial
+filename pkg/core.go
+	package pkg
+1111111111111111111111111111111111111111 2 2
+	
+2222222222222222222222222222222222222222 3 3 1
+author Bob
+author-mail <bob@example.com>
+author-time 1710000000
+previous 1111111111111111111111111111111111111111 pkg/core.go
+filename pkg/core.go
+	func A() {}
+`
+
+func TestCanParseBlamePorcelain(t *testing.T) {
+	// ACT
+	got, err := gocovparser.ParseBlamePorcelain(strings.NewReader(blamePorcelain))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "ada@example.com", got[2].AuthorMail)
+	assert.Equal(t, "Bob", got[3].Author)
+	assert.Equal(t, "2222222222222222222222222222222222222222", got[3].Commit)
+	assert.Equal(t, time.Unix(1710000000, 0), got[3].AuthorTime)
+}
+
+func TestParseBlamePorcelainFailsForInvalidOutput(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseBlamePorcelain(strings.NewReader("author Ada\n\tpackage pkg\n"))
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidBlame)
+}
+
+// blameRepo creates a git repository with pkg/core.go, whose first function was committed in 2020 by "old" and
+// its second one in 2026 by "new".
+func blameRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	git := func(author, date string, args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(
+			os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL="+author+"@test", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test", "GIT_COMMITTER_DATE="+date,
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	file := filepath.Join(repo, "pkg", "core.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("package pkg\n\nfunc A() {\n\tprintln()\n}\n"), 0o600))
+
+	git("old", "2020-01-01T00:00:00Z", "init", "-q")
+	git("old", "2020-01-01T00:00:00Z", "add", "-A")
+	git("old", "2020-01-01T00:00:00Z", "commit", "-q", "-m", "old")
+
+	require.NoError(t, os.WriteFile(
+		file, []byte("package pkg\n\nfunc A() {\n\tprintln()\n}\n\nfunc B() {\n\tprintln()\n\tprintln()\n}\n"), 0o600,
+	))
+	git("new", "2026-10-10T00:00:00Z", "add", "-A")
+	git("new", "2026-10-10T00:00:00Z", "commit", "-q", "-m", "new")
+
+	return repo
+}
+
+func TestCanAttributeUncoveredCode(t *testing.T) {
+	repo := blameRepo(t)
+
+	items, err := gocovparser.Parse(`mode: set
+github.com/heynemann/go-cov-parser/pkg/core.go:3.10,5.2 1 0
+github.com/heynemann/go-cov-parser/pkg/core.go:7.10,10.2 2 0
+github.com/heynemann/go-cov-parser/pkg/missing.go:1.1,2.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.AttributeUncovered(
+		items, repo, gocovparser.WithBlameTime(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.BlameCount{Lines: 7, Statements: 3}, got.Total)
+	assert.Equal(t, gocovparser.BlameCount{Lines: 4, Statements: 2}, got.ByAuthor["new@test"])
+	assert.Equal(t, gocovparser.BlameCount{Lines: 3, Statements: 1}, got.ByAuthor["old@test"])
+	require.Len(t, got.ByAge, 5)
+	assert.Equal(t, 4, got.ByAge[0].Lines)
+	assert.InDelta(t, 4.0/7, got.ByAge[0].Percent, 0.0001)
+	assert.Equal(t, time.Duration(0), got.ByAge[4].MaxAge)
+	assert.Equal(t, 3, got.ByAge[4].Lines)
+	assert.Len(t, got.ByCommit, 2)
+}
+
+func TestAttributeUncoveredResolvesPathsOfRelativeRepositories(t *testing.T) {
+	repo := blameRepo(t)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	relative, err := filepath.Rel(wd, repo)
+	require.NoError(t, err)
+
+	items, err := gocovparser.Parse(`mode: set
+github.com/heynemann/go-cov-parser/pkg/core.go:3.10,5.2 1 0
+github.com/heynemann/go-cov-parser/pkg/other.go:7.10,10.2 2 0
+`)
+	require.NoError(t, err)
+
+	items[1].LocalPath = filepath.Join(relative, "pkg", "core.go")
+
+	// ACT
+	got, err := gocovparser.AttributeUncovered(
+		items, relative, gocovparser.WithBlameTime(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.BlameCount{Lines: 3, Statements: 1}, got.ByAuthor["old@test"])
+	assert.Equal(t, gocovparser.BlameCount{Lines: 4, Statements: 2}, got.ByAuthor["new@test"])
+}
@@ -0,0 +1,92 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// BlocksDelta returns, per file name, the blocks whose state changed between a base run and a head run, sorted by
+// position. Blocks are matched by position, so blocks that moved (e.g. after lines were inserted above them) are
+// reported as removed from base and added in head. Files without changes are left out.
+func BlocksDelta(base, head []Coverage) map[string][]BlockDelta {
+	baseBlocks := blocksByPosition(base)
+	headBlocks := blocksByPosition(head)
+	result := make(map[string][]BlockDelta)
+
+	for fileName, blocks := range headBlocks {
+		for position, block := range blocks {
+			previous, found := baseBlocks[fileName][position]
+			if !found {
+				result[fileName] = append(result[fileName], BlockDelta{
+					Block:     block,
+					HeadCount: block.Count,
+					Change:    BlockAdded,
+				})
+
+				continue
+			}
+
+			if change, changed := blockChange(previous.Count, block.Count); changed {
+				result[fileName] = append(result[fileName], BlockDelta{
+					Block:     block,
+					BaseCount: previous.Count,
+					HeadCount: block.Count,
+					Change:    change,
+				})
+			}
+		}
+	}
+
+	for fileName, blocks := range baseBlocks {
+		for position, block := range blocks {
+			if _, found := headBlocks[fileName][position]; !found {
+				result[fileName] = append(result[fileName], BlockDelta{
+					Block:     block,
+					BaseCount: block.Count,
+					Change:    BlockRemoved,
+				})
+			}
+		}
+	}
+
+	for _, deltas := range result {
+		sort.Slice(deltas, func(i, j int) bool {
+			return blockLess(deltas[i].Block, deltas[j].Block)
+		})
+	}
+
+	return result
+}
+
+// blockChange returns the change between the counts of a block in both runs, and whether it changed.
+func blockChange(baseCount, headCount int) (BlockChange, bool) {
+	switch {
+	case baseCount == 0 && headCount > 0:
+		return BlockNewlyCovered, true
+	case baseCount > 0 && headCount == 0:
+		return BlockNewlyUncovered, true
+	case baseCount != headCount:
+		return BlockCountChanged, true
+	default:
+		return BlockCountChanged, false
+	}
+}
+
+func blocksByPosition(items []Coverage) map[string]map[blockPosition]cover.ProfileBlock {
+	result := make(map[string]map[blockPosition]cover.ProfileBlock, len(items))
+
+	for _, cov := range items {
+		blocks, found := result[cov.FileName]
+		if !found {
+			blocks = make(map[blockPosition]cover.ProfileBlock, len(cov.Blocks))
+			result[cov.FileName] = blocks
+		}
+
+		for _, block := range cov.Blocks {
+			blocks[positionOf(block)] = block
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,55 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestCanComputeBlocksDelta(t *testing.T) {
+	base, err := gocovparser.Parse(`
+mode: count
+example.com/org/repo/a.go:1.1,2.2 1 0
+example.com/org/repo/a.go:3.1,4.2 1 3
+example.com/org/repo/a.go:5.1,6.2 1 2
+example.com/org/repo/a.go:7.1,8.2 1 5
+example.com/org/repo/a.go:9.1,10.2 1 1
+example.com/org/repo/b.go:1.1,2.2 1 1
+`)
+	require.NoError(t, err)
+
+	head, err := gocovparser.Parse(`
+mode: count
+example.com/org/repo/a.go:1.1,2.2 1 4
+example.com/org/repo/a.go:3.1,4.2 1 0
+example.com/org/repo/a.go:5.1,6.2 1 7
+example.com/org/repo/a.go:7.1,8.2 1 5
+example.com/org/repo/a.go:11.1,12.2 1 0
+example.com/org/repo/b.go:1.1,2.2 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.BlocksDelta(base, head)
+
+	// ASSERT
+	block := func(start, count int) cover.ProfileBlock {
+		return cover.ProfileBlock{StartLine: start, StartCol: 1, EndLine: start + 1, EndCol: 2, NumStmt: 1, Count: count}
+	}
+
+	assert.Equal(t, map[string][]gocovparser.BlockDelta{
+		"example.com/org/repo/a.go": {
+			{Block: block(1, 4), BaseCount: 0, HeadCount: 4, Change: gocovparser.BlockNewlyCovered},
+			{Block: block(3, 0), BaseCount: 3, HeadCount: 0, Change: gocovparser.BlockNewlyUncovered},
+			{Block: block(5, 7), BaseCount: 2, HeadCount: 7, Change: gocovparser.BlockCountChanged},
+			{Block: block(9, 1), BaseCount: 1, HeadCount: 0, Change: gocovparser.BlockRemoved},
+			{Block: block(11, 0), BaseCount: 0, HeadCount: 0, Change: gocovparser.BlockAdded},
+		},
+	}, got)
+	assert.Equal(t, "newly uncovered", got["example.com/org/repo/a.go"][1].Change.String())
+}
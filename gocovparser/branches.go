@@ -0,0 +1,160 @@
+package gocovparser
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/cover"
+)
+
+// position is a line and column in a source file.
+type position struct {
+	line int
+	col  int
+}
+
+func (p position) before(other position) bool {
+	return p.line < other.line || (p.line == other.line && p.col < other.col)
+}
+
+// GetBranchBreakdown estimates branch coverage from the coverage blocks and the source files (see SourcePath).
+// Branches are the bodies of if/else statements, switch and type switch cases and select cases; a branch is taken
+// when any block inside it was executed. The implicit else of an if statement is estimated from execution counts
+// (the statement ran more often than its body), so it is only reported for profiles with counts above one.
+func GetBranchBreakdown(items []Coverage, sourceRoot string, opts ...PercentOption) (BranchBreakdown, error) {
+	options := newPercentOptions(opts)
+	result := BranchBreakdown{
+		Files: make(map[string]BranchDetail, len(items)),
+	}
+
+	for _, cov := range items {
+		fset, file, err := parseSource(SourcePath(cov, sourceRoot))
+		if err != nil {
+			return BranchBreakdown{}, err
+		}
+
+		detail := fileBranches(fset, file, cov.Blocks)
+		detail.Percent = options.percentOf(detail.Taken, detail.Branches)
+		result.Files[cov.FileName] = detail
+
+		result.Total.Branches += detail.Branches
+		result.Total.Taken += detail.Taken
+	}
+
+	result.Total.Percent = options.percentOf(result.Total.Taken, result.Total.Branches)
+
+	return result, nil
+}
+
+func fileBranches(fset *token.FileSet, file *ast.File, blocks []cover.ProfileBlock) BranchDetail {
+	detail := BranchDetail{}
+	hasCounts := false
+
+	for _, b := range blocks {
+		if b.Count > 1 {
+			hasCounts = true
+
+			break
+		}
+	}
+
+	addBranch := func(start, end token.Pos) {
+		measured, taken := branchTaken(fset, blocks, start, end)
+		if !measured {
+			return
+		}
+
+		detail.Branches++
+
+		if taken {
+			detail.Taken++
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.IfStmt:
+			addBranch(stmt.Body.Lbrace, stmt.Body.End())
+
+			if elseBlock, ok := stmt.Else.(*ast.BlockStmt); ok {
+				addBranch(elseBlock.Lbrace, elseBlock.End())
+			}
+
+			if stmt.Else == nil && hasCounts {
+				if measured, taken := implicitElseTaken(fset, blocks, stmt); measured {
+					detail.Branches++
+
+					if taken {
+						detail.Taken++
+					}
+				}
+			}
+		case *ast.CaseClause:
+			addBranch(stmt.Colon, stmt.End())
+		case *ast.CommClause:
+			addBranch(stmt.Colon, stmt.End())
+		}
+
+		return true
+	})
+
+	return detail
+}
+
+// branchTaken returns whether any block starts between start and end, and whether any of those was executed.
+func branchTaken(fset *token.FileSet, blocks []cover.ProfileBlock, start, end token.Pos) (bool, bool) {
+	from := toPosition(fset, start)
+	to := toPosition(fset, end)
+	measured := false
+
+	for _, b := range blocks {
+		blockStart := position{line: b.StartLine, col: b.StartCol}
+		if blockStart.before(from) || to.before(blockStart) {
+			continue
+		}
+
+		measured = true
+
+		if b.Count > 0 {
+			return true, true
+		}
+	}
+
+	return measured, false
+}
+
+// implicitElseTaken estimates whether the condition of an if statement without else was ever false,
+// by comparing the count of the block running the if statement with the count of its body.
+func implicitElseTaken(fset *token.FileSet, blocks []cover.ProfileBlock, stmt *ast.IfStmt) (bool, bool) {
+	ifPos := toPosition(fset, stmt.Pos())
+	bodyStart := toPosition(fset, stmt.Body.Lbrace)
+	bodyEnd := toPosition(fset, stmt.Body.End())
+
+	var enclosing, body *cover.ProfileBlock
+
+	for i := range blocks {
+		b := &blocks[i]
+		start := position{line: b.StartLine, col: b.StartCol}
+		end := position{line: b.EndLine, col: b.EndCol}
+
+		if !ifPos.before(start) && ifPos.before(end) {
+			enclosing = b
+		}
+
+		if body == nil && !start.before(bodyStart) && !bodyEnd.before(start) {
+			body = b
+		}
+	}
+
+	if enclosing == nil || body == nil {
+		return false, false
+	}
+
+	return true, enclosing.Count > body.Count
+}
+
+func toPosition(fset *token.FileSet, pos token.Pos) position {
+	p := fset.Position(pos)
+
+	return position{line: p.Line, col: p.Column}
+}
@@ -0,0 +1,105 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const branchesSource = `package bt
+
+func Classify(n int) string {
+	if n > 0 {
+		return "pos"
+	} else if n < 0 {
+		return "neg"
+	}
+
+	switch n {
+	case 0:
+		return "zero"
+	case 1:
+		return "one"
+	}
+
+	return "other"
+}
+
+func Clamp(n int) int {
+	if n > 10 {
+		n = 10
+	}
+
+	return n
+}
+`
+
+const branchesProfile = `mode: count
+example.com/bt/bt.go:4.2,4.11 1 2
+example.com/bt/bt.go:5.3,6.1 1 1
+example.com/bt/bt.go:6.9,6.18 1 1
+example.com/bt/bt.go:7.3,8.1 1 0
+example.com/bt/bt.go:10.2,10.11 1 1
+example.com/bt/bt.go:12.3,12.16 1 1
+example.com/bt/bt.go:14.3,14.15 1 0
+example.com/bt/bt.go:17.2,17.16 1 0
+example.com/bt/bt.go:21.2,21.12 1 2
+example.com/bt/bt.go:22.3,23.1 1 0
+example.com/bt/bt.go:25.2,25.10 1 2
+`
+
+func TestCanGetBranchBreakdown(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bt.go"), []byte(branchesSource), 0o600))
+
+	items, err := gocovparser.Parse(branchesProfile, gocovparser.WithModulePaths("example.com/bt"))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GetBranchBreakdown(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	file := got.Files["example.com/bt/bt.go"]
+	assert.Equal(t, 7, file.Branches)
+	assert.Equal(t, 4, file.Taken)
+	assert.EqualValues(t, 4.0/7.0, file.Percent)
+	assert.Equal(t, file, got.Total)
+}
+
+func TestBranchBreakdownSkipsImplicitElseWithoutCounts(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bt.go"), []byte(branchesSource), 0o600))
+
+	items, err := gocovparser.Parse(`mode: set
+example.com/bt/bt.go:21.2,21.12 1 1
+example.com/bt/bt.go:22.3,23.1 1 0
+example.com/bt/bt.go:25.2,25.10 1 1
+`, gocovparser.WithModulePaths("example.com/bt"))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GetBranchBreakdown(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.BranchDetail{Branches: 1, Taken: 0, Percent: 0}, got.Total)
+}
+
+func TestBranchBreakdownFailsIfSourceIsMissing(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	_, err = gocovparser.GetBranchBreakdown(items, t.TempDir())
+
+	// ASSERT
+	require.Error(t, err)
+}
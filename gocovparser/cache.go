@@ -0,0 +1,189 @@
+package gocovparser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+const diskCacheExtension = ".gob"
+
+// Cache stores parsed coverage by a key derived from the raw profile and the parse options, so unchanged
+// profiles are not parsed again. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the coverage stored under key, and whether it was found.
+	Get(key string) ([]Coverage, bool, error)
+
+	// Set stores the coverage under key.
+	Set(key string, items []Coverage) error
+}
+
+// WithCache looks parsed coverage up in cache before parsing, and stores it there after parsing. Entries are keyed
+// by the SHA-256 of the profile and of the parse options, so go.mod or go.work changes invalidate them, but source
+// changes seen by WithIgnoreDirectives don't. Only results parsed without errors are cached.
+func WithCache(cache Cache) ParseOption {
+	return func(opts *parseOptions) {
+		opts.cache = cache
+	}
+}
+
+// parseCached reads the whole profile to compute its key, and parses it unless its coverage is cached.
+func parseCached(r io.Reader, options parseOptions) ([]Coverage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidCoverageData, err.Error())
+	}
+
+	key := options.cacheKey(data)
+
+	items, found, err := options.cache.Get(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cached coverage")
+	}
+
+	if found {
+		options.debug("coverage cache hit", "key", key)
+
+		return items, nil
+	}
+
+	items, err = parseWithOptions(bytes.NewReader(data), options)
+	if err != nil {
+		return items, err
+	}
+
+	if err := options.cache.Set(key, items); err != nil {
+		return nil, errors.Wrap(err, "failed to cache coverage")
+	}
+
+	return items, nil
+}
+
+// cacheKey hashes the profile with the options changing how it is parsed.
+func (o parseOptions) cacheKey(data []byte) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(
+		hash, "%q %v %q %t %t %t %t %v %t %q %v\n",
+		o.modules, o.moduleDirs, o.ignoreRoot, o.lenient, o.lenientPaths, o.normalize, o.caseInsensitive, o.remapper,
+		o.classify, o.classifyRoot, o.mergeStrategy,
+	)
+	hash.Write(data)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type memoryCache struct {
+	mu      sync.RWMutex
+	entries map[string][]Coverage
+}
+
+var _ Cache = (*memoryCache)(nil)
+
+// NewMemoryCache returns a cache holding coverage in memory, e.g. for the watch and server modes.
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: map[string][]Coverage{}}
+}
+
+func (c *memoryCache) Get(key string) ([]Coverage, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items, found := c.entries[key]
+
+	return copyCoverage(items), found, nil
+}
+
+func (c *memoryCache) Set(key string, items []Coverage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = copyCoverage(items)
+
+	return nil
+}
+
+// copyCoverage copies the items and their blocks, so cached coverage is not changed through the returned items.
+func copyCoverage(items []Coverage) []Coverage {
+	if items == nil {
+		return nil
+	}
+
+	result := make([]Coverage, len(items))
+
+	for index, cov := range items {
+		cov.Blocks = append([]cover.ProfileBlock(nil), cov.Blocks...)
+		result[index] = cov
+	}
+
+	return result
+}
+
+type diskCache struct {
+	dir string
+}
+
+var _ Cache = (*diskCache)(nil)
+
+// NewDiskCache returns a cache storing coverage as gob files in dir, e.g. to share parsed coverage between CI steps.
+// The directory is created when the first entry is stored. Unreadable entries are treated as missing.
+func NewDiskCache(dir string) Cache {
+	return &diskCache{dir: dir}
+}
+
+func (c *diskCache) Get(key string) ([]Coverage, bool, error) {
+	file, err := os.Open(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to open cache entry %q", key)
+	}
+	defer file.Close()
+
+	items := []Coverage{}
+
+	// corrupted entries are parsed again, and replaced
+	found := gob.NewDecoder(file).Decode(&items) == nil
+
+	return items, found, nil
+}
+
+func (c *diskCache) Set(key string, items []Coverage) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create cache directory %q", c.dir)
+	}
+
+	// write to a temporary file first, so concurrent readers never see a partial entry
+	file, err := os.CreateTemp(c.dir, key+"-*")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create cache entry %q", key)
+	}
+	defer os.Remove(file.Name())
+
+	if err := gob.NewEncoder(file).Encode(items); err != nil {
+		file.Close()
+
+		return errors.Wrapf(err, "failed to write cache entry %q", key)
+	}
+
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write cache entry %q", key)
+	}
+
+	return errors.Wrapf(os.Rename(file.Name(), c.path(key)), "failed to store cache entry %q", key)
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key+diskCacheExtension)
+}
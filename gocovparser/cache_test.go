@@ -0,0 +1,110 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCache counts the lookups hitting the wrapped cache.
+type countingCache struct {
+	gocovparser.Cache
+	hits int32
+}
+
+func (c *countingCache) Get(key string) ([]gocovparser.Coverage, bool, error) {
+	items, found, err := c.Cache.Get(key)
+	if found {
+		atomic.AddInt32(&c.hits, 1)
+	}
+
+	return items, found, err
+}
+
+func TestCanParseWithCache(t *testing.T) {
+	for name, cache := range map[string]gocovparser.Cache{
+		"memory": gocovparser.NewMemoryCache(),
+		"disk":   gocovparser.NewDiskCache(filepath.Join(t.TempDir(), "cache")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			counting := &countingCache{Cache: cache}
+			expected, err := gocovparser.Parse(CoverageFixture7(t))
+			require.NoError(t, err)
+
+			// ACT
+			first, firstErr := gocovparser.Parse(CoverageFixture7(t), gocovparser.WithCache(counting))
+			second, secondErr := gocovparser.Parse(CoverageFixture7(t), gocovparser.WithCache(counting))
+
+			// ASSERT
+			require.NoError(t, firstErr)
+			require.NoError(t, secondErr)
+			assert.Equal(t, expected, first)
+			assert.Equal(t, expected, second)
+			assert.EqualValues(t, 1, counting.hits)
+		})
+	}
+}
+
+func TestCacheKeysDependOnParseOptions(t *testing.T) {
+	cache := &countingCache{Cache: gocovparser.NewMemoryCache()}
+	profile := "mode: set\nexample.com/x/tools/a.go:1.1,2.2 1 1\n"
+
+	// ACT
+	guessed, err := gocovparser.Parse(profile, gocovparser.WithCache(cache))
+	require.NoError(t, err)
+
+	declared, err := gocovparser.Parse(
+		profile, gocovparser.WithCache(cache), gocovparser.WithModulePaths("example.com/x"),
+	)
+	require.NoError(t, err)
+
+	// ASSERT
+	assert.EqualValues(t, 0, cache.hits)
+	assert.Equal(t, "tools", guessed[0].Repo)
+	assert.Equal(t, "tools/a.go", declared[0].Path)
+}
+
+func TestMemoryCacheReturnsCopies(t *testing.T) {
+	cache := gocovparser.NewMemoryCache()
+	profile := "mode: set\nexample.com/a/b/c.go:1.1,2.2 1 1\n"
+
+	first, err := gocovparser.Parse(profile, gocovparser.WithCache(cache))
+	require.NoError(t, err)
+
+	// ACT
+	first[0].Blocks[0].Count = 10
+	second, err := gocovparser.Parse(profile, gocovparser.WithCache(cache))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, 1, second[0].Blocks[0].Count)
+}
+
+func TestDiskCacheIgnoresCorruptedEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache := &countingCache{Cache: gocovparser.NewDiskCache(dir)}
+	profile := "mode: set\nexample.com/a/b/c.go:1.1,2.2 1 1\n"
+
+	_, err := gocovparser.Parse(profile, gocovparser.WithCache(cache))
+	require.NoError(t, err)
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.gob"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, os.WriteFile(entries[0], []byte("garbage"), 0o600))
+
+	// ACT
+	got, err := gocovparser.Parse(profile, gocovparser.WithCache(cache))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.EqualValues(t, 0, cache.hits)
+}
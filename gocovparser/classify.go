@@ -0,0 +1,114 @@
+package gocovparser
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Keys of ClassificationParseGroup.
+const (
+	ClassificationSource       = "source"
+	ClassificationGenerated    = "generated"
+	ClassificationMissing      = "missing"
+	ClassificationUnclassified = "unclassified"
+)
+
+// ClassificationParseGroupName is the name of ClassificationParseGroup.
+const ClassificationParseGroupName = "classification"
+
+// generatedFileSuffixes are the names of files written by well known generators.
+var generatedFileSuffixes = []string{".pb.go", ".pb.gw.go", ".pb.validate.go", ".gen.go", "_gen.go"}
+
+// generatedFilePrefixes are the names of cgo shims and of files written by well known generators.
+var generatedFilePrefixes = []string{"_cgo_", "zz_generated"}
+
+// ClassificationParseGroup buckets classified coverage (see ClassifyCoverage) as ClassificationGenerated,
+// ClassificationMissing or ClassificationSource, so generated files and files missing from the source tree are
+// reported separately. Generated files are bucketed as such even if missing. Coverage that wasn't classified is
+// bucketed as ClassificationUnclassified.
+var ClassificationParseGroup = NewParseGroup(ClassificationParseGroupName, func(cov Coverage) string {
+	switch {
+	case !cov.Classified:
+		return ClassificationUnclassified
+	case cov.Generated:
+		return ClassificationGenerated
+	case !cov.Exists:
+		return ClassificationMissing
+	default:
+		return ClassificationSource
+	}
+})
+
+// WithClassification classifies the parsed coverage against the source files found under sourceRoot, as
+// ClassifyCoverage does.
+func WithClassification(sourceRoot string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.classify = true
+		opts.classifyRoot = sourceRoot
+	}
+}
+
+// ClassifyCoverage sets Classified, Generated and Exists on the coverage of every file. Source files are read from
+// their SourcePath under sourceRoot.
+//
+// Files are generated when named as cgo shims (`_cgo_*.go`, `*.cgo1.go`) or as the output of well known
+// generators (e.g. `*.pb.go`, `zz_generated*.go`), or when their source has a `// Code generated ... DO NOT EDIT.`
+// header. Files that no longer exist are still classified by name.
+func ClassifyCoverage(items []Coverage, sourceRoot string) ([]Coverage, error) {
+	result := make([]Coverage, 0, len(items))
+
+	for _, cov := range items {
+		filename := SourcePath(cov, sourceRoot)
+
+		cov.Classified = true
+		cov.Generated = IsGeneratedFileName(cov.FileName)
+
+		_, err := os.Stat(filename)
+
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			cov.Exists = false
+		case err != nil:
+			return nil, errors.Wrapf(err, "failed to classify %q", filename)
+		default:
+			cov.Exists = true
+
+			if !cov.Generated {
+				cov.Generated, err = isGeneratedFile(filename)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to classify %q", filename)
+				}
+			}
+		}
+
+		result = append(result, cov)
+	}
+
+	return result, nil
+}
+
+// IsGeneratedFileName returns whether the file is named as a cgo shim or as the output of a well known generator.
+func IsGeneratedFileName(fileName string) bool {
+	name := path.Base(CanonicalPath(fileName))
+
+	if strings.Contains(name, ".cgo1.") || strings.Contains(name, ".cgo2.") {
+		return true
+	}
+
+	for _, suffix := range generatedFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	for _, prefix := range generatedFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
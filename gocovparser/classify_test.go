@@ -0,0 +1,162 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const classifyCoverage = `
+mode: set
+github.com/heynemann/go-cov-parser/pkg/handler.go:3.28,4.17 2 1
+github.com/heynemann/go-cov-parser/pkg/enum.go:3.28,4.17 2 0
+github.com/heynemann/go-cov-parser/pkg/api.pb.go:3.28,4.17 2 0
+github.com/heynemann/go-cov-parser/pkg/_cgo_gotypes.go:3.28,4.17 2 0
+github.com/heynemann/go-cov-parser/pkg/deleted.go:3.28,4.17 2 0
+`
+
+func writeClassifySource(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+
+	files := map[string]string{
+		"handler.go": "package pkg\n",
+		"enum.go":    "// Code generated by \"stringer -type=Enum\"; DO NOT EDIT.\n\npackage pkg\n",
+		"api.pb.go":  "package pkg\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", name), []byte(content), 0o600))
+	}
+
+	return root
+}
+
+func TestCanClassifyCoverage(t *testing.T) {
+	root := writeClassifySource(t)
+
+	// ACT
+	items, err := gocovparser.Parse(classifyCoverage, gocovparser.WithClassification(root))
+
+	// ASSERT
+	require.NoError(t, err)
+
+	classified := map[string][2]bool{}
+	for _, cov := range items {
+		classified[filepath.Base(cov.Path)] = [2]bool{cov.Generated, cov.Exists}
+	}
+
+	assert.Equal(t, map[string][2]bool{
+		"handler.go":      {false, true},
+		"enum.go":         {true, true},
+		"api.pb.go":       {true, true},
+		"_cgo_gotypes.go": {true, false},
+		"deleted.go":      {false, false},
+	}, classified)
+}
+
+func TestCanGroupCoverageByClassification(t *testing.T) {
+	root := writeClassifySource(t)
+	items, err := gocovparser.Parse(classifyCoverage)
+	require.NoError(t, err)
+
+	items, err = gocovparser.ClassifyCoverage(items, root)
+	require.NoError(t, err)
+
+	// ACT
+	details := gocovparser.GroupBy(items, gocovparser.ClassificationParseGroup.Key)
+
+	// ASSERT
+	assert.Equal(t, map[string]gocovparser.GroupDetail{
+		gocovparser.ClassificationSource:    {Covered: 2, Total: 2, Percent: 1},
+		gocovparser.ClassificationGenerated: {Covered: 0, Total: 6, Percent: 0},
+		gocovparser.ClassificationMissing:   {Covered: 0, Total: 2, Percent: 0},
+	}, details)
+}
+
+func TestCanExcludeClassifiedCoverage(t *testing.T) {
+	root := writeClassifySource(t)
+	items, err := gocovparser.Parse(classifyCoverage, gocovparser.WithClassification(root))
+	require.NoError(t, err)
+
+	// ACT
+	filtered, err := gocovparser.FilterCoverage(
+		items, gocovparser.GeneratedCodeExcludeFilter(root), gocovparser.MissingFileExcludeFilter(),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "pkg/handler.go", filtered[0].Path)
+}
+
+func TestIsGeneratedFileName(t *testing.T) {
+	tests := map[string]bool{
+		"github.com/org/repo/api/service.pb.go":             true,
+		"github.com/org/repo/api/service.pb.gw.go":          true,
+		"github.com/org/repo/pkg/_cgo_gotypes.go":           true,
+		"github.com/org/repo/pkg/sqlite.cgo1.go":            true,
+		"github.com/org/repo/apis/zz_generated.deepcopy.go": true,
+		`C:\work\repo\pkg\models.gen.go`:                    true,
+		"github.com/org/repo/pkg/handler.go":                false,
+		"github.com/org/repo/pb.go/handler.go":              false,
+	}
+
+	for fileName, expected := range tests {
+		t.Run(fileName, func(t *testing.T) {
+			// ACT
+			generated := gocovparser.IsGeneratedFileName(fileName)
+
+			// ASSERT
+			assert.Equal(t, expected, generated)
+		})
+	}
+}
+
+func TestClassificationIgnoresUnclassifiedCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(classifyCoverage)
+	require.NoError(t, err)
+
+	// ACT
+	filtered, err := gocovparser.FilterCoverage(items, gocovparser.MissingFileExcludeFilter())
+	details := gocovparser.GroupBy(items, gocovparser.ClassificationParseGroup.Key)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, filtered, len(items))
+	assert.Equal(t, map[string]gocovparser.GroupDetail{
+		gocovparser.ClassificationUnclassified: {Covered: 2, Total: 10, Percent: 0.2},
+	}, details)
+}
+
+func TestGeneratedCodeFilterUsesClassification(t *testing.T) {
+	items, err := gocovparser.Parse(classifyCoverage)
+	require.NoError(t, err)
+
+	for index := range items {
+		if items[index].Path == "pkg/handler.go" {
+			items[index].Classified, items[index].Generated = true, true
+		}
+	}
+
+	// ACT
+	filtered, err := gocovparser.FilterCoverage(items, gocovparser.GeneratedCodeExcludeFilter(t.TempDir()))
+
+	// ASSERT
+	require.NoError(t, err)
+
+	paths := []string{}
+	for _, cov := range filtered {
+		paths = append(paths, cov.Path)
+	}
+
+	assert.Equal(t, []string{"pkg/deleted.go", "pkg/enum.go"}, paths)
+}
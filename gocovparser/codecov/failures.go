@@ -0,0 +1,9 @@
+package codecov
+
+import "errors"
+
+// ErrUploadFailed happens when Codecov rejects an uploaded report.
+var ErrUploadFailed = errors.New("codecov upload failed - report rejected")
+
+// ErrMissingCommit happens when a report is uploaded without the commit it belongs to.
+var ErrMissingCommit = errors.New("missing commit - unable to upload report")
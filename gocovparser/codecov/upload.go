@@ -0,0 +1,229 @@
+// Package codecov uploads parsed coverage to Codecov.
+package codecov
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/pkg/errors"
+)
+
+// DefaultEndpoint is the Codecov upload API.
+const DefaultEndpoint = "https://codecov.io/upload/v4"
+
+// TokenEnv is the environment variable the upload token is read from by default.
+const TokenEnv = "CODECOV_TOKEN"
+
+// maximum number of bytes of the response body included in upload errors.
+const maxErrorBody = 512
+
+// number of lines in the upload endpoint response: the report URL and the storage URL.
+const announceLines = 2
+
+// UploadOption configures how reports are uploaded.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	endpoint string
+	client   *http.Client
+	token    string
+	branch   string
+	build    string
+	slug     string
+	service  string
+
+	telemetry gocovparser.Telemetry
+}
+
+// WithEndpoint sets the URL reports are posted to. Defaults to DefaultEndpoint.
+func WithEndpoint(endpoint string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the client used to upload reports. Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.client = client
+	}
+}
+
+// WithToken sets the repository upload token. Defaults to the CODECOV_TOKEN environment variable.
+func WithToken(token string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.token = token
+	}
+}
+
+// WithBranch sets the branch the commit belongs to.
+func WithBranch(branch string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.branch = branch
+	}
+}
+
+// WithBuild sets the CI build identifier of the upload.
+func WithBuild(build string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.build = build
+	}
+}
+
+// WithSlug sets the owner/repo the report belongs to, for tokenless uploads.
+func WithSlug(slug string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.slug = slug
+	}
+}
+
+// WithService sets the CI service name, e.g. "github-actions".
+func WithService(service string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.service = service
+	}
+}
+
+// WithTelemetry traces uploads in gocovparser.SpanUpload spans and records their metrics.
+func WithTelemetry(telemetry gocovparser.Telemetry) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.telemetry = telemetry
+	}
+}
+
+// Upload sends the coverage of commit to Codecov. The report is announced to the upload endpoint,
+// which replies with the URL the report is then stored at.
+func Upload(items []gocovparser.Coverage, commit string, opts ...UploadOption) error {
+	return UploadContext(context.Background(), items, commit, opts...)
+}
+
+// UploadContext sends the coverage as Upload does, aborting the requests once ctx is done.
+func UploadContext(ctx context.Context, items []gocovparser.Coverage, commit string, opts ...UploadOption) error {
+	if commit == "" {
+		return ErrMissingCommit
+	}
+
+	options := uploadOptions{
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+		token:    os.Getenv(TokenEnv),
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, operation := options.telemetry.Start(ctx, gocovparser.SpanUpload)
+	err := upload(ctx, items, commit, options)
+
+	operation.End(
+		err,
+		gocovparser.Attribute{Key: gocovparser.AttributeService, Value: "codecov"},
+		gocovparser.Attribute{Key: gocovparser.AttributeFiles, Value: len(items)},
+	)
+
+	return err
+}
+
+func upload(ctx context.Context, items []gocovparser.Coverage, commit string, options uploadOptions) error {
+	var report bytes.Buffer
+
+	report.WriteString("# path=coverage.json\n")
+
+	if err := export.WriteCodecov(&report, items); err != nil {
+		return err
+	}
+
+	report.WriteString("<<<<<< EOF\n")
+
+	storeURL, err := announce(ctx, options, commit)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, storeURL, &report)
+	if err != nil {
+		return errors.Wrap(err, "failed to build codecov request")
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+
+	return send(options.client, req)
+}
+
+// announce posts the upload metadata and returns the URL the report must be stored at.
+func announce(ctx context.Context, options uploadOptions, commit string) (string, error) {
+	query := url.Values{"commit": {commit}, "package": {"go-cov-parser"}}
+
+	for key, value := range map[string]string{
+		"token":   options.token,
+		"branch":  options.branch,
+		"build":   options.build,
+		"slug":    options.slug,
+		"service": options.service,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, options.endpoint+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to build codecov request")
+	}
+
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := options.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to post codecov upload")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read codecov response")
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", errors.Wrapf(ErrUploadFailed, "%s: %s", resp.Status, truncate(body))
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) < announceLines {
+		return "", errors.Wrapf(ErrUploadFailed, "unexpected response %q", truncate(body))
+	}
+
+	return strings.TrimSpace(lines[announceLines-1]), nil
+}
+
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to store codecov report")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+		return errors.Wrapf(ErrUploadFailed, "%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
+
+func truncate(body []byte) []byte {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+
+	return bytes.TrimSpace(body)
+}
@@ -0,0 +1,128 @@
+package codecov_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/codecov"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uploadFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1
+`
+
+func TestCanUploadReport(t *testing.T) {
+	t.Setenv(codecov.TokenEnv, "env-token")
+
+	items, err := gocovparser.Parse(uploadFixture)
+	require.NoError(t, err)
+
+	var query url.Values
+
+	var stored string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			query = r.URL.Query()
+			_, _ = io.WriteString(w, "https://codecov.io/report\n"+server.URL+"/store\n")
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			stored = string(body)
+		}
+	}))
+	defer server.Close()
+
+	// ACT
+	err = codecov.Upload(items, "abc123", codecov.WithEndpoint(server.URL), codecov.WithBranch("main"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", query.Get("commit"))
+	assert.Equal(t, "env-token", query.Get("token"))
+	assert.Equal(t, "main", query.Get("branch"))
+	assert.Contains(t, stored, `"pkg/a.go":{"3":1,"4":1,"5":1}`)
+	assert.Contains(t, stored, "<<<<<< EOF")
+}
+
+func TestUploadFailsIfReportIsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	// ACT
+	err := codecov.Upload(nil, "abc123", codecov.WithEndpoint(server.URL), codecov.WithToken("token"))
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, codecov.ErrUploadFailed)
+	assert.Contains(t, err.Error(), "invalid token")
+}
+
+func TestUploadFailsWithoutCommit(t *testing.T) {
+	// ACT
+	err := codecov.Upload(nil, "")
+
+	// ASSERT
+	assert.ErrorIs(t, err, codecov.ErrMissingCommit)
+}
+
+func TestUploadContextAbortsWhenCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// ACT
+	err := codecov.UploadContext(ctx, nil, "abc123", codecov.WithEndpoint(server.URL))
+
+	// ASSERT
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, requests)
+}
+
+type recordingMeter struct {
+	attrs map[string][]gocovparser.Attribute
+}
+
+func (m *recordingMeter) Record(_ context.Context, metric string, _ float64, attrs ...gocovparser.Attribute) {
+	m.attrs[metric] = attrs
+}
+
+func TestUploadWithTelemetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	meter := &recordingMeter{attrs: map[string][]gocovparser.Attribute{}}
+
+	// ACT
+	err := codecov.Upload(
+		nil, "abc123", codecov.WithEndpoint(server.URL), codecov.WithTelemetry(gocovparser.Telemetry{Meter: meter}),
+	)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.Equal(t, []gocovparser.Attribute{
+		{Key: gocovparser.AttributeOperation, Value: gocovparser.SpanUpload},
+		{Key: gocovparser.AttributeError, Value: true},
+	}, meter.attrs[gocovparser.MetricDuration])
+}
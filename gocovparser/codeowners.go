@@ -0,0 +1,102 @@
+package gocovparser
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UnownedKey is the ByCodeOwner key of files that no CODEOWNERS rule assigns owners to.
+const UnownedKey = "(unowned)"
+
+// codeOwnersRule is a CODEOWNERS line: a path pattern and its owners.
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  string
+}
+
+// ByCodeOwner returns a parse group named "codeowner" keyed by the owners of each file, as assigned by the
+// GitHub CODEOWNERS file at codeownersPath. Owners of a rule are joined by spaces (e.g. "@org/a @org/b").
+// As on GitHub, the last matching rule wins; files without owners are keyed by UnownedKey.
+// Patterns are matched against the file path relative to its repository.
+func ByCodeOwner(codeownersPath string) (ParseGroup, error) {
+	file, err := os.Open(codeownersPath)
+	if err != nil {
+		return ParseGroup{}, errors.Wrapf(err, "failed to open CODEOWNERS file %q", codeownersPath)
+	}
+	defer file.Close()
+
+	rules := []codeOwnersRule{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		rules = append(rules, codeOwnersRule{
+			pattern: regexp.MustCompile(codeOwnersRegex(fields[0])),
+			owners:  strings.Join(fields[1:], " "),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ParseGroup{}, errors.Wrapf(err, "failed to read CODEOWNERS file %q", codeownersPath)
+	}
+
+	return ParseGroup{
+		Name: "codeowner",
+		KeyFunc: func(filename string) string {
+			repoPath := filename
+			if location, ok := splitFileName(filename, nil); ok {
+				repoPath = location.path
+			}
+
+			owners := ""
+
+			for _, rule := range rules {
+				if rule.pattern.MatchString(repoPath) {
+					owners = rule.owners
+				}
+			}
+
+			if owners == "" {
+				return UnownedKey
+			}
+
+			return owners
+		},
+	}, nil
+}
+
+// codeOwnersRegex converts a CODEOWNERS pattern to a regular expression matching repository relative paths.
+// Patterns containing a slash (other than a trailing one) are anchored to the repository root, others match at any
+// depth. A pattern matches the paths under it, except when it ends with `/*`, which only matches direct children.
+func codeOwnersRegex(pattern string) string {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directChildren := strings.HasSuffix(pattern, "/*")
+
+	glob := globToRegex(strings.Trim(pattern, "/"))
+	glob = strings.TrimSuffix(strings.TrimPrefix(glob, "^"), "$")
+
+	prefix := "^(?:.*/)?"
+	if anchored {
+		prefix = "^"
+	}
+
+	suffix := "(?:/.*)?$"
+	if directChildren {
+		suffix = "$"
+	}
+
+	return prefix + glob + suffix
+}
@@ -0,0 +1,86 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCodeOwners(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "CODEOWNERS")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func TestByCodeOwnerKeys(t *testing.T) {
+	path := writeCodeOwners(t, `# default owners
+*                @org/platform
+
+/internal/       @org/backend # trailing comment
+*.pb.go          @org/api
+docs/*           @org/docs
+/internal/legacy/
+/pkg/shared/     @alice @org/shared
+`)
+
+	group, err := gocovparser.ByCodeOwner(path)
+	require.NoError(t, err)
+
+	testCases := map[string]string{
+		"github.com/owner/repo/main.go":                    "@org/platform",
+		"github.com/owner/repo/internal/config/config.go":  "@org/backend",
+		"github.com/owner/repo/internal/api/service.pb.go": "@org/api",
+		"github.com/owner/repo/docs/example.go":            "@org/docs",
+		"github.com/owner/repo/docs/nested/example.go":     "@org/platform",
+		"github.com/owner/repo/internal/legacy/old.go":     gocovparser.UnownedKey,
+		"github.com/owner/repo/pkg/shared/shared.go":       "@alice @org/shared",
+		"github.com/owner/repo/other/internal/file.go":     "@org/platform",
+	}
+
+	for filename, expected := range testCases {
+		t.Run(filename, func(t *testing.T) {
+			// ACT
+			got := group.KeyFunc(filename)
+
+			// ASSERT
+			assert.Equal(t, "codeowner", group.Name)
+			assert.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestByCodeOwnerGroupsCoverage(t *testing.T) {
+	path := writeCodeOwners(t, "/internal/ @org/backend\n")
+
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	group, err := gocovparser.ByCodeOwner(path)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverage(items, group)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got["codeowner"], 2)
+	assert.InDelta(t, 0.7002, got["codeowner"]["@org/backend"], 0.0001)
+	assert.InDelta(t, 0.9228, got["codeowner"][gocovparser.UnownedKey], 0.0001)
+}
+
+func TestByCodeOwnerFailsForMissingFile(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ByCodeOwner(filepath.Join(t.TempDir(), "CODEOWNERS"))
+
+	// ASSERT
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// CompareCoverage compares a head coverage run against a base run (e.g. the main branch), per file,
+// per key of the specified groups and overall, to detect coverage regressions.
+func CompareCoverage(base, head []Coverage, groups ...ParseGroup) (CoverageComparison, error) {
+	baseBreakdown := GetTotalCoverageBreakdown(base)
+	headBreakdown := GetTotalCoverageBreakdown(head)
+
+	result := CoverageComparison{
+		Base:        baseBreakdown,
+		Head:        headBreakdown,
+		Delta:       headBreakdown.Coverage - baseBreakdown.Coverage,
+		Files:       compareFiles(base, head),
+		Groups:      make(map[string]map[string]CoverageDelta, len(groups)),
+		Regressions: []string{},
+	}
+
+	for fileName, file := range result.Files {
+		if file.Base.Total > 0 && file.Head.Total > 0 && file.Delta < 0 {
+			result.Regressions = append(result.Regressions, fileName)
+		}
+	}
+
+	sort.Strings(result.Regressions)
+
+	baseGroups, err := GroupCoverage(base, groups...)
+	if err != nil {
+		return CoverageComparison{}, errors.Wrap(err, "failed to group base coverage")
+	}
+
+	headGroups, err := GroupCoverage(head, groups...)
+	if err != nil {
+		return CoverageComparison{}, errors.Wrap(err, "failed to group head coverage")
+	}
+
+	for _, group := range groups {
+		deltas := make(map[string]CoverageDelta)
+
+		for key, coverage := range baseGroups[group.Name] {
+			deltas[key] = CoverageDelta{Base: coverage, Delta: -coverage}
+		}
+
+		for key, coverage := range headGroups[group.Name] {
+			delta := deltas[key]
+			delta.Head = coverage
+			delta.Delta = coverage - delta.Base
+			deltas[key] = delta
+		}
+
+		result.Groups[group.Name] = deltas
+	}
+
+	return result, nil
+}
+
+func compareFiles(base, head []Coverage) map[string]FileComparison {
+	fileName := func(cov Coverage) string { return cov.FileName }
+	baseDetails := GroupBy(base, fileName)
+	headDetails := GroupBy(head, fileName)
+	baseLines := GetLineCoverage(base)
+	headLines := GetLineCoverage(head)
+
+	files := make(map[string]FileComparison, len(headDetails))
+
+	for name, detail := range baseDetails {
+		files[name] = FileComparison{Base: detail, Delta: -detail.Percent}
+	}
+
+	for name, detail := range headDetails {
+		file := files[name]
+		file.Head = detail
+		file.Delta = detail.Percent - file.Base.Percent
+		file.NewUncoveredLines = newUncoveredLines(baseLines[name], headLines[name])
+		files[name] = file
+	}
+
+	return files
+}
+
+func newUncoveredLines(base, head FileLineCoverage) []int {
+	lines := []int{}
+
+	for number, line := range head {
+		if line.Status != LineUncovered {
+			continue
+		}
+
+		if previous, found := base[number]; found && previous.Status == LineUncovered {
+			continue
+		}
+
+		lines = append(lines, number)
+	}
+
+	sort.Ints(lines)
+
+	return lines
+}
@@ -0,0 +1,73 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanCompareCoverage(t *testing.T) {
+	base, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,11.2 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,21.2 2 0
+github.com/heynemann/go-cov-parser/gocovparser/filter.go:5.1,5.10 1 1
+`)
+	require.NoError(t, err)
+
+	head, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,11.2 2 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,21.2 2 0
+github.com/heynemann/go-cov-parser/gocovparser/models.go:1.1,1.10 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.CompareCoverage(base, head, gocovparser.PackageParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 0.6, got.Base.Coverage)
+	assert.EqualValues(t, 0.2, got.Head.Coverage)
+	assert.InDelta(t, -0.4, got.Delta, 1e-9)
+
+	core := got.Files["github.com/heynemann/go-cov-parser/gocovparser/core.go"]
+	assert.EqualValues(t, 0.5, core.Base.Percent)
+	assert.EqualValues(t, 0, core.Head.Percent)
+	assert.EqualValues(t, -0.5, core.Delta)
+	assert.Equal(t, []int{10, 11}, core.NewUncoveredLines)
+
+	removed := got.Files["github.com/heynemann/go-cov-parser/gocovparser/filter.go"]
+	assert.EqualValues(t, -1, removed.Delta)
+
+	added := got.Files["github.com/heynemann/go-cov-parser/gocovparser/models.go"]
+	assert.EqualValues(t, 1, added.Delta)
+	assert.Empty(t, added.NewUncoveredLines)
+
+	assert.Equal(t, []string{"github.com/heynemann/go-cov-parser/gocovparser/core.go"}, got.Regressions)
+
+	pkg := got.Groups["package"]["github.com/heynemann/go-cov-parser/gocovparser"]
+	assert.EqualValues(t, 0.6, pkg.Base)
+	assert.EqualValues(t, 0.2, pkg.Head)
+	assert.InDelta(t, -0.4, pkg.Delta, 1e-9)
+}
+
+func TestCompareIdenticalCoverageHasNoRegressions(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.CompareCoverage(items, items, gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Zero(t, got.Delta)
+	assert.Empty(t, got.Regressions)
+	assert.Zero(t, got.Groups["total"]["total"].Delta)
+}
@@ -0,0 +1,135 @@
+package gocovparser
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// WithConcurrency sets the number of workers used by ParseFiles. Values below 1 use one worker per CPU.
+func WithConcurrency(workers int) ParseOption {
+	return func(opts *parseOptions) {
+		opts.concurrency = workers
+	}
+}
+
+// ParseFiles parses the coverage files at paths concurrently and merges them into a single result,
+// as MergeCoverage does with the strategy set by WithMergeStrategy, so files in set and count modes are merged in
+// set mode. The number of workers is set by WithConcurrency. With WithLenientParsing, the errors skipped in every
+// file are returned together.
+func ParseFiles(paths []string, opts ...ParseOption) ([]Coverage, error) {
+	options, err := newParseOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]Coverage, len(paths))
+	errs := make([]error, len(paths))
+
+	runWorkers(len(paths), options.concurrency, func(i int) {
+		results[i], errs[i] = ParseFile(paths[i], opts...)
+	})
+
+	skipped := ParseErrors{}
+
+	for i, err := range errs {
+		parseErrs := ParseErrors{}
+
+		switch {
+		case err == nil:
+		case options.lenient && errors.As(err, &parseErrs):
+			for _, parseErr := range parseErrs {
+				skipped = append(skipped, errors.Wrapf(parseErr, "in %q", paths[i]))
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	merged, err := MergeCoverageWithStrategy(options.mergeStrategy, results...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(skipped) > 0 {
+		return merged, skipped
+	}
+
+	return merged, nil
+}
+
+// GroupCoverageConcurrently groups coverage as GroupCoverage does, splitting items between workers.
+// Values of workers below 1 use one worker per CPU.
+func GroupCoverageConcurrently(items []Coverage, workers int, groups ...ParseGroup) (ParseGroupResult, error) {
+	workers = workerCount(len(items), workers)
+	chunkSize := (len(items) + workers - 1) / workers
+	partials := make([]groupTotals, workers)
+
+	runWorkers(workers, workers, func(i int) {
+		start := i * chunkSize
+		end := start + chunkSize
+
+		if end > len(items) {
+			end = len(items)
+		}
+
+		if start > end {
+			start = end
+		}
+
+		partials[i] = groupTotalsOf(items[start:end], groups)
+	})
+
+	totals := groupTotalsOf(nil, groups)
+
+	for _, partial := range partials {
+		totals.add(partial)
+	}
+
+	return totals.result(), nil
+}
+
+// runWorkers calls fn for every index in [0, jobs) using at most workers goroutines.
+func runWorkers(jobs, workers int, fn func(i int)) {
+	workers = workerCount(jobs, workers)
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < jobs; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// workerCount bounds workers between one and the number of jobs, defaulting to one worker per CPU.
+func workerCount(jobs, workers int) int {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > jobs {
+		workers = jobs
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	return workers
+}
@@ -0,0 +1,132 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestCanParseFilesConcurrently(t *testing.T) {
+	items, err := gocovparser.ParseFile("./coverage-fixture4.out")
+	require.NoError(t, err)
+
+	expected, err := gocovparser.MergeCoverage(items, items)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ParseFiles(
+		[]string{"./coverage-fixture4.out", "./coverage-fixture4.out"},
+		gocovparser.WithConcurrency(2),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestParseFilesFailsIfAnyFileFails(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseFiles([]string{"./coverage-fixture4.out", "./does-not-exist.out"})
+
+	// ASSERT
+	require.Error(t, err)
+}
+
+func TestParseFilesMergesFilesOfDifferentModes(t *testing.T) {
+	dir := t.TempDir()
+	count := filepath.Join(dir, "count.out")
+	set := filepath.Join(dir, "set.out")
+
+	require.NoError(t, os.WriteFile(count, []byte("mode: count\ngithub.com/a/b/c.go:1.1,2.2 1 3\n"), 0o600))
+	require.NoError(t, os.WriteFile(set, []byte("mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 0\n"), 0o600))
+
+	// ACT
+	got, err := gocovparser.ParseFiles([]string{count, set})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, gocovparser.ModeSet, got[0].Mode)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+}
+
+func TestGroupCoverageConcurrentlyMatchesGroupCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	groups := []gocovparser.ParseGroup{
+		gocovparser.FileParseGroup,
+		gocovparser.PackageParseGroup,
+		gocovparser.TotalParseGroup,
+	}
+
+	expected, err := gocovparser.GroupCoverage(items, groups...)
+	require.NoError(t, err)
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		// ACT
+		got, err := gocovparser.GroupCoverageConcurrently(items, workers, groups...)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Equal(t, expected, got, "workers: %d", workers)
+	}
+}
+
+func TestGroupCoverageConcurrentlyOfEmptyCoverage(t *testing.T) {
+	// ACT
+	got, err := gocovparser.GroupCoverageConcurrently(nil, 4, gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.ParseGroupResult{"total": {}}, got)
+}
+
+func largeCoverage(files, blocks int) []gocovparser.Coverage {
+	items := make([]gocovparser.Coverage, 0, files)
+
+	for f := 0; f < files; f++ {
+		cov := gocovparser.Coverage{
+			FileName: fmt.Sprintf("github.com/heynemann/monorepo/pkg%d/file%d.go", f%100, f),
+			Blocks:   make([]cover.ProfileBlock, 0, blocks),
+		}
+
+		for b := 0; b < blocks; b++ {
+			cov.Blocks = append(cov.Blocks, cover.ProfileBlock{
+				StartLine: b + 1, EndLine: b + 2, NumStmt: 1, Count: b % 2,
+			})
+		}
+
+		items = append(items, cov)
+	}
+
+	return items
+}
+
+func BenchmarkGroupCoverage(b *testing.B) {
+	items := largeCoverage(20000, 20)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = gocovparser.GroupCoverage(items, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup)
+	}
+}
+
+func BenchmarkGroupCoverageConcurrently(b *testing.B) {
+	items := largeCoverage(20000, 20)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = gocovparser.GroupCoverageConcurrently(items, 0, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup)
+	}
+}
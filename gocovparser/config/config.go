@@ -0,0 +1,228 @@
+// Package config reads gocovparser.yaml (or .gocovrc) files describing groups, exclusions, thresholds and exports,
+// and runs them, so coverage handling can be configured declaratively.
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the names of configuration files looked up by Find, in order of precedence.
+var FileNames = []string{"gocovparser.yaml", "gocovparser.yml", ".gocovrc"}
+
+const (
+	defaultCoverageFile = "coverage.out"
+	defaultCodeOwners   = ".github/CODEOWNERS"
+)
+
+// groupKinds are the values of Group.By.
+var groupKinds = map[string]bool{
+	"package": true, "file": true, "repo": true, "owner": true, "directory": true, "codeowner": true, "total": true,
+}
+
+// Config describes how coverage is parsed, grouped, checked and exported. Paths are relative to the directory of
+// the configuration file.
+type Config struct {
+	// Coverage is the path of the coverage profile. Defaults to coverage.out.
+	Coverage string `yaml:"coverage"`
+
+	// ModuleRoot is the directory of the go.mod the coverage paths are relative to, if any.
+	ModuleRoot string `yaml:"moduleRoot"`
+
+	// Remap rewrites the file names of the coverage, e.g. from the layout of the container it was collected in.
+	Remap []Remap `yaml:"remap"`
+
+	// Groups the coverage is grouped in, besides the total. Defaults to the package group.
+	Groups []Group `yaml:"groups"`
+
+	// Exclude lists glob patterns of files left out of the coverage (see gocovparser.GlobExcludeFilter).
+	Exclude []string `yaml:"exclude"`
+
+	// Thresholds are the minimum coverage of group keys.
+	Thresholds []Threshold `yaml:"thresholds"`
+
+	// Exports are the reports written from the coverage.
+	Exports []Export `yaml:"exports"`
+}
+
+// Group is a parse group: by package, file, repo, owner, directory, codeowner or total.
+type Group struct {
+	By string `yaml:"by"`
+
+	// Name of the group in thresholds and results. Defaults to the name of the parse group (e.g. "directory-2").
+	Name string `yaml:"name"`
+
+	// Depth is the number of path segments of directory groups. Defaults to 1.
+	Depth int `yaml:"depth"`
+
+	// CodeOwners is the CODEOWNERS file of codeowner groups. Defaults to .github/CODEOWNERS.
+	CodeOwners string `yaml:"codeowners"`
+}
+
+// Threshold is the minimum coverage percentage (0-100), or the maximum number of uncovered statements, of the keys
+// of a group.
+type Threshold struct {
+	// Group name, e.g. "total" or "package".
+	Group string `yaml:"group"`
+
+	// Key the threshold applies to, as gocovparser.PolicyRule.Key. Empty applies it to every key of the group.
+	Key string `yaml:"key"`
+
+	Minimum float64 `yaml:"minimum"`
+
+	// MaxUncovered is the maximum number of uncovered statements of the keys. Zero doesn't limit them.
+	MaxUncovered int `yaml:"maxUncovered"`
+
+	// MinStatements ignores keys with fewer statements, e.g. tiny utility packages.
+	MinStatements int `yaml:"minStatements"`
+}
+
+// Remap is a rule of a gocovparser.PathRemapper, rewriting either a prefix or the matches of a regular expression.
+type Remap struct {
+	Prefix string `yaml:"prefix"`
+	Regex  string `yaml:"regex"`
+
+	// To is the replacement of the prefix or of the matches, which can refer to the groups of the regex as $1.
+	To string `yaml:"to"`
+}
+
+// Export is a report written in one of the registered export formats.
+type Export struct {
+	Format string `yaml:"format"`
+
+	// Output is the path of the report.
+	Output string `yaml:"output"`
+
+	// Options of the format, as given to export.Exporter.Write.
+	Options map[string]string `yaml:"options"`
+}
+
+// Find returns the path of the configuration file in dir, or fails with ErrConfigNotFound.
+func Find(dir string) (string, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", errors.Wrapf(ErrConfigNotFound, "searched %q", dir)
+}
+
+// Load reads the configuration file at path. Relative paths of the configuration are resolved against the
+// directory of the file, and unknown fields fail, to catch typos. Empty files load as an empty configuration.
+func Load(path string) (Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to open config file %q", path)
+	}
+	defer file.Close()
+
+	config := Config{}
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, errors.Wrapf(ErrInvalidConfig, "%q: %v", path, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return Config{}, errors.Wrapf(err, "%q", path)
+	}
+
+	config.resolve(filepath.Dir(path))
+
+	return config, nil
+}
+
+func (c Config) validate() error {
+	for _, remap := range c.Remap {
+		if (remap.Prefix == "") == (remap.Regex == "") {
+			return errors.Wrap(ErrInvalidConfig, "remap rules require either a prefix or a regex")
+		}
+
+		if _, err := regexp.Compile(remap.Regex); err != nil {
+			return errors.Wrapf(ErrInvalidConfig, "invalid remap regex %q: %v", remap.Regex, err)
+		}
+	}
+
+	for _, group := range c.Groups {
+		if !groupKinds[group.By] {
+			return errors.Wrapf(ErrInvalidConfig, "unknown group %q", group.By)
+		}
+	}
+
+	for _, export := range c.Exports {
+		if export.Format == "" || export.Output == "" {
+			return errors.Wrap(ErrInvalidConfig, "exports require a format and an output")
+		}
+	}
+
+	return nil
+}
+
+// resolve sets the defaults and makes the paths relative to dir.
+func (c *Config) resolve(dir string) {
+	if c.Coverage == "" {
+		c.Coverage = defaultCoverageFile
+	}
+
+	if len(c.Groups) == 0 {
+		c.Groups = []Group{{By: "package"}}
+	}
+
+	c.Coverage = resolvePath(dir, c.Coverage)
+	c.ModuleRoot = resolvePath(dir, c.ModuleRoot)
+
+	for index := range c.Groups {
+		if c.Groups[index].By == "codeowner" {
+			if c.Groups[index].CodeOwners == "" {
+				c.Groups[index].CodeOwners = defaultCodeOwners
+			}
+
+			c.Groups[index].CodeOwners = resolvePath(dir, c.Groups[index].CodeOwners)
+		}
+	}
+
+	for index := range c.Exports {
+		c.Exports[index].Output = resolvePath(dir, c.Exports[index].Output)
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+// pathRule returns the path rule described by the remap.
+func (r Remap) pathRule() gocovparser.PathRule {
+	if r.Regex != "" {
+		return gocovparser.RegexRule(r.Regex, r.To)
+	}
+
+	return gocovparser.PrefixRule(r.Prefix, r.To)
+}
+
+// parseGroup returns the parse group described by the group.
+func (g Group) parseGroup() (gocovparser.ParseGroup, error) {
+	group, err := gocovparser.ParseGroupByName(g.By, g.Depth, g.CodeOwners)
+	if err != nil {
+		return gocovparser.ParseGroup{}, err
+	}
+
+	if g.Name != "" {
+		group.Name = g.Name
+	}
+
+	return group, nil
+}
@@ -0,0 +1,160 @@
+package config_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configFixture = `mode: set
+github.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1
+github.com/heynemann/go-cov-parser/pkg/a.go:7.10,9.2 1 0
+github.com/heynemann/go-cov-parser/other/b.go:3.10,5.2 2 1
+github.com/heynemann/go-cov-parser/gen/c.pb.go:3.10,5.2 4 0
+`
+
+func writeConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "coverage.out"), []byte(configFixture), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+
+	return dir
+}
+
+func TestCanLoadConfig(t *testing.T) {
+	dir := writeConfig(t, "gocovparser.yaml", `
+coverage: profiles/coverage.out
+groups:
+  - by: directory
+    depth: 2
+    name: areas
+exclude: ["**/*.pb.go"]
+thresholds:
+  - group: total
+    minimum: 80
+exports:
+  - format: lcov
+    output: out/lcov.info
+`)
+
+	// ACT
+	got, err := config.Load(filepath.Join(dir, "gocovparser.yaml"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, config.Config{
+		Coverage:   filepath.Join(dir, "profiles", "coverage.out"),
+		Groups:     []config.Group{{By: "directory", Name: "areas", Depth: 2}},
+		Exclude:    []string{"**/*.pb.go"},
+		Thresholds: []config.Threshold{{Group: "total", Minimum: 80}},
+		Exports:    []config.Export{{Format: "lcov", Output: filepath.Join(dir, "out", "lcov.info")}},
+	}, got)
+}
+
+func TestCanLoadEmptyConfig(t *testing.T) {
+	dir := writeConfig(t, ".gocovrc", "")
+
+	// ACT
+	got, err := config.Load(filepath.Join(dir, ".gocovrc"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, config.Config{
+		Coverage: filepath.Join(dir, "coverage.out"),
+		Groups:   []config.Group{{By: "package"}},
+	}, got)
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	for name, contents := range map[string]string{
+		"unknown field": "thresold: []\n",
+		"unknown group": "groups: [{by: team}]\n",
+		"no output":     "exports: [{format: lcov}]\n",
+		"no remap rule": "remap: [{to: github.com/a/b/}]\n",
+		"remap regex":   "remap: [{regex: '(', to: a}]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := writeConfig(t, ".gocovrc", contents)
+
+			// ACT
+			_, err := config.Load(filepath.Join(dir, ".gocovrc"))
+
+			// ASSERT
+			assert.ErrorIs(t, err, config.ErrInvalidConfig)
+		})
+	}
+}
+
+func TestCanRunFromConfig(t *testing.T) {
+	dir := writeConfig(t, ".gocovrc", `
+exclude: ["gen/**"]
+thresholds:
+  - group: total
+    minimum: 70
+  - group: package
+    key: pkg
+    minimum: 60
+  - group: package
+    maxUncovered: 1
+    minStatements: 3
+exports:
+  - format: lcov
+    output: lcov.info
+  - format: prometheus
+    output: metrics.prom
+    options: {namespace: cov}
+`)
+
+	// ACT
+	got, err := config.RunFromConfig(context.Background(), dir)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got.Coverage, 2)
+	assert.EqualValues(t, 0.75, got.Groups["total"]["total"])
+	assert.EqualValues(t, 0.5, got.Groups["package"]["github.com/heynemann/go-cov-parser/pkg"])
+	assert.False(t, got.Passed())
+	require.Len(t, got.Violations, 1)
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/pkg", got.Violations[0].Key)
+
+	assert.FileExists(t, filepath.Join(dir, "lcov.info"))
+
+	metrics, err := os.ReadFile(filepath.Join(dir, "metrics.prom"))
+	require.NoError(t, err)
+	assert.Contains(t, string(metrics), "cov_coverage_ratio 0.75\n")
+}
+
+func TestRunFromConfigRemapsPaths(t *testing.T) {
+	dir := writeConfig(t, "gocovparser.yaml", `
+remap:
+  - regex: ^github.com/heynemann/go-cov-parser/(pkg|other)/
+    to: github.com/heynemann/go-cov-parser/src/
+`)
+
+	// ACT
+	got, err := config.RunFromConfig(context.Background(), dir)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got.Coverage, 3)
+	assert.Equal(t, "gen/c.pb.go", got.Coverage[0].Path)
+	assert.Equal(t, "src/a.go", got.Coverage[1].Path)
+	assert.Equal(t, "src/b.go", got.Coverage[2].Path)
+}
+
+func TestRunFromConfigWithoutConfigFile(t *testing.T) {
+	// ACT
+	_, err := config.RunFromConfig(context.Background(), t.TempDir())
+
+	// ASSERT
+	assert.ErrorIs(t, err, config.ErrConfigNotFound)
+}
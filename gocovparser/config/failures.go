@@ -0,0 +1,9 @@
+package config
+
+import "errors"
+
+// ErrConfigNotFound happens when no configuration file is found in a directory.
+var ErrConfigNotFound = errors.New("config file not found - expected gocovparser.yaml, gocovparser.yml or .gocovrc")
+
+// ErrInvalidConfig happens when a configuration file can't be decoded or has invalid values.
+var ErrInvalidConfig = errors.New("invalid config file - check its fields and values")
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"os"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/pkg/errors"
+)
+
+const percentScale = 100
+
+// Result is the outcome of running a configuration.
+type Result struct {
+	// Coverage is the parsed coverage, without the excluded files.
+	Coverage []gocovparser.Coverage
+
+	// Groups holds the coverage of the configured groups and of the total.
+	Groups gocovparser.ParseGroupResult
+
+	// Details holds the statement and line counts of the configured groups and of the total.
+	Details gocovparser.DetailedGroupResult
+
+	// Violations lists the thresholds that don't hold, as returned by gocovparser.CheckPolicyDetailed.
+	Violations []gocovparser.PolicyViolation
+}
+
+// Passed returns whether every threshold holds.
+func (r Result) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// RunFromConfig loads the configuration file at cfgPath, or found in the cfgPath directory, and runs it.
+func RunFromConfig(ctx context.Context, cfgPath string) (Result, error) {
+	if info, err := os.Stat(cfgPath); err == nil && info.IsDir() {
+		found, err := Find(cfgPath)
+		if err != nil {
+			return Result{}, err
+		}
+
+		cfgPath = found
+	}
+
+	config, err := Load(cfgPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Run(ctx, config)
+}
+
+// Run parses the coverage of the configuration, drops the excluded files, groups it, checks the thresholds and
+// writes the exports. Failing thresholds are reported in the result, not as an error, so exports are written
+// either way.
+func Run(ctx context.Context, config Config) (Result, error) {
+	parseOpts := []gocovparser.ParseOption{}
+	if config.ModuleRoot != "" {
+		parseOpts = append(parseOpts, gocovparser.WithModuleRoot(config.ModuleRoot))
+	}
+
+	if len(config.Remap) > 0 {
+		rules := make([]gocovparser.PathRule, 0, len(config.Remap))
+		for _, remap := range config.Remap {
+			rules = append(rules, remap.pathRule())
+		}
+
+		parseOpts = append(parseOpts, gocovparser.WithPathRemapper(gocovparser.NewPathRemapper(rules...)))
+	}
+
+	items, err := gocovparser.ParseFileContext(ctx, config.Coverage, parseOpts...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	filters := make([]gocovparser.Filter, 0, len(config.Exclude))
+	for _, pattern := range config.Exclude {
+		filters = append(filters, gocovparser.GlobExcludeFilter(pattern))
+	}
+
+	items, err = gocovparser.FilterCoverage(items, filters...)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to exclude files")
+	}
+
+	groups := []gocovparser.ParseGroup{gocovparser.TotalParseGroup}
+
+	for _, group := range config.Groups {
+		parseGroup, err := group.parseGroup()
+		if err != nil {
+			return Result{}, err
+		}
+
+		groups = append(groups, parseGroup)
+	}
+
+	grouped, err := gocovparser.GroupCoverageContext(ctx, items, groups...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	details, err := gocovparser.GroupCoverageDetailed(items, groups)
+	if err != nil {
+		return Result{}, err
+	}
+
+	policy := gocovparser.Policy{}
+	for _, threshold := range config.Thresholds {
+		policy.Rules = append(policy.Rules, gocovparser.PolicyRule{
+			Group:         threshold.Group,
+			Key:           threshold.Key,
+			Minimum:       threshold.Minimum / percentScale,
+			MaxUncovered:  threshold.MaxUncovered,
+			MinStatements: threshold.MinStatements,
+		})
+	}
+
+	for _, target := range config.Exports {
+		if err := writeExport(ctx, target, items); err != nil {
+			return Result{}, err
+		}
+	}
+
+	return Result{
+		Coverage:   items,
+		Groups:     grouped,
+		Details:    details,
+		Violations: gocovparser.CheckPolicyDetailed(details, policy),
+	}, nil
+}
+
+func writeExport(ctx context.Context, target Export, items []gocovparser.Coverage) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "export canceled")
+	}
+
+	exporter, err := export.Lookup(target.Format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(target.Output)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create export %q", target.Output)
+	}
+
+	if err := exporter.Write(file, items, target.Options); err != nil {
+		file.Close()
+
+		return errors.Wrapf(err, "failed to write export %q", target.Output)
+	}
+
+	return errors.Wrapf(file.Close(), "failed to close export %q", target.Output)
+}
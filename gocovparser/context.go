@@ -0,0 +1,113 @@
+package gocovparser
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// groupContextChunk is the number of items grouped between checks of the context.
+const groupContextChunk = 1024
+
+// contextReader fails reads once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+// contextWriter fails writes once its context is done.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return w.w.Write(p)
+}
+
+// NewContextWriter returns a writer that writes to w until ctx is done, failing later writes with the context
+// error, so long running exports and reports stop writing once cancelled.
+func NewContextWriter(ctx context.Context, w io.Writer) io.Writer {
+	return contextWriter{ctx: ctx, w: w}
+}
+
+// ParseContext parses coverage data as Parse does, aborting with the context error once ctx is done.
+func ParseContext(ctx context.Context, coverageData string, opts ...ParseOption) ([]Coverage, error) {
+	return ParseReaderContext(ctx, strings.NewReader(coverageData), opts...)
+}
+
+// ParseFileContext parses the coverage file at path as ParseFile does, aborting once ctx is done.
+func ParseFileContext(ctx context.Context, path string, opts ...ParseOption) ([]Coverage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open coverage file %q", path)
+	}
+	defer file.Close()
+
+	return ParseReaderContext(ctx, file, opts...)
+}
+
+// ParseCovDataDirContext parses the binary coverage data under dir as ParseCovDataDir does, aborting with the
+// context error once ctx is done. The context is checked before decoding every file.
+func ParseCovDataDirContext(ctx context.Context, dir string, opts ...ParseOption) ([]Coverage, error) {
+	profile, err := covDataProfile(ctx, dir)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, errors.Wrap(ctxErr, "parsing coverage aborted")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseReaderContext(ctx, strings.NewReader(profile), opts...)
+}
+
+// ParseReaderContext parses coverage data as ParseReader does, aborting with the context error once ctx is done.
+// The context is checked before every read, so a cancelled parse stops reading r.
+func ParseReaderContext(ctx context.Context, r io.Reader, opts ...ParseOption) ([]Coverage, error) {
+	coverage, err := ParseReader(contextReader{ctx: ctx, r: r}, append([]ParseOption{withContext(ctx)}, opts...)...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, errors.Wrap(ctxErr, "parsing coverage aborted")
+	}
+
+	return coverage, err
+}
+
+// GroupCoverageContext groups coverage as GroupCoverage does, aborting with the context error once ctx is done.
+func GroupCoverageContext(ctx context.Context, items []Coverage, groups ...ParseGroup) (ParseGroupResult, error) {
+	totals := groupTotalsOf(nil, groups)
+
+	for start := 0; start < len(items); start += groupContextChunk {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "grouping coverage aborted")
+		}
+
+		end := start + groupContextChunk
+		if end > len(items) {
+			end = len(items)
+		}
+
+		totals.add(groupTotalsOf(items[start:end], groups))
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "grouping coverage aborted")
+	}
+
+	return totals.result(), nil
+}
@@ -0,0 +1,77 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
+
+func TestParseContext(t *testing.T) {
+	expected, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ParseContext(context.Background(), CoverageFixture7(t))
+	_, cancelledErr := gocovparser.ParseContext(cancelledContext(), CoverageFixture7(t))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+}
+
+func TestParseFileContext(t *testing.T) {
+	// ACT
+	got, err := gocovparser.ParseFileContext(context.Background(), "coverage-fixture4.out")
+	_, cancelledErr := gocovparser.ParseFileContext(cancelledContext(), "coverage-fixture4.out")
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got, 28)
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+}
+
+func TestNewContextWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	// ACT
+	_, err := gocovparser.NewContextWriter(context.Background(), &buf).Write([]byte("data"))
+	_, cancelledErr := gocovparser.NewContextWriter(cancelledContext(), &buf).Write([]byte("more"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "data", buf.String())
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+}
+
+func TestGroupCoverageContext(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	expected, err := gocovparser.GroupCoverage(items, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverageContext(
+		context.Background(), items, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup,
+	)
+	_, cancelledErr := gocovparser.GroupCoverageContext(cancelledContext(), items, gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+}
@@ -1,6 +1,9 @@
 package gocovparser
 
 import (
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -23,90 +26,325 @@ var parseLineRegex = regexp.MustCompile(
 )
 
 // Parse a coverage result file contents from go tests.
-func Parse(coverageData string) ([]Coverage, error) {
-	// Remove empty blank lines
-	coverageData = strings.TrimSpace(coverageData)
+func Parse(coverageData string, opts ...ParseOption) ([]Coverage, error) {
+	return ParseReader(strings.NewReader(coverageData), opts...)
+}
 
-	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(coverageData))
+// ParseFile parses the coverage file at path, streaming its contents instead of loading it in memory.
+func ParseFile(path string, opts ...ParseOption) ([]Coverage, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, errors.Wrapf(ErrInvalidCoverageData, err.Error())
+		return nil, errors.Wrapf(err, "failed to open coverage file %q", path)
 	}
+	defer file.Close()
+
+	return ParseReader(file, opts...)
+}
+
+// ParseReader parses coverage data from go tests as it is read from r. Items are sorted by file name and their
+// blocks by position, so the result does not depend on the order of the lines in the profile. Concatenated
+// profiles are merged, in set mode if any of them is in set mode.
+// Malformed data fails with ErrMalformedLine or ErrUnparsableFileName, unless parsing with WithLenientParsing.
+func ParseReader(r io.Reader, opts ...ParseOption) ([]Coverage, error) {
+	options, err := newParseOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, operation := options.telemetry.Start(options.ctx, SpanParse)
 
-	if len(profiles) == 0 {
-		return []Coverage{}, nil
+	options.tracker = newProgressTracker(OperationParse, options.progress)
+	r = options.tracker.reader(r)
+
+	var items []Coverage
+
+	if options.cache != nil {
+		items, err = parseCached(r, options)
+	} else {
+		items, err = parseWithOptions(r, options)
+	}
+
+	progress := options.tracker.done(len(items))
+	attrs := []any{"files", progress.Files, "bytes", progress.Bytes, "duration", progress.Elapsed}
+
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+
+	options.debug("parsed coverage", attrs...)
+	operation.End(
+		err, Attribute{Key: AttributeFiles, Value: progress.Files}, Attribute{Key: AttributeBytes, Value: progress.Bytes},
+	)
+
+	return items, err
+}
+
+func parseWithOptions(r io.Reader, options parseOptions) ([]Coverage, error) {
+	profiles, skipped, err := readProfiles(r, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.remapper != nil {
+		profiles, err = remapProfiles(profiles, options.remapper, options.mergeStrategy)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	coverage := make([]Coverage, 0, len(profiles))
 
 	for _, profile := range profiles {
-		match := parseLineRegex.FindStringSubmatch(profile.FileName)
-		if len(match) == 0 {
-			return nil, errors.Wrapf(ErrInvalidCoverageData, "invalid coverage file name %q", profile.FileName)
+		location, ok := splitFileName(profile.FileName, options.modules)
+		if !ok && options.lenientPaths {
+			location, ok = fileLocation{path: profile.FileName}, true
+		}
+
+		if !ok {
+			if !options.lenient {
+				return nil, ErrUnparsableFileName{FileName: profile.FileName}
+			}
+
+			skipped = append(skipped, ErrUnparsableFileName{FileName: profile.FileName})
+
+			continue
 		}
 
-		host := match[hostPosition]
-		owner := match[ownerPosition]
-		repo := match[repoPosition]
-		path := match[pathPosition]
+		if options.normalize {
+			profile.Blocks = normalizeBlocks(profile.Blocks, profile.Mode, options.mergeStrategy)
+		}
+
+		localPath := ""
+		if dir, found := options.moduleDirs[location.module]; found {
+			localPath = filepath.Join(dir, filepath.FromSlash(location.path))
+		}
 
 		coverage = append(coverage, Coverage{
-			FileName: profile.FileName,
-			Host:     host,
-			Owner:    owner,
-			Repo:     repo,
-			Path:     path,
-			Blocks:   profile.Blocks,
+			FileName:  profile.FileName,
+			Host:      location.host,
+			Owner:     location.owner,
+			Repo:      location.repo,
+			Path:      location.path,
+			Module:    location.module,
+			LocalPath: localPath,
+			Blocks:    profile.Blocks,
+			Mode:      profile.Mode,
 		})
+
+		options.tracker.file()
+	}
+
+	if options.ignoreRoot != "" {
+		coverage, err = ApplyIgnoreDirectives(coverage, options.ignoreRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.classify {
+		coverage, err = ClassifyCoverage(coverage, options.classifyRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(skipped) > 0 {
+		return coverage, skipped
 	}
 
 	return coverage, nil
 }
 
-// GroupCoverage in the specified groups.
+// GroupCoverage in the specified groups. Use ParseGroupResult.Format to round or scale the result.
 func GroupCoverage(items []Coverage, groups ...ParseGroup) (ParseGroupResult, error) {
-	result := make(map[string]map[string]float64)
+	return groupTotalsOf(items, groups).result(), nil
+}
 
-	statements := make(map[string]map[string]int)
-	covered := make(map[string]map[string]int)
+// GroupCoverageDetailed groups coverage as GroupCoverage does, keeping the statement and line counts of each key
+// so reports can show absolute numbers (e.g. "123/150 statements") or weight keys by their size. The percentages
+// are rounded and scaled with the options.
+func GroupCoverageDetailed(
+	items []Coverage, groups []ParseGroup, opts ...PercentOption,
+) (DetailedGroupResult, error) {
+	options := newPercentOptions(opts)
+	result := make(DetailedGroupResult, len(groups))
 
 	for _, group := range groups {
 		if _, found := result[group.Name]; !found {
-			result[group.Name] = make(map[string]float64)
+			result[group.Name] = make(map[string]GroupStats)
 		}
+	}
 
-		if _, found := statements[group.Name]; !found {
-			statements[group.Name] = make(map[string]int)
-		}
+	lines := GetLineCoverage(items)
+	countedLines := make(map[string]bool, len(items))
 
-		if _, found := covered[group.Name]; !found {
-			covered[group.Name] = make(map[string]int)
-		}
+	for _, cov := range items {
+		covered, total := statementTotals(cov.Blocks)
+		lineCount, coveredLines := 0, 0
 
-		for _, cov := range items {
-			key := group.KeyFunc(cov.FileName)
+		// files split in several items have their lines counted once, with the first item
+		if !countedLines[cov.FileName] {
+			countedLines[cov.FileName] = true
 
-			for _, b := range cov.Blocks {
-				statements[group.Name][key] += b.NumStmt
+			for _, line := range lines[cov.FileName] {
+				lineCount++
 
-				if b.Count > 0 { // is covered
-					covered[group.Name][key] += b.NumStmt
+				if line.Status != LineUncovered {
+					coveredLines++
 				}
 			}
 		}
 
-		for key := range statements[group.Name] {
-			stmts := statements[group.Name][key]
-			cov := covered[group.Name][key]
+		for _, group := range groups {
+			key := group.Key(cov)
+			stats := result[group.Name][key]
+			stats.Statements += total
+			stats.Covered += covered
+			stats.Lines += lineCount
+			stats.CoveredLines += coveredLines
+			result[group.Name][key] = stats
+		}
+	}
+
+	for _, keys := range result {
+		for key, stats := range keys {
+			stats.Percent = options.percentOf(stats.Covered, stats.Statements)
+			keys[key] = stats
+		}
+	}
 
-			if stmts == 0 {
-				result[group.Name][key] = 0.0
+	return result, nil
+}
 
-				continue
+// groupTotals holds the statement totals of each key in each group.
+type groupTotals map[string]map[string]GroupDetail
+
+// groupTotalsOf sums the statements of items in a single pass, computing each item totals only once.
+func groupTotalsOf(items []Coverage, groups []ParseGroup) groupTotals {
+	totals := make(groupTotals, len(groups))
+
+	// keys holds the totals of each group by index, so the group maps are not looked up for every item
+	keys := make([]map[string]GroupDetail, len(groups))
+
+	for index, group := range groups {
+		if _, found := totals[group.Name]; !found {
+			totals[group.Name] = make(map[string]GroupDetail)
+		}
+
+		keys[index] = totals[group.Name]
+	}
+
+	for _, cov := range items {
+		covered, total := statementTotals(cov.Blocks)
+
+		for index, group := range groups {
+			key := group.Key(cov)
+			detail := keys[index][key]
+			detail.Covered += covered
+			detail.Total += total
+			keys[index][key] = detail
+		}
+	}
+
+	return totals
+}
+
+// add sums other into totals.
+func (totals groupTotals) add(other groupTotals) {
+	for name, keys := range other {
+		if _, found := totals[name]; !found {
+			totals[name] = make(map[string]GroupDetail, len(keys))
+		}
+
+		for key, detail := range keys {
+			sum := totals[name][key]
+			sum.Covered += detail.Covered
+			sum.Total += detail.Total
+			totals[name][key] = sum
+		}
+	}
+}
+
+func (totals groupTotals) result() ParseGroupResult {
+	result := make(ParseGroupResult, len(totals))
+
+	for name, keys := range totals {
+		result[name] = make(map[string]float64, len(keys))
+
+		for key, detail := range keys {
+			result[name][key] = percentOf(detail.Covered, detail.Total)
+		}
+	}
+
+	return result
+}
+
+// GroupBy groups coverage in buckets using the key returned by keyFn for each item.
+func GroupBy(items []Coverage, keyFn func(Coverage) string, opts ...PercentOption) map[string]GroupDetail {
+	options := newPercentOptions(opts)
+	result := make(map[string]GroupDetail)
+
+	for _, cov := range items {
+		key := keyFn(cov)
+		covered, total := statementTotals(cov.Blocks)
+		detail := result[key]
+		detail.Covered += covered
+		detail.Total += total
+		result[key] = detail
+	}
+
+	for key, detail := range result {
+		detail.Percent = options.percentOf(detail.Covered, detail.Total)
+		result[key] = detail
+	}
+
+	return result
+}
+
+// GetTotalCoverageBreakdown aggregates all coverage items into a single breakdown.
+func GetTotalCoverageBreakdown(items []Coverage, opts ...PercentOption) OverallCoverageBreakdown {
+	breakdown := OverallCoverageBreakdown{
+		Files: len(items),
+	}
+
+	for _, cov := range items {
+		breakdown.ExcludedStatements += cov.ExcludedStatements
+
+		for _, b := range cov.Blocks {
+			breakdown.Blocks++
+			breakdown.Statements += b.NumStmt
+
+			if b.Count > 0 { // is covered
+				breakdown.CoveredBlocks++
+				breakdown.CoveredStatements += b.NumStmt
 			}
+		}
+	}
+
+	breakdown.Coverage = newPercentOptions(opts).percentOf(breakdown.CoveredStatements, breakdown.Statements)
+
+	return breakdown
+}
+
+// statementTotals returns the number of covered and total statements in blocks.
+func statementTotals(blocks []cover.ProfileBlock) (int, int) {
+	covered, total := 0, 0
 
-			result[group.Name][key] = float64(cov) / float64(stmts)
+	for _, b := range blocks {
+		total += b.NumStmt
+
+		if b.Count > 0 { // is covered
+			covered += b.NumStmt
 		}
 	}
 
-	return result, nil
+	return covered, total
+}
+
+func percentOf(covered, total int) float64 {
+	if total == 0 {
+		return 0.0
+	}
+
+	return float64(covered) / float64(total)
 }
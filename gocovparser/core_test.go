@@ -3,10 +3,13 @@ package gocovparser_test
 //revive:disable:add-constant
 
 import (
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/heynemann/go-cov-parser/gocovparser"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCanGroupCoverageData(t *testing.T) {
@@ -495,3 +498,167 @@ github.com/heynemann/go-cov-parser/gocovparser/core.go:invalid`,
 		})
 	}
 }
+
+func TestCanGroupByArbitraryKey(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return cov.Owner
+	})
+
+	// ASSERT
+	require.Len(t, got, 1)
+	require.Contains(t, got, "engineering")
+
+	detail := got["engineering"]
+	assert.Equal(t, 344, detail.Total)
+	assert.Equal(t, 238, detail.Covered)
+	assert.EqualValues(t, 0.6918604651162791, detail.Percent)
+}
+
+func TestGroupByReturnsZeroPercentForEmptyBuckets(t *testing.T) {
+	items := []gocovparser.Coverage{
+		{FileName: "github.com/heynemann/go-cov-parser/gocovparser/core.go"},
+	}
+
+	// ACT
+	got := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return cov.FileName
+	})
+
+	// ASSERT
+	require.Contains(t, got, "github.com/heynemann/go-cov-parser/gocovparser/core.go")
+	assert.Equal(t, gocovparser.GroupDetail{}, got["github.com/heynemann/go-cov-parser/gocovparser/core.go"])
+}
+
+func TestCanParseCoverageFile(t *testing.T) {
+	// ACT
+	got, err := gocovparser.ParseFile("./coverage-fixture4.out")
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, got)
+}
+
+func TestParseFileFailsIfFileDoesNotExist(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseFile("./does-not-exist.out")
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCanParseCoverageReaderWithBlankLines(t *testing.T) {
+	data := "\n\nmode: set\n" +
+		"github.com/heynemann/go-cov-parser/gocovparser/core.go:38.53,42.2 2 1\n" +
+		"\n" +
+		"github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 2 0" // no trailing newline
+
+	// ACT
+	got, err := gocovparser.ParseReader(strings.NewReader(data))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Len(t, got[0].Blocks, 2)
+}
+
+func TestCanGetTotalCoverageBreakdown(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetTotalCoverageBreakdown(items)
+
+	// ASSERT
+	assert.Equal(t, 1, got.Files)
+	assert.Equal(t, 43, got.Blocks)
+	assert.EqualValues(t, 0.8823529411764706, got.Coverage)
+	assert.Equal(t, 68, got.Statements)
+	assert.Equal(t, 60, got.CoveredStatements)
+}
+
+func TestTotalCoverageBreakdownOfEmptyCoverageIsZero(t *testing.T) {
+	// ACT
+	got := gocovparser.GetTotalCoverageBreakdown(nil)
+
+	// ASSERT
+	assert.Equal(t, gocovparser.OverallCoverageBreakdown{}, got)
+}
+
+func TestParseExposesCoverageMode(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse("mode: atomic\ngithub.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 7\n")
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, gocovparser.ModeAtomic, got[0].Mode)
+}
+
+func TestGroupCoverageDetailed(t *testing.T) {
+	items, err := gocovparser.Parse(`mode: set
+github.com/owner/repo/pkg/a.go:3.10,5.2 2 1
+github.com/owner/repo/pkg/a.go:5.2,9.2 1 0
+github.com/owner/repo/other/b.go:1.10,2.2 3 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverageDetailed(
+		items, []gocovparser.ParseGroup{gocovparser.PackageParseGroup, gocovparser.TotalParseGroup},
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.GroupStats{
+		Statements: 3, Covered: 2, Lines: 7, CoveredLines: 3, Percent: 2.0 / 3,
+	}, got["package"]["github.com/owner/repo/pkg"])
+	assert.Equal(t, gocovparser.GroupStats{
+		Statements: 6, Covered: 5, Lines: 9, CoveredLines: 5, Percent: 5.0 / 6,
+	}, got["total"]["total"])
+}
+
+func TestGroupCoverageDetailedFormatsPercentages(t *testing.T) {
+	items, err := gocovparser.Parse(`mode: set
+github.com/owner/repo/pkg/a.go:3.10,5.2 2 1
+github.com/owner/repo/pkg/a.go:5.2,9.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverageDetailed(
+		items, []gocovparser.ParseGroup{gocovparser.TotalParseGroup},
+		gocovparser.WithRounding(gocovparser.RoundNearest, 1), gocovparser.WithPercentScale(),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, 66.7, got["total"]["total"].Percent)
+}
+
+func TestGroupCoverageDetailedMatchesGroupCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	expected, err := gocovparser.GroupCoverage(items, gocovparser.PackageParseGroup)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverageDetailed(items, []gocovparser.ParseGroup{gocovparser.PackageParseGroup})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got["package"], len(expected["package"]))
+
+	for key, value := range expected["package"] {
+		assert.InDelta(t, value, got["package"][key].Percent, 1e-9, key)
+	}
+}
@@ -0,0 +1,501 @@
+package gocovparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	covMetaHeaderSize    = 56
+	covMetaPackageSize   = 44
+	covCounterHeaderSize = 32
+	covCounterFooterSize = 16
+	covCounterFlavorRaw  = 1
+	covCounterFlavorULEB = 2
+	covCounterAlignment  = 4
+)
+
+var (
+	covMetaMagic    = []byte{0, 'c', 'v', 'm'}
+	covCounterMagic = []byte{0, 'c', 'w', 'm'}
+
+	covMetaFileRegex    = regexp.MustCompile(`^covmeta\.(\S+)$`)
+	covCounterFileRegex = regexp.MustCompile(`^covcounters\.(\S+)\.(\d+)\.(\d+)+$`)
+
+	covModes = map[uint8]string{1: "set", 2: "count", 3: "atomic"}
+)
+
+// ParseCovDataDir parses the binary coverage data (covmeta/covcounters files) written under a GOCOVERDIR
+// by binaries built with `go build -cover` (Go 1.20+).
+// The files are decoded directly, so the go toolchain is not needed. Counters of every run are merged, as
+// `go tool covdata textfmt` does, and the result is parsed with opts as a text profile.
+func ParseCovDataDir(dir string, opts ...ParseOption) ([]Coverage, error) {
+	profile, err := covDataProfile(context.Background(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseReader(strings.NewReader(profile), opts...)
+}
+
+// covUnit is a coverable unit of a function, as recorded in a meta-data file.
+type covUnit struct {
+	startLine, startCol, endLine, endCol, statements uint32
+}
+
+// covFunc is a function of a package recorded in a meta-data file.
+type covFunc struct {
+	file  string
+	units []covUnit
+}
+
+// covMeta is a decoded meta-data file, with the functions of each of its packages.
+type covMeta struct {
+	mode     string
+	packages [][]covFunc
+}
+
+// covFuncKey identifies a function by its package and function indices in a meta-data file.
+type covFuncKey struct {
+	pkg, fn uint32
+}
+
+// covBlock identifies a unit of a source file in the text profile.
+type covBlock struct {
+	file string
+	unit covUnit
+}
+
+// covDataProfile decodes the coverage data under dir into a text profile, checking ctx before reading every file.
+func covDataProfile(ctx context.Context, dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidCoverageData, "failed to read coverage data in %q: %s", dir, err.Error())
+	}
+
+	metas := map[string]string{}
+	counters := map[string][]string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if match := covMetaFileRegex.FindStringSubmatch(entry.Name()); match != nil {
+			metas[match[1]] = filepath.Join(dir, entry.Name())
+		} else if match := covCounterFileRegex.FindStringSubmatch(entry.Name()); match != nil {
+			counters[match[1]] = append(counters[match[1]], filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	if len(metas) == 0 {
+		return "", errors.Wrapf(ErrInvalidCoverageData, "no coverage meta-data files found in %q", dir)
+	}
+
+	mode := ""
+	blocks := map[covBlock]uint32{}
+
+	for hash, path := range metas {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		meta, err := readCovMeta(path)
+		if err != nil {
+			return "", err
+		}
+
+		if mode != "" && mode != meta.mode {
+			return "", errors.Wrapf(
+				ErrInvalidCoverageData, "meta-data file %q is in %s mode, other files are in %s mode", path, meta.mode, mode,
+			)
+		}
+
+		mode = meta.mode
+
+		counts := map[covFuncKey][]uint32{}
+
+		for _, counterPath := range counters[hash] {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+
+			if err := readCovCounters(counterPath, mode, counts); err != nil {
+				return "", err
+			}
+		}
+
+		addCovBlocks(blocks, meta, counts)
+	}
+
+	return formatCovProfile(mode, blocks), nil
+}
+
+// addCovBlocks adds the units of every function of meta to blocks, with their merged counts.
+func addCovBlocks(blocks map[covBlock]uint32, meta covMeta, counts map[covFuncKey][]uint32) {
+	for pkg, funcs := range meta.packages {
+		for fn, desc := range funcs {
+			values := counts[covFuncKey{pkg: uint32(pkg), fn: uint32(fn)}]
+
+			for i, unit := range desc.units {
+				var count uint32
+				if i < len(values) {
+					count = values[i]
+				}
+
+				block := covBlock{file: desc.file, unit: unit}
+				blocks[block] = mergeCovCount(meta.mode, blocks[block], count)
+			}
+		}
+	}
+}
+
+// mergeCovCount merges two counts of the same unit: set mode keeps whether it ran, other modes add the counts.
+func mergeCovCount(mode string, a, b uint32) uint32 {
+	if mode == "set" {
+		if a != 0 || b != 0 {
+			return 1
+		}
+
+		return 0
+	}
+
+	if sum := a + b; sum >= a {
+		return sum
+	}
+
+	return ^uint32(0)
+}
+
+// formatCovProfile writes blocks as a text profile, sorted by file and position.
+func formatCovProfile(mode string, blocks map[covBlock]uint32) string {
+	sorted := make([]covBlock, 0, len(blocks))
+	for block := range blocks {
+		sorted = append(sorted, block)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.file != b.file {
+			return a.file < b.file
+		}
+
+		if a.unit.startLine != b.unit.startLine {
+			return a.unit.startLine < b.unit.startLine
+		}
+
+		return a.unit.startCol < b.unit.startCol
+	})
+
+	var profile strings.Builder
+
+	fmt.Fprintf(&profile, "mode: %s\n", mode)
+
+	for _, block := range sorted {
+		fmt.Fprintf(
+			&profile, "%s:%d.%d,%d.%d %d %d\n", block.file, block.unit.startLine, block.unit.startCol,
+			block.unit.endLine, block.unit.endCol, block.unit.statements, blocks[block],
+		)
+	}
+
+	return profile.String()
+}
+
+// readCovMeta decodes the meta-data file at path.
+func readCovMeta(path string) (covMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return covMeta{}, errors.Wrapf(err, "failed to read coverage meta-data file %q", path)
+	}
+
+	r := covDataReader{data: data}
+
+	magic := r.bytes(len(covMetaMagic))
+	r.seek(16)
+	entries := r.u64(binary.LittleEndian)
+	r.seek(48)
+	mode, known := covModes[r.u8()]
+
+	if r.err == nil && !bytes.Equal(magic, covMetaMagic) {
+		return covMeta{}, errors.Wrapf(ErrInvalidCoverageData, "%q is not a coverage meta-data file", path)
+	}
+
+	if r.err == nil && !known {
+		return covMeta{}, errors.Wrapf(ErrInvalidCoverageData, "unsupported counter mode in %q", path)
+	}
+
+	r.seek(covMetaHeaderSize)
+
+	if entries > uint64(len(data)) {
+		r.fail()
+
+		entries = 0
+	}
+
+	offsets := make([]uint64, 0, entries)
+	for i := uint64(0); i < entries && r.err == nil; i++ {
+		offsets = append(offsets, r.u64(binary.LittleEndian))
+	}
+
+	meta := covMeta{mode: mode}
+
+	for _, offset := range offsets {
+		length := r.u64(binary.LittleEndian)
+		if r.err != nil || offset > uint64(len(data)) || length > uint64(len(data))-offset {
+			r.fail()
+
+			break
+		}
+
+		funcs, err := readCovMetaPackage(data[offset : offset+length])
+		if err != nil {
+			r.err = err
+
+			break
+		}
+
+		meta.packages = append(meta.packages, funcs)
+	}
+
+	if r.err != nil {
+		return covMeta{}, errors.Wrapf(r.err, "failed to decode coverage meta-data file %q", path)
+	}
+
+	return meta, nil
+}
+
+// readCovMetaPackage decodes the functions of a package payload of a meta-data file.
+func readCovMetaPackage(data []byte) ([]covFunc, error) {
+	r := covDataReader{data: data}
+
+	r.seek(covMetaPackageSize - 4)
+	numFuncs := r.u32(binary.LittleEndian)
+
+	if uint64(numFuncs) > uint64(len(data)) {
+		r.fail()
+
+		numFuncs = 0
+	}
+
+	offsets := make([]uint32, 0, numFuncs)
+	for i := uint32(0); i < numFuncs && r.err == nil; i++ {
+		offsets = append(offsets, r.u32(binary.LittleEndian))
+	}
+
+	table := r.strings()
+	funcs := make([]covFunc, 0, len(offsets))
+
+	for _, offset := range offsets {
+		r.seek(int(offset))
+
+		numUnits := r.uleb()
+		r.uleb() // function name
+		file := r.uleb()
+
+		if r.err != nil || file >= uint32(len(table)) || uint64(numUnits) > uint64(len(data)) {
+			r.fail()
+
+			break
+		}
+
+		desc := covFunc{file: table[file], units: make([]covUnit, 0, numUnits)}
+
+		for i := uint32(0); i < numUnits; i++ {
+			desc.units = append(desc.units, covUnit{
+				startLine: r.uleb(), startCol: r.uleb(), endLine: r.uleb(), endCol: r.uleb(), statements: r.uleb(),
+			})
+		}
+
+		funcs = append(funcs, desc)
+	}
+
+	return funcs, r.err
+}
+
+// readCovCounters decodes the counter data file at path, merging its counters into counts.
+func readCovCounters(path, mode string, counts map[covFuncKey][]uint32) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read coverage counter file %q", path)
+	}
+
+	r := covDataReader{data: data}
+
+	magic := r.bytes(len(covCounterMagic))
+	r.seek(24)
+	flavor := r.u8()
+	bigEndian := r.u8() != 0
+
+	r.seek(len(data) - covCounterFooterSize)
+	footerMagic := r.bytes(len(covCounterMagic))
+	r.seek(len(data) - covCounterFooterSize + 8)
+	segments := r.u32(binary.LittleEndian)
+
+	if r.err == nil && (!bytes.Equal(magic, covCounterMagic) || !bytes.Equal(footerMagic, covCounterMagic)) {
+		return errors.Wrapf(ErrInvalidCoverageData, "%q is not a coverage counter file", path)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+
+	read := func() uint32 { return r.u32(order) }
+
+	switch {
+	case flavor == covCounterFlavorULEB:
+		read = r.uleb
+	case flavor != covCounterFlavorRaw && r.err == nil:
+		return errors.Wrapf(ErrInvalidCoverageData, "unsupported counter flavor in %q", path)
+	}
+
+	r.seek(covCounterHeaderSize)
+
+	for segment := uint32(0); segment < segments && r.err == nil; segment++ {
+		if segment > 0 {
+			r.seek(r.offset + covCounterFooterSize)
+		}
+
+		entries := r.u64(binary.LittleEndian)
+		tableLength := r.u32(binary.LittleEndian)
+		argsLength := r.u32(binary.LittleEndian)
+		r.seek(r.offset + int(tableLength) + int(argsLength))
+
+		if rem := r.offset % covCounterAlignment; rem != 0 {
+			r.seek(r.offset + covCounterAlignment - rem)
+		}
+
+		for entry := uint64(0); entry < entries && r.err == nil; entry++ {
+			numCounters := read()
+			key := covFuncKey{pkg: read(), fn: read()}
+
+			if uint64(numCounters) > uint64(len(data)) {
+				r.fail()
+
+				break
+			}
+
+			values := counts[key]
+			for i := uint32(0); i < numCounters; i++ {
+				if int(i) == len(values) {
+					values = append(values, 0)
+				}
+
+				values[i] = mergeCovCount(mode, values[i], read())
+			}
+
+			counts[key] = values
+		}
+	}
+
+	if r.err != nil {
+		return errors.Wrapf(r.err, "failed to decode coverage counter file %q", path)
+	}
+
+	return nil
+}
+
+// covDataReader reads the binary coverage data files, failing with ErrInvalidCoverageData once the data runs out.
+type covDataReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *covDataReader) fail() {
+	if r.err == nil {
+		r.err = errors.Wrap(ErrInvalidCoverageData, "truncated or malformed coverage data")
+	}
+}
+
+func (r *covDataReader) seek(offset int) {
+	if offset < 0 || offset > len(r.data) {
+		r.fail()
+
+		return
+	}
+
+	r.offset = offset
+}
+
+func (r *covDataReader) bytes(n int) []byte {
+	if r.err != nil || n > len(r.data)-r.offset {
+		r.fail()
+
+		return nil
+	}
+
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+
+	return b
+}
+
+func (r *covDataReader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+
+	return 0
+}
+
+func (r *covDataReader) u32(order binary.ByteOrder) uint32 {
+	if b := r.bytes(4); b != nil {
+		return order.Uint32(b)
+	}
+
+	return 0
+}
+
+func (r *covDataReader) u64(order binary.ByteOrder) uint64 {
+	if b := r.bytes(8); b != nil {
+		return order.Uint64(b)
+	}
+
+	return 0
+}
+
+func (r *covDataReader) uleb() uint32 {
+	var value uint64
+
+	for shift := uint(0); ; shift += 7 {
+		b := r.bytes(1)
+		if b == nil || shift > 63 {
+			r.fail()
+
+			return 0
+		}
+
+		value |= uint64(b[0]&0x7F) << shift
+
+		if b[0]&0x80 == 0 {
+			return uint32(value)
+		}
+	}
+}
+
+// strings reads a string table: its number of strings, followed by the length and bytes of each string.
+func (r *covDataReader) strings() []string {
+	count := r.uleb()
+	if uint64(count) > uint64(len(r.data)) {
+		r.fail()
+
+		return nil
+	}
+
+	values := make([]string, 0, count)
+	for i := uint32(0); i < count && r.err == nil; i++ {
+		values = append(values, string(r.bytes(int(r.uleb()))))
+	}
+
+	return values
+}
@@ -0,0 +1,131 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCoverBinary builds a small program with -covermode=mode and runs it times times, returning its GOCOVERDIR.
+func runCoverBinary(t *testing.T, mode string, times int) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain is not available")
+	}
+
+	root := t.TempDir()
+	coverDir := filepath.Join(root, "covdata")
+	binary := filepath.Join(root, "app")
+
+	require.NoError(t, os.Mkdir(coverDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.20\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte(`package main
+
+func main() {
+	if len("a") > 5 {
+		println("unreachable")
+	}
+}
+`), 0o600))
+
+	build := exec.Command("go", "build", "-cover", "-covermode="+mode, "-o", binary, ".")
+	build.Dir = root
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	for i := 0; i < times; i++ {
+		app := exec.Command(binary)
+		app.Env = append(os.Environ(), "GOCOVERDIR="+coverDir)
+		out, err = app.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	return coverDir
+}
+
+func TestCanParseCovDataDir(t *testing.T) {
+	coverDir := runCoverBinary(t, "set", 1)
+
+	// ACT
+	got, err := gocovparser.ParseCovDataDir(coverDir, gocovparser.WithModulePaths("example.com/app"))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "example.com/app/main.go", got[0].FileName)
+	assert.Equal(t, "main.go", got[0].Path)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(got)
+	assert.Equal(t, 2, breakdown.Statements)
+	assert.Equal(t, 1, breakdown.CoveredStatements)
+}
+
+func TestParseCovDataDirMergesCountersOfEveryRun(t *testing.T) {
+	coverDir := runCoverBinary(t, "count", 3)
+
+	// ACT
+	got, err := gocovparser.ParseCovDataDir(coverDir, gocovparser.WithModulePaths("example.com/app"))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "count", got[0].Mode)
+	require.NotEmpty(t, got[0].Blocks)
+	assert.Equal(t, 3, got[0].Blocks[0].Count)
+	assert.Equal(t, 0, got[0].Blocks[len(got[0].Blocks)-1].Count)
+}
+
+func TestParseCovDataDirContext(t *testing.T) {
+	coverDir := runCoverBinary(t, "set", 1)
+
+	// ACT
+	got, err := gocovparser.ParseCovDataDirContext(context.Background(), coverDir)
+	_, cancelledErr := gocovparser.ParseCovDataDirContext(cancelledContext(), coverDir)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.ErrorIs(t, cancelledErr, context.Canceled)
+}
+
+func TestParseCovDataDirFailsForInvalidDirectory(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseCovDataDir(filepath.Join(t.TempDir(), "missing"))
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
+
+func TestParseCovDataDirFailsForMalformedFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "covmeta.0123456789abcdef"), []byte{0, 'c', 'v', 'm', 1}, 0o600))
+
+	// ACT
+	_, err := gocovparser.ParseCovDataDir(dir)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
+
+func TestParseCovDataDirFailsWithoutMetaDataFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "coverage.out"), []byte("mode: set\n"), 0o600))
+
+	// ACT
+	_, err := gocovparser.ParseCovDataDir(dir)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
@@ -0,0 +1,117 @@
+package gocovparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// TotalStatements returns the number of statements in the file.
+func (c Coverage) TotalStatements() int {
+	_, total := statementTotals(c.Blocks)
+
+	return total
+}
+
+// CoveredStatements returns the number of statements executed at least once.
+func (c Coverage) CoveredStatements() int {
+	covered, _ := statementTotals(c.Blocks)
+
+	return covered
+}
+
+// Percent returns the ratio (0 to 1) of covered statements in the file, formatted by the options.
+func (c Coverage) Percent(opts ...PercentOption) float64 {
+	return newPercentOptions(opts).percentOf(statementTotals(c.Blocks))
+}
+
+// UncoveredRanges returns the sorted ranges of lines where no block was executed.
+// Lines partially covered by another block are not included.
+func (c Coverage) UncoveredRanges() []LineRange {
+	return uncoveredRanges(GetLineCoverage([]Coverage{c})[c.FileName])
+}
+
+// GetUncoveredRanges returns the ranges of uncovered lines of each file (by FileName), as Coverage.UncoveredRanges does.
+// Fully covered files are left out.
+func GetUncoveredRanges(items []Coverage) map[string][]LineRange {
+	result := make(map[string][]LineRange)
+
+	for fileName, lines := range GetLineCoverage(items) {
+		if ranges := uncoveredRanges(lines); len(ranges) > 0 {
+			result[fileName] = ranges
+		}
+	}
+
+	return result
+}
+
+// FormatLineRanges formats ranges for humans, e.g. "45-52, 77".
+func FormatLineRanges(ranges []LineRange) string {
+	formatted := make([]string, 0, len(ranges))
+
+	for _, r := range ranges {
+		formatted = append(formatted, r.String())
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// uncoveredRanges collapses the uncovered lines into sorted ranges of consecutive lines.
+func uncoveredRanges(lines FileLineCoverage) []LineRange {
+	uncovered := make([]int, 0, len(lines))
+
+	for line, coverage := range lines {
+		if coverage.Status == LineUncovered {
+			uncovered = append(uncovered, line)
+		}
+	}
+
+	sort.Ints(uncovered)
+
+	ranges := []LineRange{}
+
+	for _, line := range uncovered {
+		last := len(ranges) - 1
+		if last >= 0 && ranges[last].End == line-1 {
+			ranges[last].End = line
+
+			continue
+		}
+
+		ranges = append(ranges, LineRange{Start: line, End: line})
+	}
+
+	return ranges
+}
+
+// GetFileBreakdowns returns the coverage breakdown of each file, sorted by file name.
+func GetFileBreakdowns(items []Coverage, opts ...PercentOption) []FileBreakdown {
+	options := newPercentOptions(opts)
+	result := make([]FileBreakdown, 0, len(items))
+
+	for _, cov := range items {
+		breakdown := FileBreakdown{
+			FileName:           cov.FileName,
+			Path:               cov.Path,
+			Blocks:             len(cov.Blocks),
+			ExcludedStatements: cov.ExcludedStatements,
+			UncoveredRanges:    cov.UncoveredRanges(),
+		}
+
+		for _, b := range cov.Blocks {
+			if b.Count > 0 { // is covered
+				breakdown.CoveredBlocks++
+			}
+		}
+
+		breakdown.CoveredStatements, breakdown.Statements = statementTotals(cov.Blocks)
+		breakdown.Coverage = options.percentOf(breakdown.CoveredStatements, breakdown.Statements)
+
+		result = append(result, breakdown)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FileName < result[j].FileName
+	})
+
+	return result
+}
@@ -0,0 +1,87 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fileCoverageFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/pkg/b.go:3.10,4.12 1 1
+github.com/heynemann/go-cov-parser/pkg/b.go:4.12,6.3 2 0
+github.com/heynemann/go-cov-parser/pkg/b.go:7.2,9.12 3 0
+github.com/heynemann/go-cov-parser/pkg/b.go:12.2,12.10 1 0
+github.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1
+`
+
+func TestCoverageStatementMethods(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	// ACT
+	total := items[0].TotalStatements()
+	covered := items[0].CoveredStatements()
+	percent := items[0].Percent()
+
+	// ASSERT
+	assert.Equal(t, 68, total)
+	assert.Equal(t, 60, covered)
+	assert.InDelta(t, 0.8823529411764706, percent, 0.0001)
+}
+
+func TestCoverageUncoveredRanges(t *testing.T) {
+	items, err := gocovparser.Parse(fileCoverageFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := items[1].UncoveredRanges()
+
+	// ASSERT
+	assert.Equal(t, []gocovparser.LineRange{{Start: 5, End: 9}, {Start: 12, End: 12}}, got)
+	assert.Empty(t, items[0].UncoveredRanges())
+}
+
+func TestCanGetFileBreakdowns(t *testing.T) {
+	items, err := gocovparser.Parse(fileCoverageFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetFileBreakdowns(items)
+
+	// ASSERT
+	require.Len(t, got, 2)
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/pkg/a.go", got[0].FileName)
+	assert.EqualValues(t, 1, got[0].Coverage)
+
+	assert.Equal(t, gocovparser.FileBreakdown{
+		FileName:          "github.com/heynemann/go-cov-parser/pkg/b.go",
+		Path:              "pkg/b.go",
+		Blocks:            4,
+		CoveredBlocks:     1,
+		Statements:        7,
+		CoveredStatements: 1,
+		Coverage:          1.0 / 7,
+		UncoveredRanges:   []gocovparser.LineRange{{Start: 5, End: 9}, {Start: 12, End: 12}},
+	}, got[1])
+}
+
+func TestCanGetUncoveredRanges(t *testing.T) {
+	items, err := gocovparser.Parse(fileCoverageFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetUncoveredRanges(items)
+
+	// ASSERT
+	expected := map[string][]gocovparser.LineRange{
+		"github.com/heynemann/go-cov-parser/pkg/b.go": {{Start: 5, End: 9}, {Start: 12, End: 12}},
+	}
+	assert.Equal(t, expected, got)
+	assert.Equal(t, "5-9, 12", gocovparser.FormatLineRanges(got["github.com/heynemann/go-cov-parser/pkg/b.go"]))
+}
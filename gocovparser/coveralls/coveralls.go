@@ -0,0 +1,141 @@
+// Package coveralls builds and uploads Coveralls jobs from parsed coverage.
+package coveralls
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"sort"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// Job is the payload of the Coveralls jobs API.
+type Job struct {
+	RepoToken     string       `json:"repo_token,omitempty"`
+	ServiceName   string       `json:"service_name,omitempty"`
+	ServiceJobID  string       `json:"service_job_id,omitempty"`
+	ServiceNumber string       `json:"service_number,omitempty"`
+	Parallel      bool         `json:"parallel,omitempty"`
+	SourceFiles   []SourceFile `json:"source_files"`
+	Git           *Git         `json:"git,omitempty"`
+}
+
+// SourceFile is the coverage of a single source file. Coverage holds the hits of each line,
+// with nil for lines that are not relevant.
+type SourceFile struct {
+	Name         string `json:"name"`
+	SourceDigest string `json:"source_digest"`
+	Coverage     []*int `json:"coverage"`
+}
+
+// Git is the repository metadata attached to a job.
+type Git struct {
+	Head    Head     `json:"head"`
+	Branch  string   `json:"branch"`
+	Remotes []Remote `json:"remotes,omitempty"`
+}
+
+// Head is the commit the coverage was collected at.
+type Head struct {
+	ID             string `json:"id"`
+	AuthorName     string `json:"author_name"`
+	AuthorEmail    string `json:"author_email"`
+	CommitterName  string `json:"committer_name"`
+	CommitterEmail string `json:"committer_email"`
+	Message        string `json:"message"`
+}
+
+// Remote is a git remote of the repository.
+type Remote struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// JobOptions configures the job built from coverage.
+type JobOptions struct {
+	// SourceRoot is the directory the coverage Path of each file is relative to, for files without LocalPath.
+	SourceRoot string
+
+	RepoToken     string
+	ServiceName   string
+	ServiceJobID  string
+	ServiceNumber string
+	Parallel      bool
+
+	// Git metadata of the job, usually read with ReadGit.
+	Git *Git
+}
+
+// BuildJob converts coverage into a Coveralls job. Source files are read from their gocovparser.SourcePath under the
+// source root to compute their digest and line count.
+func BuildJob(items []gocovparser.Coverage, opts JobOptions) (Job, error) {
+	job := Job{
+		RepoToken:     opts.RepoToken,
+		ServiceName:   opts.ServiceName,
+		ServiceJobID:  opts.ServiceJobID,
+		ServiceNumber: opts.ServiceNumber,
+		Parallel:      opts.Parallel,
+		SourceFiles:   make([]SourceFile, 0, len(items)),
+		Git:           opts.Git,
+	}
+
+	lines := gocovparser.GetLineCoverage(items)
+
+	for _, cov := range items {
+		file, err := sourceFile(opts.SourceRoot, cov, lines[cov.FileName])
+		if err != nil {
+			return Job{}, err
+		}
+
+		job.SourceFiles = append(job.SourceFiles, file)
+	}
+
+	sort.Slice(job.SourceFiles, func(i, j int) bool {
+		return job.SourceFiles[i].Name < job.SourceFiles[j].Name
+	})
+
+	return job, nil
+}
+
+func sourceFile(root string, cov gocovparser.Coverage, lines gocovparser.FileLineCoverage) (SourceFile, error) {
+	contents, err := os.ReadFile(gocovparser.SourcePath(cov, root))
+	if err != nil {
+		return SourceFile{}, errors.Wrapf(err, "failed to read source of %q", cov.FileName)
+	}
+
+	// Coveralls identifies source files by their MD5 digest
+	digest := md5.Sum(contents)
+
+	file := SourceFile{
+		Name:         cov.Path,
+		SourceDigest: hex.EncodeToString(digest[:]),
+		Coverage:     make([]*int, lineCount(contents)),
+	}
+
+	for line, coverage := range lines {
+		if line < 1 || line > len(file.Coverage) {
+			continue
+		}
+
+		hits := coverage.Hits
+		file.Coverage[line-1] = &hits
+	}
+
+	return file, nil
+}
+
+func lineCount(contents []byte) int {
+	count := 0
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+
+	for scanner.Scan() {
+		count++
+	}
+
+	return count
+}
@@ -0,0 +1,101 @@
+package coveralls_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/coveralls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const coverallsSource = "package pkg\n\nfunc A() {\n\tprintln()\n}\n"
+
+func coverallsFixture(t *testing.T) (string, []gocovparser.Coverage) {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte(coverallsSource), 0o600))
+
+	items, err := gocovparser.Parse(`
+mode: count
+github.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 3
+`)
+	require.NoError(t, err)
+
+	return root, items
+}
+
+func TestCanBuildJob(t *testing.T) {
+	root, items := coverallsFixture(t)
+
+	// ACT
+	got, err := coveralls.BuildJob(items, coveralls.JobOptions{
+		SourceRoot:   root,
+		RepoToken:    "token",
+		ServiceName:  "github",
+		ServiceJobID: "42",
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "token", got.RepoToken)
+	assert.Equal(t, "github", got.ServiceName)
+	require.Len(t, got.SourceFiles, 1)
+
+	file := got.SourceFiles[0]
+	assert.Equal(t, "pkg/a.go", file.Name)
+	assert.Equal(t, "6daf333be46344280c5e419ada70ad99", file.SourceDigest)
+	require.Len(t, file.Coverage, 5)
+	assert.Nil(t, file.Coverage[0])
+	require.NotNil(t, file.Coverage[3])
+	assert.Equal(t, 3, *file.Coverage[3])
+}
+
+func TestBuildJobFailsIfSourceIsMissing(t *testing.T) {
+	_, items := coverallsFixture(t)
+
+	// ACT
+	_, err := coveralls.BuildJob(items, coveralls.JobOptions{SourceRoot: t.TempDir()})
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCanReadGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@test"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git("init", "-q", "-b", "main")
+	git("remote", "add", "origin", "https://github.com/heynemann/go-cov-parser.git")
+	git("commit", "-q", "--allow-empty", "-m", "initial commit")
+
+	// ACT
+	got, err := coveralls.ReadGit(repo)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got.Head.ID, 40)
+	assert.Equal(t, "test", got.Head.AuthorName)
+	assert.Equal(t, "test@test", got.Head.CommitterEmail)
+	assert.Equal(t, "initial commit", got.Head.Message)
+	assert.Equal(t, "main", got.Branch)
+	assert.Equal(t, []coveralls.Remote{{Name: "origin", URL: "https://github.com/heynemann/go-cov-parser.git"}}, got.Remotes)
+}
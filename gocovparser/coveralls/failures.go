@@ -0,0 +1,6 @@
+package coveralls
+
+import "errors"
+
+// ErrUploadFailed happens when Coveralls rejects an uploaded job.
+var ErrUploadFailed = errors.New("coveralls upload failed - job rejected")
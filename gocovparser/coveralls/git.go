@@ -0,0 +1,71 @@
+package coveralls
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// number of lines in the head commit log format.
+const headLines = 6
+
+// ReadGit reads the Git metadata of the repository at repoPath: the HEAD commit, current branch and remotes.
+func ReadGit(repoPath string) (*Git, error) {
+	log, err := runGit(repoPath, "log", "-1", "--format=%H%n%an%n%ae%n%cn%n%ce%n%s")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.SplitN(log, "\n", headLines)
+	if len(fields) < headLines {
+		return nil, errors.Errorf("unexpected git log output %q", log)
+	}
+
+	branch, err := runGit(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	git := &Git{
+		Head: Head{
+			ID:             fields[0],
+			AuthorName:     fields[1],
+			AuthorEmail:    fields[2],
+			CommitterName:  fields[3],
+			CommitterEmail: fields[4],
+			Message:        fields[5],
+		},
+		Branch: branch,
+	}
+
+	// git config exits with 1 when there are no remotes
+	remotes, _ := runGit(repoPath, "config", "--get-regexp", `^remote\..*\.url$`)
+
+	for _, line := range strings.Split(remotes, "\n") {
+		key, url, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")
+		git.Remotes = append(git.Remotes, Remote{Name: name, URL: url})
+	}
+
+	return git, nil
+}
+
+func runGit(repoPath string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", append([]string{"-C", repoPath}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to run git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,122 @@
+package coveralls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// DefaultEndpoint is the Coveralls jobs API.
+const DefaultEndpoint = "https://coveralls.io/api/v1/jobs"
+
+// maximum number of bytes of the response body included in upload errors.
+const maxErrorBody = 512
+
+// UploadOption configures how jobs are uploaded.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	endpoint  string
+	client    *http.Client
+	telemetry gocovparser.Telemetry
+}
+
+// WithEndpoint sets the URL jobs are posted to. Defaults to DefaultEndpoint.
+func WithEndpoint(endpoint string) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the client used to post jobs. Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.client = client
+	}
+}
+
+// WithTelemetry traces uploads in gocovparser.SpanUpload spans and records their metrics.
+func WithTelemetry(telemetry gocovparser.Telemetry) UploadOption {
+	return func(opts *uploadOptions) {
+		opts.telemetry = telemetry
+	}
+}
+
+// Upload posts the job to Coveralls as the json_file form field.
+func Upload(job Job, opts ...UploadOption) error {
+	return UploadContext(context.Background(), job, opts...)
+}
+
+// UploadContext posts the job as Upload does, aborting the request once ctx is done.
+func UploadContext(ctx context.Context, job Job, opts ...UploadOption) error {
+	options := uploadOptions{
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, operation := options.telemetry.Start(ctx, gocovparser.SpanUpload)
+	err := upload(ctx, job, options)
+
+	operation.End(
+		err,
+		gocovparser.Attribute{Key: gocovparser.AttributeService, Value: "coveralls"},
+		gocovparser.Attribute{Key: gocovparser.AttributeFiles, Value: len(job.SourceFiles)},
+	)
+
+	return err
+}
+
+func upload(ctx context.Context, job Job, options uploadOptions) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize coveralls job")
+	}
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	part, err := form.CreateFormFile("json_file", "coverage.json")
+	if err != nil {
+		return errors.Wrap(err, "failed to build coveralls request")
+	}
+
+	if _, err := part.Write(payload); err != nil {
+		return errors.Wrap(err, "failed to build coveralls request")
+	}
+
+	if err := form.Close(); err != nil {
+		return errors.Wrap(err, "failed to build coveralls request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, options.endpoint, &body)
+	if err != nil {
+		return errors.Wrap(err, "failed to build coveralls request")
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := options.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post coveralls job")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+		return errors.Wrapf(ErrUploadFailed, "%s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package coveralls_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/coveralls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanUploadJob(t *testing.T) {
+	var received coveralls.Job
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("json_file")
+		require.NoError(t, err)
+
+		payload, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(payload, &received))
+	}))
+	defer server.Close()
+
+	job := coveralls.Job{RepoToken: "token", SourceFiles: []coveralls.SourceFile{{Name: "pkg/a.go"}}}
+
+	// ACT
+	err := coveralls.Upload(job, coveralls.WithEndpoint(server.URL), coveralls.WithHTTPClient(server.Client()))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, job, received)
+}
+
+func TestUploadFailsIfJobIsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid repo token", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	// ACT
+	err := coveralls.Upload(coveralls.Job{}, coveralls.WithEndpoint(server.URL))
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, coveralls.ErrUploadFailed)
+	assert.Contains(t, err.Error(), "invalid repo token")
+}
+
+func TestUploadContextAbortsWhenCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// ACT
+	err := coveralls.UploadContext(ctx, coveralls.Job{}, coveralls.WithEndpoint(server.URL))
+
+	// ASSERT
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, requests)
+}
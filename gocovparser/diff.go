@@ -0,0 +1,57 @@
+package gocovparser
+
+// DiffCoverage computes coverage only for blocks that intersect the changed line ranges of each file.
+// Profiles only record statement counts per block, so every statement of a block touching a changed line is
+// counted, including the statements on unchanged lines of that block.
+// The changes map is keyed by the coverage FileName or by its Path.
+func DiffCoverage(items []Coverage, changes map[string][]LineRange, opts ...PercentOption) DiffCoverageResult {
+	options := newPercentOptions(opts)
+	result := DiffCoverageResult{
+		Files: make(map[string]GroupDetail),
+	}
+
+	for _, cov := range items {
+		ranges, found := changes[cov.FileName]
+		if !found {
+			ranges, found = changes[cov.Path]
+		}
+
+		if !found {
+			continue
+		}
+
+		detail := GroupDetail{}
+
+		for _, b := range cov.Blocks {
+			if !blockIntersects(b.StartLine, b.EndLine, ranges) {
+				continue
+			}
+
+			detail.Total += b.NumStmt
+
+			if b.Count > 0 { // is covered
+				detail.Covered += b.NumStmt
+			}
+		}
+
+		detail.Percent = options.percentOf(detail.Covered, detail.Total)
+		result.Files[cov.FileName] = detail
+
+		result.Total.Total += detail.Total
+		result.Total.Covered += detail.Covered
+	}
+
+	result.Total.Percent = options.percentOf(result.Total.Covered, result.Total.Total)
+
+	return result
+}
+
+func blockIntersects(startLine, endLine int, ranges []LineRange) bool {
+	for _, r := range ranges {
+		if startLine <= r.End && endLine >= r.Start {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanComputeDiffCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.2 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:14.1,20.2 3 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:30.1,31.2 1 1
+github.com/heynemann/go-cov-parser/gocovparser/filter.go:5.1,6.2 4 0
+`)
+	require.NoError(t, err)
+
+	// changing line 11 counts both statements of the 10-12 block
+	changes := map[string][]gocovparser.LineRange{
+		"gocovparser/core.go": {{Start: 11, End: 11}, {Start: 18, End: 25}},
+		"github.com/heynemann/go-cov-parser/gocovparser/models.go": {{Start: 1, End: 100}},
+	}
+
+	// ACT
+	got := gocovparser.DiffCoverage(items, changes)
+
+	// ASSERT
+	require.Len(t, got.Files, 1)
+
+	file := got.Files["github.com/heynemann/go-cov-parser/gocovparser/core.go"]
+	assert.Equal(t, 5, file.Total)
+	assert.Equal(t, 2, file.Covered)
+	assert.EqualValues(t, 0.4, file.Percent)
+
+	assert.Equal(t, file, got.Total)
+}
+
+func TestDiffCoverageWithoutChangesIsEmpty(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.DiffCoverage(items, nil)
+
+	// ASSERT
+	assert.Empty(t, got.Files)
+	assert.Equal(t, gocovparser.GroupDetail{}, got.Total)
+}
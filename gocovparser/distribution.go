@@ -0,0 +1,77 @@
+package gocovparser
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	distributionBuckets = 10
+	medianQuantile      = 0.5
+	p10Quantile         = 0.1
+	p90Quantile         = 0.9
+)
+
+// GetCoverageDistribution computes the histogram, quantiles and extremes of the per-file coverage (by FileName).
+// Files without statements are left out, and every file weighs the same regardless of its size.
+// Quantiles are interpolated between the closest files.
+func GetCoverageDistribution(items []Coverage) CoverageDistribution {
+	result := CoverageDistribution{Buckets: make([]CoverageBucket, distributionBuckets)}
+
+	for index := range result.Buckets {
+		result.Buckets[index].Min = float64(index) / distributionBuckets
+		result.Buckets[index].Max = float64(index+1) / distributionBuckets
+	}
+
+	percents := []float64{}
+	sum := 0.0
+
+	for _, detail := range GroupBy(items, func(cov Coverage) string { return cov.FileName }) {
+		if detail.Total == 0 {
+			continue
+		}
+
+		// integer arithmetic keeps ratios such as 3/10 out of the previous bucket
+		bucket := detail.Covered * distributionBuckets / detail.Total
+		if bucket == distributionBuckets {
+			bucket--
+		}
+
+		result.Buckets[bucket].Files++
+
+		switch detail.Covered {
+		case 0:
+			result.Uncovered++
+		case detail.Total:
+			result.FullyCovered++
+		}
+
+		percents = append(percents, detail.Percent)
+		sum += detail.Percent
+	}
+
+	if len(percents) == 0 {
+		return result
+	}
+
+	sort.Float64s(percents)
+
+	result.Files = len(percents)
+	result.Min = percents[0]
+	result.Max = percents[len(percents)-1]
+	result.Mean = sum / float64(len(percents))
+	result.Median = quantile(percents, medianQuantile)
+	result.P10 = quantile(percents, p10Quantile)
+	result.P90 = quantile(percents, p90Quantile)
+
+	return result
+}
+
+// quantile interpolates the q quantile (0 to 1) of the sorted values.
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
@@ -0,0 +1,54 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCoverageDistribution(t *testing.T) {
+	items, err := gocovparser.Parse(`mode: set
+github.com/a/b/none.go:1.1,2.2 4 0
+github.com/a/b/third.go:1.1,2.2 3 1
+github.com/a/b/third.go:3.1,4.2 7 0
+github.com/a/b/half.go:1.1,2.2 1 1
+github.com/a/b/half.go:3.1,4.2 1 0
+github.com/a/b/full.go:1.1,2.2 2 1
+github.com/a/b/full.go:3.1,4.2 1 1
+github.com/a/b/empty.go:1.1,2.2 0 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetCoverageDistribution(items)
+
+	// ASSERT
+	assert.Equal(t, 4, got.Files)
+	assert.Equal(t, 1, got.Uncovered)
+	assert.Equal(t, 1, got.FullyCovered)
+	require.Len(t, got.Buckets, 10)
+	assert.Equal(t, gocovparser.CoverageBucket{Min: 0, Max: 0.1, Files: 1}, got.Buckets[0])
+	assert.Equal(t, 1, got.Buckets[3].Files)
+	assert.Equal(t, 1, got.Buckets[5].Files)
+	assert.Equal(t, gocovparser.CoverageBucket{Min: 0.9, Max: 1, Files: 1}, got.Buckets[9])
+	assert.EqualValues(t, 0, got.Min)
+	assert.EqualValues(t, 1, got.Max)
+	assert.InDelta(t, 0.45, got.Mean, 0.0001)
+	assert.InDelta(t, 0.4, got.Median, 0.0001)
+	assert.InDelta(t, 0.09, got.P10, 0.0001)
+	assert.InDelta(t, 0.85, got.P90, 0.0001)
+}
+
+func TestGetCoverageDistributionWithoutFiles(t *testing.T) {
+	// ACT
+	got := gocovparser.GetCoverageDistribution(nil)
+
+	// ASSERT
+	assert.Equal(t, 0, got.Files)
+	assert.Len(t, got.Buckets, 10)
+	assert.EqualValues(t, 0, got.Median)
+}
@@ -0,0 +1,226 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// Annotation levels of GitHub Actions workflow commands.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNotice  = "notice"
+)
+
+const annotationTitle = "Uncovered code"
+
+// annotation is a range of uncovered lines of a file.
+type annotation struct {
+	path      string
+	startLine int
+	endLine   int
+}
+
+func (a annotation) message() string {
+	if a.startLine == a.endLine {
+		return fmt.Sprintf("Line %d is not covered by tests", a.startLine)
+	}
+
+	return fmt.Sprintf("Lines %d-%d are not covered by tests", a.startLine, a.endLine)
+}
+
+// AnnotationOption configures the uncovered code annotations.
+type AnnotationOption func(*annotationOptions)
+
+type annotationOptions struct {
+	level      string
+	pathPrefix string
+	changes    map[string][]gocovparser.LineRange
+}
+
+// WithAnnotationLevel sets the level of the annotations: LevelError (default), LevelWarning or LevelNotice.
+// Other levels fail the export with ErrInvalidOption.
+func WithAnnotationLevel(level string) AnnotationOption {
+	return func(opts *annotationOptions) {
+		opts.level = level
+	}
+}
+
+// WithPathPrefix prefixes file paths with the directory of the module in the repository, for modules that are
+// not at the repository root. Annotations must use repository relative paths to show in pull requests.
+func WithPathPrefix(prefix string) AnnotationOption {
+	return func(opts *annotationOptions) {
+		opts.pathPrefix = prefix
+	}
+}
+
+// WithChangedLines only annotates the uncovered lines that were changed, as returned by gocovparser.ChangedLines.
+// The changes are keyed by coverage FileName or Path.
+func WithChangedLines(changes map[string][]gocovparser.LineRange) AnnotationOption {
+	return func(opts *annotationOptions) {
+		opts.changes = changes
+	}
+}
+
+// WriteGitHubAnnotations writes a GitHub Actions workflow command (e.g. `::error file=pkg/a.go,line=3::...`) for
+// every range of uncovered lines, so they are shown inline in the pull request diff when printed by a workflow step.
+func WriteGitHubAnnotations(w io.Writer, items []gocovparser.Coverage, opts ...AnnotationOption) error {
+	options, err := newAnnotationOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range annotationsOf(items, options) {
+		_, err := fmt.Fprintf(
+			w, "::%s file=%s,line=%d,endLine=%d,title=%s::%s\n",
+			options.level,
+			escapeProperty(a.path), a.startLine, a.endLine, escapeProperty(annotationTitle),
+			escapeData(a.message()),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to write github annotations")
+		}
+	}
+
+	return nil
+}
+
+type checkRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+}
+
+// WriteCheckRunAnnotations writes the ranges of uncovered lines as a JSON array of GitHub check run annotations,
+// to be sent in the output of a check run through the GitHub API. The error level is reported as "failure".
+func WriteCheckRunAnnotations(w io.Writer, items []gocovparser.Coverage, opts ...AnnotationOption) error {
+	options, err := newAnnotationOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	level := options.level
+	if level == LevelError {
+		level = "failure"
+	}
+
+	result := []checkRunAnnotation{}
+
+	for _, a := range annotationsOf(items, options) {
+		result = append(result, checkRunAnnotation{
+			Path:            a.path,
+			StartLine:       a.startLine,
+			EndLine:         a.endLine,
+			AnnotationLevel: level,
+			Title:           annotationTitle,
+			Message:         a.message(),
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return errors.Wrap(err, "failed to write check run annotations")
+	}
+
+	return nil
+}
+
+func newAnnotationOptions(opts []AnnotationOption) (annotationOptions, error) {
+	options := annotationOptions{level: LevelError}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	switch options.level {
+	case LevelError, LevelWarning, LevelNotice:
+		return options, nil
+	default:
+		return annotationOptions{}, errors.Wrapf(
+			ErrInvalidOption, "annotation level %q is not one of %s, %s or %s", options.level,
+			LevelError, LevelWarning, LevelNotice,
+		)
+	}
+}
+
+// annotationsOf returns the uncovered ranges of lines of the items (restricted to the changed lines, if any),
+// sorted by path and line.
+func annotationsOf(items []gocovparser.Coverage, options annotationOptions) []annotation {
+	uncovered := gocovparser.GetUncoveredRanges(items)
+	result := []annotation{}
+
+	for _, cov := range items {
+		ranges := uncovered[cov.FileName]
+
+		if options.changes != nil {
+			changed, found := options.changes[cov.FileName]
+			if !found {
+				changed = options.changes[cov.Path]
+			}
+
+			ranges = intersectRanges(ranges, changed)
+		}
+
+		for _, r := range ranges {
+			result = append(result, annotation{
+				path:      path.Join(options.pathPrefix, gocovparser.CanonicalPath(cov.Path)),
+				startLine: r.Start,
+				endLine:   r.End,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].path != result[j].path {
+			return result[i].path < result[j].path
+		}
+
+		return result[i].startLine < result[j].startLine
+	})
+
+	return result
+}
+
+// intersectRanges returns the parts of the ranges that are also in others.
+func intersectRanges(ranges, others []gocovparser.LineRange) []gocovparser.LineRange {
+	result := []gocovparser.LineRange{}
+
+	for _, r := range ranges {
+		for _, other := range others {
+			start, end := r.Start, r.End
+
+			if other.Start > start {
+				start = other.Start
+			}
+
+			if other.End < end {
+				end = other.End
+			}
+
+			if start <= end {
+				result = append(result, gocovparser.LineRange{Start: start, End: end})
+			}
+		}
+	}
+
+	return result
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeProperty escapes a property value of a workflow command.
+func escapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
@@ -0,0 +1,101 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const annotationsFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.20 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:14.20,17.3 1 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,20.30 1 0
+github.com/heynemann/go-cov-parser/gocovparser/export/lines.go:5.1,5.30 1 1
+`
+
+func TestCanWriteGitHubAnnotations(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteGitHubAnnotations(&buf, items, export.WithPathPrefix("module"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t,
+		"::error file=module/gocovparser/core.go,line=14,endLine=17,title=Uncovered code::"+
+			"Lines 14-17 are not covered by tests\n"+
+			"::error file=module/gocovparser/core.go,line=20,endLine=20,title=Uncovered code::"+
+			"Line 20 is not covered by tests\n",
+		buf.String(),
+	)
+}
+
+func TestGitHubAnnotationsOfChangedLines(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	changes := map[string][]gocovparser.LineRange{
+		"gocovparser/core.go": {{Start: 1, End: 11}, {Start: 16, End: 30}},
+	}
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteGitHubAnnotations(
+		&buf, items, export.WithChangedLines(changes), export.WithAnnotationLevel(export.LevelWarning),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t,
+		"::warning file=gocovparser/core.go,line=16,endLine=17,title=Uncovered code::"+
+			"Lines 16-17 are not covered by tests\n"+
+			"::warning file=gocovparser/core.go,line=20,endLine=20,title=Uncovered code::"+
+			"Line 20 is not covered by tests\n",
+		buf.String(),
+	)
+}
+
+func TestCanWriteCheckRunAnnotations(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteCheckRunAnnotations(&buf, items, export.WithChangedLines(map[string][]gocovparser.LineRange{
+		"github.com/heynemann/go-cov-parser/gocovparser/core.go": {{Start: 20, End: 20}},
+	}))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"path": "gocovparser/core.go",
+		"start_line": 20,
+		"end_line": 20,
+		"annotation_level": "failure",
+		"title": "Uncovered code",
+		"message": "Line 20 is not covered by tests"
+	}]`, buf.String())
+}
+
+func TestWriteGitHubAnnotationsFailsIfWriterFails(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	// ACT
+	err = export.WriteGitHubAnnotations(failingWriter{}, items)
+
+	// ASSERT
+	assert.ErrorIs(t, err, errWrite)
+}
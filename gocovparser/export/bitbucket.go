@@ -0,0 +1,111 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const (
+	bitbucketPrecision = 2
+
+	bitbucketPassed = "PASSED"
+	bitbucketFailed = "FAILED"
+)
+
+type bitbucketReport struct {
+	Title      string          `json:"title"`
+	Details    string          `json:"details"`
+	ReportType string          `json:"report_type"`
+	Reporter   string          `json:"reporter"`
+	Result     string          `json:"result"`
+	Data       []bitbucketData `json:"data"`
+}
+
+type bitbucketData struct {
+	Title string      `json:"title"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type bitbucketAnnotation struct {
+	ExternalID     string `json:"external_id"`
+	Path           string `json:"path"`
+	Line           int    `json:"line"`
+	Summary        string `json:"summary"`
+	AnnotationType string `json:"annotation_type"`
+	Severity       string `json:"severity"`
+}
+
+// bitbucketSeverities maps annotation levels to Bitbucket code insights severities.
+var bitbucketSeverities = map[string]string{
+	LevelError:   "HIGH",
+	LevelWarning: "MEDIUM",
+	LevelNotice:  "LOW",
+}
+
+// WriteBitbucketReport writes a Bitbucket code insights report with the total coverage, to be created through the
+// reports API of a commit. The report fails if the total coverage ratio is below minimum (0 to 1).
+func WriteBitbucketReport(w io.Writer, items []gocovparser.Coverage, minimum float64) error {
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+
+	result := bitbucketPassed
+	if breakdown.Coverage < minimum {
+		result = bitbucketFailed
+	}
+
+	report := bitbucketReport{
+		Title:      "Coverage",
+		Details:    fmt.Sprintf("%d of %d statements covered by tests", breakdown.CoveredStatements, breakdown.Statements),
+		ReportType: "COVERAGE",
+		Reporter:   "gocovparser",
+		Result:     result,
+		Data: []bitbucketData{
+			{Title: "Coverage", Type: "PERCENTAGE", Value: gocovparser.FormatPercent(
+				breakdown.Coverage,
+				gocovparser.WithPercentScale(),
+				gocovparser.WithRounding(gocovparser.RoundNearest, bitbucketPrecision),
+			)},
+			{Title: "Statements", Type: "NUMBER", Value: breakdown.Statements},
+			{Title: "Covered statements", Type: "NUMBER", Value: breakdown.CoveredStatements},
+		},
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		return errors.Wrap(err, "failed to write bitbucket report")
+	}
+
+	return nil
+}
+
+// WriteBitbucketAnnotations writes every range of uncovered lines as a Bitbucket code insights annotation on its
+// first line, to be added to a report through the annotations API.
+// Annotation levels are reported as the HIGH, MEDIUM and LOW severities.
+func WriteBitbucketAnnotations(w io.Writer, items []gocovparser.Coverage, opts ...AnnotationOption) error {
+	options, err := newAnnotationOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	annotations := []bitbucketAnnotation{}
+
+	for _, a := range annotationsOf(items, options) {
+		annotations = append(annotations, bitbucketAnnotation{
+			ExternalID:     fmt.Sprintf("%s:%d-%d", a.path, a.startLine, a.endLine),
+			Path:           a.path,
+			Line:           a.startLine,
+			Summary:        a.message(),
+			AnnotationType: "CODE_SMELL",
+			Severity:       bitbucketSeverities[options.level],
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(annotations); err != nil {
+		return errors.Wrap(err, "failed to write bitbucket annotations")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWriteBitbucketReport(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		minimum float64
+		result  string
+	}{
+		"passed": {minimum: 0.5, result: "PASSED"},
+		"failed": {minimum: 0.8, result: "FAILED"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			// ACT
+			err := export.WriteBitbucketReport(&buf, items, testCase.minimum)
+
+			// ASSERT
+			require.NoError(t, err)
+			assert.JSONEq(t, `{
+				"title": "Coverage",
+				"details": "3 of 5 statements covered by tests",
+				"report_type": "COVERAGE",
+				"reporter": "gocovparser",
+				"result": "`+testCase.result+`",
+				"data": [
+					{"title": "Coverage", "type": "PERCENTAGE", "value": 60},
+					{"title": "Statements", "type": "NUMBER", "value": 5},
+					{"title": "Covered statements", "type": "NUMBER", "value": 3}
+				]
+			}`, buf.String())
+		})
+	}
+}
+
+func TestCanWriteBitbucketAnnotations(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteBitbucketAnnotations(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"external_id": "gocovparser/core.go:14-17",
+			"path": "gocovparser/core.go",
+			"line": 14,
+			"summary": "Lines 14-17 are not covered by tests",
+			"annotation_type": "CODE_SMELL",
+			"severity": "HIGH"
+		},
+		{
+			"external_id": "gocovparser/core.go:20-20",
+			"path": "gocovparser/core.go",
+			"line": 20,
+			"summary": "Line 20 is not covered by tests",
+			"annotation_type": "CODE_SMELL",
+			"severity": "HIGH"
+		}
+	]`, buf.String())
+}
@@ -0,0 +1,223 @@
+// Package export writes parsed coverage in formats consumed by other coverage tools.
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        float64            `xml:"line-rate,attr"`
+	BranchRate      float64            `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      float64            `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   float64          `xml:"line-rate,attr"`
+	BranchRate float64          `xml:"branch-rate,attr"`
+	Complexity float64          `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	FileName   string          `xml:"filename,attr"`
+	LineRate   float64         `xml:"line-rate,attr"`
+	BranchRate float64         `xml:"branch-rate,attr"`
+	Complexity float64         `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+}
+
+// counts accumulates covered/valid lines and branches.
+type counts struct {
+	lines, coveredLines       int
+	branches, coveredBranches int
+}
+
+func (c *counts) add(other counts) {
+	c.lines += other.lines
+	c.coveredLines += other.coveredLines
+	c.branches += other.branches
+	c.coveredBranches += other.coveredBranches
+}
+
+func (c counts) lineRate() float64 {
+	return rate(c.coveredLines, c.lines)
+}
+
+func (c counts) branchRate() float64 {
+	return rate(c.coveredBranches, c.branches)
+}
+
+// CoberturaOption configures the Cobertura export.
+type CoberturaOption func(*coberturaOptions)
+
+type coberturaOptions struct {
+	timestamp time.Time
+	source    string
+}
+
+// WithTimestamp sets the report timestamp. Defaults to the current time.
+func WithTimestamp(timestamp time.Time) CoberturaOption {
+	return func(opts *coberturaOptions) {
+		opts.timestamp = timestamp
+	}
+}
+
+// WithSource sets the source directory file names are relative to. Defaults to ".".
+func WithSource(source string) CoberturaOption {
+	return func(opts *coberturaOptions) {
+		opts.source = source
+	}
+}
+
+// WriteCobertura writes the coverage items as a Cobertura XML report.
+// Each coverage block is treated as a branch of the lines it spans.
+// The report is also GitLab's coverage report artifact format (`coverage_format: cobertura`).
+func WriteCobertura(w io.Writer, items []gocovparser.Coverage, opts ...CoberturaOption) error {
+	options := coberturaOptions{
+		timestamp: time.Now(),
+		source:    ".",
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	report := coberturaCoverage{
+		Version:   "gocovparser",
+		Timestamp: options.timestamp.UnixMilli(),
+		Sources:   []string{options.source},
+	}
+
+	total := counts{}
+
+	for _, pkg := range groupByPackage(items) {
+		pkgCounts := counts{}
+		coberturaPkg := coberturaPackage{Name: pkg.name}
+
+		for _, cov := range pkg.items {
+			class, classCounts := coberturaClassFor(cov)
+			coberturaPkg.Classes = append(coberturaPkg.Classes, class)
+			pkgCounts.add(classCounts)
+		}
+
+		coberturaPkg.LineRate = pkgCounts.lineRate()
+		coberturaPkg.BranchRate = pkgCounts.branchRate()
+		report.Packages = append(report.Packages, coberturaPkg)
+		total.add(pkgCounts)
+	}
+
+	report.LineRate = total.lineRate()
+	report.BranchRate = total.branchRate()
+	report.LinesCovered = total.coveredLines
+	report.LinesValid = total.lines
+	report.BranchesCovered = total.coveredBranches
+	report.BranchesValid = total.branches
+
+	if _, err := io.WriteString(w, xml.Header+coberturaDocType+"\n"); err != nil {
+		return errors.Wrap(err, "failed to write cobertura header")
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return errors.Wrap(err, "failed to write cobertura report")
+	}
+
+	return nil
+}
+
+func coberturaClassFor(cov gocovparser.Coverage) (coberturaClass, counts) {
+	class := coberturaClass{
+		Name:     path.Base(gocovparser.CanonicalPath(cov.FileName)),
+		FileName: gocovparser.CanonicalPath(cov.Path),
+	}
+
+	classCounts := counts{}
+
+	for _, line := range linesOf(cov) {
+		coberturaLine := coberturaLine{
+			Number: line.number,
+			Hits:   line.hits,
+		}
+
+		if line.blocks > 1 {
+			coberturaLine.Branch = true
+			coberturaLine.ConditionCoverage = conditionCoverage(line.coveredBlocks, line.blocks)
+			classCounts.branches += line.blocks
+			classCounts.coveredBranches += line.coveredBlocks
+		}
+
+		classCounts.lines++
+
+		if line.hits > 0 {
+			classCounts.coveredLines++
+		}
+
+		class.Lines = append(class.Lines, coberturaLine)
+	}
+
+	class.LineRate = classCounts.lineRate()
+	class.BranchRate = classCounts.branchRate()
+
+	return class, classCounts
+}
+
+type packageItems struct {
+	name  string
+	items []gocovparser.Coverage
+}
+
+// groupByPackage groups coverage items by package import path, sorted by package and file name.
+func groupByPackage(items []gocovparser.Coverage) []packageItems {
+	byName := make(map[string][]gocovparser.Coverage)
+
+	for _, cov := range items {
+		pkg := path.Dir(gocovparser.CanonicalPath(cov.FileName))
+		byName[pkg] = append(byName[pkg], cov)
+	}
+
+	result := make([]packageItems, 0, len(byName))
+
+	for name, pkgItems := range byName {
+		sort.Slice(pkgItems, func(i, j int) bool {
+			return pkgItems[i].FileName < pkgItems[j].FileName
+		})
+
+		result = append(result, packageItems{name: name, items: pkgItems})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+
+	return result
+}
@@ -0,0 +1,86 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exportFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.20 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:12.20,13.3 1 0
+github.com/heynemann/go-cov-parser/gocovparser/export/lines.go:5.1,5.30 1 1
+`
+
+func TestCanWriteCobertura(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteCobertura(&buf, items, export.WithTimestamp(time.UnixMilli(1000)))
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.8" branch-rate="0.5" lines-covered="4" lines-valid="5" branches-covered="1" branches-valid="2" complexity="0" version="gocovparser" timestamp="1000">
+  <sources>
+    <source>.</source>
+  </sources>
+  <packages>
+    <package name="github.com/heynemann/go-cov-parser/gocovparser" line-rate="0.75" branch-rate="0.5" complexity="0">
+      <classes>
+        <class name="core.go" filename="gocovparser/core.go" line-rate="0.75" branch-rate="0.5" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="10" hits="1" branch="false"></line>
+            <line number="11" hits="1" branch="false"></line>
+            <line number="12" hits="1" branch="true" condition-coverage="50% (1/2)"></line>
+            <line number="13" hits="0" branch="false"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+    <package name="github.com/heynemann/go-cov-parser/gocovparser/export" line-rate="1" branch-rate="0" complexity="0">
+      <classes>
+        <class name="lines.go" filename="gocovparser/export/lines.go" line-rate="1" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="5" hits="1" branch="false"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+	assert.Equal(t, expected, buf.String())
+
+	var decoded struct {
+		XMLName xml.Name `xml:"coverage"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+}
+
+func TestCanWriteEmptyCobertura(t *testing.T) {
+	var buf bytes.Buffer
+
+	// ACT
+	err := export.WriteCobertura(&buf, nil, export.WithSource("/src"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `<source>/src</source>`)
+	assert.Contains(t, buf.String(), `lines-valid="0"`)
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+type codecovReport struct {
+	Coverage map[string]map[string]interface{} `json:"coverage"`
+}
+
+// WriteCodecov writes the coverage items in the Codecov JSON format. Each line holds its hits, or the number
+// of covered blocks out of the blocks on the line (e.g. "1/2") when it is only partially covered.
+func WriteCodecov(w io.Writer, items []gocovparser.Coverage) error {
+	report := codecovReport{
+		Coverage: make(map[string]map[string]interface{}, len(items)),
+	}
+
+	for _, cov := range items {
+		lines := make(map[string]interface{})
+
+		for _, line := range linesOf(cov) {
+			lines[strconv.Itoa(line.number)] = codecovHits(line)
+		}
+
+		report.Coverage[gocovparser.CanonicalPath(cov.Path)] = lines
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		return errors.Wrap(err, "failed to write codecov report")
+	}
+
+	return nil
+}
+
+func codecovHits(line lineInfo) interface{} {
+	if line.coveredBlocks > 0 && line.coveredBlocks < line.blocks {
+		return fmt.Sprintf("%d/%d", line.coveredBlocks, line.blocks)
+	}
+
+	return line.hits
+}
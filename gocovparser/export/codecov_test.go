@@ -0,0 +1,44 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWriteCodecov(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteCodecov(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected := `{"coverage": {
+		"gocovparser/core.go": {"10": 1, "11": 1, "12": "1/2", "13": 0},
+		"gocovparser/export/lines.go": {"5": 1}
+	}}`
+	assert.JSONEq(t, expected, buf.String())
+}
+
+func TestWriteCodecovFailsIfWriterFails(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	// ACT
+	err = export.WriteCodecov(failingWriter{}, items)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// writeContext runs write with a writer that fails once ctx is done, returning the context error if it is.
+func writeContext(ctx context.Context, w io.Writer, write func(w io.Writer) error) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "export aborted")
+	}
+
+	err := write(gocovparser.NewContextWriter(ctx, w))
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return errors.Wrap(ctxErr, "export aborted")
+	}
+
+	return err
+}
+
+// WriteLCOVContext writes the coverage as WriteLCOV does, aborting once ctx is done.
+func WriteLCOVContext(ctx context.Context, w io.Writer, items []gocovparser.Coverage) error {
+	return writeContext(ctx, w, func(w io.Writer) error {
+		return WriteLCOV(w, items)
+	})
+}
+
+// WriteCoberturaContext writes the coverage as WriteCobertura does, aborting once ctx is done.
+func WriteCoberturaContext(ctx context.Context, w io.Writer, items []gocovparser.Coverage, opts ...CoberturaOption) error {
+	return writeContext(ctx, w, func(w io.Writer) error {
+		return WriteCobertura(w, items, opts...)
+	})
+}
+
+// WriteCodecovContext writes the coverage as WriteCodecov does, aborting once ctx is done.
+func WriteCodecovContext(ctx context.Context, w io.Writer, items []gocovparser.Coverage) error {
+	return writeContext(ctx, w, func(w io.Writer) error {
+		return WriteCodecov(w, items)
+	})
+}
+
+// WriteSonarGenericCoverageContext writes the coverage as WriteSonarGenericCoverage does, aborting once ctx is done.
+func WriteSonarGenericCoverageContext(
+	ctx context.Context, w io.Writer, items []gocovparser.Coverage, opts ...SonarOption,
+) error {
+	return writeContext(ctx, w, func(w io.Writer) error {
+		return WriteSonarGenericCoverage(w, items, opts...)
+	})
+}
@@ -0,0 +1,54 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritersHonorContext(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	writers := map[string]func(context.Context, io.Writer) error{
+		"lcov": func(ctx context.Context, w io.Writer) error {
+			return export.WriteLCOVContext(ctx, w, items)
+		},
+		"cobertura": func(ctx context.Context, w io.Writer) error {
+			return export.WriteCoberturaContext(ctx, w, items)
+		},
+		"codecov": func(ctx context.Context, w io.Writer) error {
+			return export.WriteCodecovContext(ctx, w, items)
+		},
+		"sonar": func(ctx context.Context, w io.Writer) error {
+			return export.WriteSonarGenericCoverageContext(ctx, w, items)
+		},
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			var buf, discarded bytes.Buffer
+
+			// ACT
+			err := write(context.Background(), &buf)
+			cancelledErr := write(cancelled, &discarded)
+
+			// ASSERT
+			require.NoError(t, err)
+			assert.NotEmpty(t, buf.String())
+			assert.ErrorIs(t, cancelledErr, context.Canceled)
+			assert.Empty(t, discarded.String())
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// EditorJSONVersion is the version of the format written by WriteEditorJSON.
+const EditorJSONVersion = 1
+
+// EditorOption configures the exports read by editors.
+type EditorOption func(*editorOptions)
+
+type editorOptions struct {
+	workspace string
+	moduleDir string
+}
+
+// WithWorkspaceFolder sets the folder opened in the editor. Files whose LocalPath is known (see
+// gocovparser.WithGoMod, WithModuleRoot and WithGoWork) are written relative to it, or as absolute paths when
+// outside of it.
+func WithWorkspaceFolder(dir string) EditorOption {
+	return func(opts *editorOptions) {
+		opts.workspace = dir
+	}
+}
+
+// WithModuleFolder sets the folder of the module in the workspace (e.g. `services/api`), prefixing the module
+// relative paths of files whose LocalPath is unknown. Defaults to the workspace folder itself.
+func WithModuleFolder(dir string) EditorOption {
+	return func(opts *editorOptions) {
+		opts.moduleDir = dir
+	}
+}
+
+// path returns the path of the file as the editor resolves it from the workspace folder.
+func (o editorOptions) path(cov gocovparser.Coverage) string {
+	if o.workspace != "" && cov.LocalPath != "" {
+		workspace, workspaceErr := filepath.Abs(o.workspace)
+		local, localErr := filepath.Abs(cov.LocalPath)
+
+		if workspaceErr == nil && localErr == nil {
+			relative, err := filepath.Rel(workspace, local)
+			if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+				return filepath.ToSlash(local)
+			}
+
+			return filepath.ToSlash(relative)
+		}
+	}
+
+	return path.Join(filepath.ToSlash(o.moduleDir), gocovparser.CanonicalPath(cov.Path))
+}
+
+func newEditorOptions(opts []EditorOption) editorOptions {
+	options := editorOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WriteCoverageGutters writes the coverage items as the LCOV tracefile read by the Coverage Gutters extension of
+// VS Code (by default from `lcov.info` at the root of the workspace), with source files relative to the workspace
+// folder so they resolve to the open files.
+func WriteCoverageGutters(w io.Writer, items []gocovparser.Coverage, opts ...EditorOption) error {
+	options := newEditorOptions(opts)
+	buf := bufio.NewWriter(w)
+
+	for _, cov := range gocovparser.SortCoverage(items) {
+		writeLCOVRecord(buf, options.path(cov), cov)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write coverage gutters report")
+	}
+
+	return nil
+}
+
+type editorReport struct {
+	Version int          `json:"version"`
+	Files   []editorFile `json:"files"`
+}
+
+type editorFile struct {
+	Path         string       `json:"path"`
+	Lines        []editorLine `json:"lines"`
+	CoveredLines int          `json:"coveredLines"`
+	TotalLines   int          `json:"totalLines"`
+}
+
+type editorLine struct {
+	Line   int    `json:"line"`
+	Hits   int    `json:"hits"`
+	Status string `json:"status"`
+}
+
+// WriteEditorJSON writes the coverage of every line with statements as JSON, for editor plugins and scripts that
+// don't read LCOV, e.g.:
+//
+//	{"version": 1, "files": [{"path": "pkg/a.go", "lines": [{"line": 3, "hits": 1, "status": "covered"}],
+//	  "coveredLines": 1, "totalLines": 1}]}
+//
+// Files are sorted by path and lines by number. Partial lines touch both executed and unexecuted blocks.
+func WriteEditorJSON(w io.Writer, items []gocovparser.Coverage, opts ...EditorOption) error {
+	options := newEditorOptions(opts)
+	lines := gocovparser.GetLineCoverage(items)
+	files := map[string]*editorFile{}
+
+	for _, cov := range items {
+		filePath := options.path(cov)
+		if _, found := files[filePath]; found {
+			continue
+		}
+
+		file := &editorFile{Path: filePath, Lines: []editorLine{}}
+
+		for number, line := range lines[cov.FileName] {
+			file.Lines = append(file.Lines, editorLine{Line: number, Hits: line.Hits, Status: line.Status.String()})
+			file.TotalLines++
+
+			if line.Status != gocovparser.LineUncovered {
+				file.CoveredLines++
+			}
+		}
+
+		sort.Slice(file.Lines, func(i, j int) bool {
+			return file.Lines[i].Line < file.Lines[j].Line
+		})
+
+		files[filePath] = file
+	}
+
+	report := editorReport{Version: EditorJSONVersion, Files: make([]editorFile, 0, len(files))}
+	for _, file := range files {
+		report.Files = append(report.Files, *file)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return errors.Wrap(err, "failed to write editor json report")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWriteCoverageGutters(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteCoverageGutters(&buf, items, export.WithModuleFolder("services/api"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "TN:\nSF:services/api/gocovparser/core.go\nDA:10,1\n")
+	assert.Contains(t, buf.String(), "TN:\nSF:services/api/gocovparser/export/lines.go\nDA:5,1\n")
+}
+
+func TestCoverageGuttersPathsAreRelativeToWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	outside := t.TempDir()
+
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	items[0].LocalPath = filepath.Join(workspace, "api", "core.go")
+	items[1].LocalPath = filepath.Join(outside, "lines.go")
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteCoverageGutters(&buf, items, export.WithWorkspaceFolder(workspace))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "SF:api/core.go\n")
+	assert.Contains(t, buf.String(), "SF:"+filepath.ToSlash(filepath.Join(outside, "lines.go"))+"\n")
+}
+
+func TestCanWriteEditorJSON(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteEditorJSON(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"files": [
+			{
+				"path": "gocovparser/core.go",
+				"lines": [
+					{"line": 10, "hits": 1, "status": "covered"},
+					{"line": 11, "hits": 1, "status": "covered"},
+					{"line": 12, "hits": 1, "status": "partial"},
+					{"line": 13, "hits": 0, "status": "uncovered"}
+				],
+				"coveredLines": 3,
+				"totalLines": 4
+			},
+			{
+				"path": "gocovparser/export/lines.go",
+				"lines": [{"line": 5, "hits": 1, "status": "covered"}],
+				"coveredLines": 1,
+				"totalLines": 1
+			}
+		]
+	}`, buf.String())
+}
@@ -0,0 +1,12 @@
+package export
+
+import "errors"
+
+// ErrUnknownFormat happens when looking up an export format that was not registered.
+var ErrUnknownFormat = errors.New("unknown export format - not registered")
+
+// ErrFormatExists happens when registering an exporter with the name of an already registered format.
+var ErrFormatExists = errors.New("export format already registered - names must be unique")
+
+// ErrInvalidOption happens when an export option has a value the exporter can't use.
+var ErrInvalidOption = errors.New("invalid export option - unexpected value")
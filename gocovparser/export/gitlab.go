@@ -0,0 +1,72 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const gitlabCheckName = "gocovparser-uncovered"
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// gitlabSeverities maps annotation levels to GitLab code quality severities.
+var gitlabSeverities = map[string]string{
+	LevelError:   "major",
+	LevelWarning: "minor",
+	LevelNotice:  "info",
+}
+
+// WriteGitLabCodeQuality writes every range of uncovered lines as an issue of a GitLab code quality report
+// (the `codequality` report artifact), so they are shown in merge request diffs.
+// Annotation levels are reported as the major, minor and info severities.
+func WriteGitLabCodeQuality(w io.Writer, items []gocovparser.Coverage, opts ...AnnotationOption) error {
+	options, err := newAnnotationOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	issues := []gitlabIssue{}
+
+	for _, a := range annotationsOf(items, options) {
+		fingerprint := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d", a.path, a.startLine, a.endLine)))
+
+		issues = append(issues, gitlabIssue{
+			Description: a.message(),
+			CheckName:   gitlabCheckName,
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			Severity:    gitlabSeverities[options.level],
+			Location: gitlabLocation{
+				Path:  a.path,
+				Lines: gitlabLines{Begin: a.startLine, End: a.endLine},
+			},
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(issues); err != nil {
+		return errors.Wrap(err, "failed to write gitlab code quality report")
+	}
+
+	return nil
+}
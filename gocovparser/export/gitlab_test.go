@@ -0,0 +1,55 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWriteGitLabCodeQuality(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteGitLabCodeQuality(&buf, items, export.WithAnnotationLevel(export.LevelWarning))
+
+	// ASSERT
+	require.NoError(t, err)
+
+	issues := []map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	require.Len(t, issues, 2)
+
+	assert.Equal(t, "Lines 14-17 are not covered by tests", issues[0]["description"])
+	assert.Equal(t, "gocovparser-uncovered", issues[0]["check_name"])
+	assert.Equal(t, "minor", issues[0]["severity"])
+	assert.Equal(t, map[string]interface{}{
+		"path":  "gocovparser/core.go",
+		"lines": map[string]interface{}{"begin": 14.0, "end": 17.0},
+	}, issues[0]["location"])
+	assert.Len(t, issues[0]["fingerprint"], 64)
+	assert.NotEqual(t, issues[0]["fingerprint"], issues[1]["fingerprint"])
+}
+
+func TestWriteGitLabCodeQualityWithoutUncoveredLines(t *testing.T) {
+	items, err := gocovparser.Parse("mode: set\ngithub.com/owner/repo/a.go:1.1,2.2 1 1\n")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteGitLabCodeQuality(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", buf.String())
+}
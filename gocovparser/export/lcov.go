@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// WriteLCOV writes the coverage items as an LCOV tracefile, with one record per file.
+// Source files (SF) are written relative to the repository root.
+func WriteLCOV(w io.Writer, items []gocovparser.Coverage) error {
+	buf := bufio.NewWriter(w)
+
+	for _, cov := range gocovparser.SortCoverage(items) {
+		writeLCOVRecord(buf, gocovparser.CanonicalPath(cov.Path), cov)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write lcov report")
+	}
+
+	return nil
+}
+
+// ExportLCOV exports the coverage items as an LCOV tracefile, as WriteLCOV does.
+func ExportLCOV(w io.Writer, items []gocovparser.Coverage) error {
+	return WriteLCOV(w, items)
+}
+
+// writeLCOVRecord writes the record of the file, with path as its source file.
+func writeLCOVRecord(w *bufio.Writer, path string, cov gocovparser.Coverage) {
+	lines := linesOf(cov)
+	hit := 0
+
+	fmt.Fprintf(w, "TN:\nSF:%s\n", path)
+
+	for _, line := range lines {
+		fmt.Fprintf(w, "DA:%d,%d\n", line.number, line.hits)
+
+		if line.hits > 0 {
+			hit++
+		}
+	}
+
+	fmt.Fprintf(w, "LF:%d\nLH:%d\nend_of_record\n", len(lines), hit)
+}
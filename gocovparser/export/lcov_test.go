@@ -0,0 +1,154 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestCanWriteLCOV(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteLCOV(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected := `TN:
+SF:gocovparser/core.go
+DA:10,1
+DA:11,1
+DA:12,1
+DA:13,0
+LF:4
+LH:3
+end_of_record
+TN:
+SF:gocovparser/export/lines.go
+DA:5,1
+LF:1
+LH:1
+end_of_record
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestExportLCOVWritesLCOV(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var exported, written bytes.Buffer
+
+	// ACT
+	err = export.ExportLCOV(&exported, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, export.WriteLCOV(&written, items))
+	assert.Equal(t, written.String(), exported.String())
+}
+
+func TestWriteLCOVFailsIfWriterFails(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	// ACT
+	err = export.WriteLCOV(failingWriter{}, items)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errWrite)
+}
+
+func TestWrittenLCOVCanBeParsedBack(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, export.WriteLCOV(&buf, items))
+
+	// ACT
+	got, err := gocovparser.ParseLCOV(&buf)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "gocovparser/core.go", got[0].Path)
+	assert.Equal(t, 5, gocovparser.GetTotalCoverageBreakdown(got).Statements)
+}
+
+func TestWritersIgnoreItemOrder(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	shuffled := make([]gocovparser.Coverage, 0, len(items))
+	for index := len(items) - 1; index >= 0; index-- {
+		shuffled = append(shuffled, items[index])
+	}
+
+	writers := map[string]func(*bytes.Buffer, []gocovparser.Coverage) error{
+		"lcov": func(buf *bytes.Buffer, items []gocovparser.Coverage) error {
+			return export.WriteLCOV(buf, items)
+		},
+		"cobertura": func(buf *bytes.Buffer, items []gocovparser.Coverage) error {
+			return export.WriteCobertura(buf, items, export.WithTimestamp(time.Unix(0, 0)))
+		},
+		"codecov": func(buf *bytes.Buffer, items []gocovparser.Coverage) error {
+			return export.WriteCodecov(buf, items)
+		},
+		"sonar": func(buf *bytes.Buffer, items []gocovparser.Coverage) error {
+			return export.WriteSonarGenericCoverage(buf, items)
+		},
+	}
+
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			var expected, got bytes.Buffer
+
+			// ACT
+			require.NoError(t, write(&expected, items))
+			require.NoError(t, write(&got, shuffled))
+
+			// ASSERT
+			assert.Equal(t, expected.String(), got.String())
+		})
+	}
+}
+
+func TestWriteLCOVUsesSlashes(t *testing.T) {
+	items := []gocovparser.Coverage{{
+		FileName: `github.com/a/b/pkg\core.go`,
+		Path:     `pkg\core.go`,
+		Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 1}},
+	}}
+
+	var buf bytes.Buffer
+
+	// ACT
+	err := export.WriteLCOV(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "SF:pkg/core.go\n")
+}
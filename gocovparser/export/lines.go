@@ -0,0 +1,46 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+// lineInfo is the coverage of a source line along with the blocks touching it.
+type lineInfo struct {
+	number        int
+	hits          int
+	blocks        int
+	coveredBlocks int
+}
+
+// linesOf returns the coverage of each line with statements in the file, sorted by line number.
+func linesOf(cov gocovparser.Coverage) []lineInfo {
+	lines := gocovparser.GetLineCoverage([]gocovparser.Coverage{cov})[cov.FileName]
+
+	result := make([]lineInfo, 0, len(lines))
+	for number, line := range lines {
+		result = append(result, lineInfo{
+			number: number, hits: line.Hits, blocks: line.Blocks, coveredBlocks: line.CoveredBlocks,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].number < result[j].number
+	})
+
+	return result
+}
+
+func rate(covered, total int) float64 {
+	if total == 0 {
+		return 0.0
+	}
+
+	return float64(covered) / float64(total)
+}
+
+func conditionCoverage(covered, total int) string {
+	return fmt.Sprintf("%d%% (%d/%d)", covered*100/total, covered, total)
+}
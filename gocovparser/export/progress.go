@@ -0,0 +1,74 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+type instrumentedExporter struct {
+	Exporter
+
+	logger   gocovparser.Logger
+	progress gocovparser.ProgressFunc
+}
+
+var _ Exporter = (*instrumentedExporter)(nil)
+
+// Instrument wraps the exporter so it reports the bytes written to progress while writing, and the files exported
+// once done, and logs each export at debug level to logger. Either of them can be nil.
+func Instrument(exporter Exporter, logger gocovparser.Logger, progress gocovparser.ProgressFunc) Exporter {
+	return &instrumentedExporter{Exporter: exporter, logger: logger, progress: progress}
+}
+
+func (e *instrumentedExporter) Write(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+	writer := &progressWriter{w: w, start: time.Now(), progress: e.progress}
+
+	err := e.Exporter.Write(writer, items, opts)
+
+	done := writer.snapshot()
+	done.Files, done.Done = len(items), true
+
+	if e.progress != nil {
+		e.progress(done)
+	}
+
+	if e.logger != nil {
+		attrs := []any{"format", e.Name(), "files", done.Files, "bytes", done.Bytes, "duration", done.Elapsed}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+
+		e.logger.Debug("exported coverage", attrs...)
+	}
+
+	return err
+}
+
+// progressWriter reports the bytes written through it.
+type progressWriter struct {
+	w        io.Writer
+	start    time.Time
+	bytes    int64
+	progress gocovparser.ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.bytes += int64(n)
+
+	if w.progress != nil {
+		w.progress(w.snapshot())
+	}
+
+	return n, err
+}
+
+func (w *progressWriter) snapshot() gocovparser.Progress {
+	return gocovparser.Progress{
+		Operation: gocovparser.OperationExport,
+		Bytes:     w.bytes,
+		Elapsed:   time.Since(w.start),
+	}
+}
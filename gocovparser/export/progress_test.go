@@ -0,0 +1,63 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records each log as a `msg key=value ...` line.
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) {
+	l.record(msg, args)
+}
+
+func (l *recordingLogger) Info(msg string, args ...any) {
+	l.record(msg, args)
+}
+
+func (l *recordingLogger) record(msg string, args []any) {
+	line := msg
+	for i := 0; i+1 < len(args); i += 2 {
+		line += fmt.Sprintf(" %v=%v", args[i], args[i+1])
+	}
+
+	l.lines = append(l.lines, line)
+}
+
+func TestInstrumentReportsExportProgress(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	exporter, err := export.Lookup("lcov")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	logger := &recordingLogger{}
+	last := gocovparser.Progress{}
+
+	// ACT
+	err = export.Instrument(exporter, logger, func(progress gocovparser.Progress) {
+		last = progress
+	}).Write(&out, items, nil)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, gocovparser.OperationExport, last.Operation)
+	assert.True(t, last.Done)
+	assert.Equal(t, len(items), last.Files)
+	assert.EqualValues(t, out.Len(), last.Bytes)
+	assert.Contains(t, strings.Join(logger.lines, "\n"), "exported coverage format=lcov")
+}
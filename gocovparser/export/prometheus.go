@@ -0,0 +1,172 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const defaultMetricsNamespace = "gocovparser"
+
+// prometheusEscaper escapes label values of the Prometheus text exposition format.
+var prometheusEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// PrometheusOption configures the Prometheus metrics export.
+type PrometheusOption func(*prometheusOptions)
+
+type prometheusOptions struct {
+	namespace string
+	labels    map[string]string
+}
+
+// WithMetricsNamespace sets the prefix of the metric names. Defaults to "gocovparser".
+func WithMetricsNamespace(namespace string) PrometheusOption {
+	return func(opts *prometheusOptions) {
+		opts.namespace = namespace
+	}
+}
+
+// WithConstLabels adds the labels (e.g. the repository or branch) to every metric.
+func WithConstLabels(labels map[string]string) PrometheusOption {
+	return func(opts *prometheusOptions) {
+		for name, value := range labels {
+			opts.labels[name] = value
+		}
+	}
+}
+
+// metricFamily is a gauge and its samples, by label set.
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels string
+	value  float64
+}
+
+// WritePrometheusMetrics writes the total and per package coverage ratio, statements and covered statements as
+// gauges in the Prometheus text exposition format, e.g. to serve them on a /metrics endpoint or to push them to
+// a Pushgateway. Packages are labeled with `package`.
+func WritePrometheusMetrics(w io.Writer, items []gocovparser.Coverage, opts ...PrometheusOption) error {
+	options := prometheusOptions{namespace: defaultMetricsNamespace, labels: map[string]string{}}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	total := gocovparser.GetTotalCoverageBreakdown(items)
+	packages := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return gocovparser.PackageParseGroup.Key(cov)
+	})
+
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	packageRatio := metricFamily{
+		name: "package_coverage_ratio",
+		help: "Ratio (0 to 1) of statements covered by tests, per package.",
+	}
+	packageStatements := metricFamily{name: "package_statements", help: "Number of statements, per package."}
+	packageCovered := metricFamily{
+		name: "package_covered_statements",
+		help: "Number of statements covered by tests, per package.",
+	}
+
+	for _, name := range names {
+		detail := packages[name]
+		labels := options.labelSet("package", name)
+
+		packageRatio.samples = append(packageRatio.samples, metricSample{labels, detail.Percent})
+		packageStatements.samples = append(packageStatements.samples, metricSample{labels, float64(detail.Total)})
+		packageCovered.samples = append(packageCovered.samples, metricSample{labels, float64(detail.Covered)})
+	}
+
+	totalLabels := options.labelSet("", "")
+	families := []metricFamily{
+		{
+			name:    "coverage_ratio",
+			help:    "Ratio (0 to 1) of statements covered by tests.",
+			samples: []metricSample{{totalLabels, total.Coverage}},
+		},
+		{
+			name:    "statements",
+			help:    "Number of statements.",
+			samples: []metricSample{{totalLabels, float64(total.Statements)}},
+		},
+		{
+			name:    "covered_statements",
+			help:    "Number of statements covered by tests.",
+			samples: []metricSample{{totalLabels, float64(total.CoveredStatements)}},
+		},
+		packageRatio,
+		packageStatements,
+		packageCovered,
+	}
+
+	buf := bufio.NewWriter(w)
+
+	for _, family := range families {
+		name := family.name
+		if options.namespace != "" {
+			name = options.namespace + "_" + name
+		}
+
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s gauge\n", name, family.help, name)
+
+		for _, sample := range family.samples {
+			fmt.Fprintf(buf, "%s%s %s\n", name, sample.labels, strconv.FormatFloat(sample.value, 'g', -1, 64))
+		}
+	}
+
+	if err := buf.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write prometheus metrics")
+	}
+
+	return nil
+}
+
+// labelSet formats the constant labels and the extra label, if any, sorted by name (e.g. `{package="a",repo="b"}`).
+func (o prometheusOptions) labelSet(name, value string) string {
+	labels := make(map[string]string, len(o.labels)+1)
+	for key, labelValue := range o.labels {
+		labels[key] = labelValue
+	}
+
+	if name != "" {
+		labels[name] = value
+	}
+
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+`="`+prometheusEscaper.Replace(labels[key])+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,52 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWritePrometheusMetrics(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WritePrometheusMetrics(
+		&buf, items, export.WithMetricsNamespace("cov"), export.WithConstLabels(map[string]string{"repo": `a"b`}),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected := `# HELP cov_coverage_ratio Ratio (0 to 1) of statements covered by tests.
+# TYPE cov_coverage_ratio gauge
+cov_coverage_ratio{repo="a\"b"} 0.75
+# HELP cov_statements Number of statements.
+# TYPE cov_statements gauge
+cov_statements{repo="a\"b"} 4
+# HELP cov_covered_statements Number of statements covered by tests.
+# TYPE cov_covered_statements gauge
+cov_covered_statements{repo="a\"b"} 3
+# HELP cov_package_coverage_ratio Ratio (0 to 1) of statements covered by tests, per package.
+# TYPE cov_package_coverage_ratio gauge
+cov_package_coverage_ratio{package="github.com/heynemann/go-cov-parser/gocovparser",repo="a\"b"} 0.6666666666666666
+cov_package_coverage_ratio{package="github.com/heynemann/go-cov-parser/gocovparser/export",repo="a\"b"} 1
+# HELP cov_package_statements Number of statements, per package.
+# TYPE cov_package_statements gauge
+cov_package_statements{package="github.com/heynemann/go-cov-parser/gocovparser",repo="a\"b"} 3
+cov_package_statements{package="github.com/heynemann/go-cov-parser/gocovparser/export",repo="a\"b"} 1
+# HELP cov_package_covered_statements Number of statements covered by tests, per package.
+# TYPE cov_package_covered_statements gauge
+cov_package_covered_statements{package="github.com/heynemann/go-cov-parser/gocovparser",repo="a\"b"} 2
+cov_package_covered_statements{package="github.com/heynemann/go-cov-parser/gocovparser/export",repo="a\"b"} 1
+`
+	assert.Equal(t, expected, buf.String())
+}
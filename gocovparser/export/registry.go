@@ -0,0 +1,250 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// Options are the settings of an export as key value pairs (e.g. `level=warning`), so they can be given on the
+// command line whatever the format. Exporters ignore the options they don't support.
+type Options map[string]string
+
+// Exporter writes coverage in a report format.
+type Exporter interface {
+	// Name of the format, as given to the `--format` flag of the command line.
+	Name() string
+
+	// Write writes the coverage items in the format.
+	Write(w io.Writer, items []gocovparser.Coverage, opts Options) error
+}
+
+type exporterFunc struct {
+	name  string
+	write func(w io.Writer, items []gocovparser.Coverage, opts Options) error
+}
+
+var _ Exporter = (*exporterFunc)(nil)
+
+// NewExporter returns an exporter of the named format writing coverage with the write function.
+func NewExporter(name string, write func(w io.Writer, items []gocovparser.Coverage, opts Options) error) Exporter {
+	return &exporterFunc{name: name, write: write}
+}
+
+func (e *exporterFunc) Name() string {
+	return e.name
+}
+
+func (e *exporterFunc) Write(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+	return e.write(w, items, opts)
+}
+
+// percentScale converts percentages given as options to ratios.
+const percentScale = 100
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Exporter{}
+)
+
+func init() {
+	for _, exporter := range builtinExporters() {
+		registry[exporter.Name()] = exporter
+	}
+}
+
+// Register adds the exporter to the registry, so it can be looked up by its name.
+// Registering a name twice fails with ErrFormatExists.
+func Register(exporter Exporter) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, found := registry[exporter.Name()]; found {
+		return errors.Wrapf(ErrFormatExists, "%q", exporter.Name())
+	}
+
+	registry[exporter.Name()] = exporter
+
+	return nil
+}
+
+// Lookup returns the registered exporter of the format, or fails with ErrUnknownFormat.
+func Lookup(name string) (Exporter, error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	exporter, found := registry[name]
+	if !found {
+		return nil, errors.Wrapf(ErrUnknownFormat, "%q", name)
+	}
+
+	return exporter, nil
+}
+
+// Names returns the sorted names of the registered formats.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// builtinExporters returns the formats of this package. Their options are:
+//   - cobertura: source, the directory file names are relative to.
+//   - sonar: strip-prefix, written instead of module relative paths.
+//   - github, checkrun, gitlab and bitbucket-annotations: level (error, warning or notice), path-prefix and diff, a
+//     unified diff file (as generated by `git diff --no-prefix`) restricting the annotations to the changed lines.
+//   - bitbucket: minimum, the total coverage percentage (0-100) below which the report fails.
+//   - protobuf: commit and timestamp (RFC 3339) of the run.
+//   - prometheus: namespace, the prefix of the metric names.
+//   - coverage-gutters and editor-json: workspace, the folder opened in the editor, and module-dir, the folder of
+//     the module in it.
+func builtinExporters() []Exporter {
+	return []Exporter{
+		NewExporter("lcov", func(w io.Writer, items []gocovparser.Coverage, _ Options) error {
+			return WriteLCOV(w, items)
+		}),
+		NewExporter("cobertura", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			coberturaOpts := []CoberturaOption{}
+			if source, found := opts["source"]; found {
+				coberturaOpts = append(coberturaOpts, WithSource(source))
+			}
+
+			return WriteCobertura(w, items, coberturaOpts...)
+		}),
+		NewExporter("codecov", func(w io.Writer, items []gocovparser.Coverage, _ Options) error {
+			return WriteCodecov(w, items)
+		}),
+		NewExporter("sonar", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			return WriteSonarGenericCoverage(w, items, WithStripPrefix(opts["strip-prefix"]))
+		}),
+		NewExporter("github", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			annotationOpts, err := opts.annotationOptions()
+			if err != nil {
+				return err
+			}
+
+			return WriteGitHubAnnotations(w, items, annotationOpts...)
+		}),
+		NewExporter("checkrun", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			annotationOpts, err := opts.annotationOptions()
+			if err != nil {
+				return err
+			}
+
+			return WriteCheckRunAnnotations(w, items, annotationOpts...)
+		}),
+		NewExporter("gitlab", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			annotationOpts, err := opts.annotationOptions()
+			if err != nil {
+				return err
+			}
+
+			return WriteGitLabCodeQuality(w, items, annotationOpts...)
+		}),
+		NewExporter("bitbucket", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			minimum := 0.0
+
+			if value, found := opts["minimum"]; found {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return errors.Wrapf(ErrInvalidOption, "minimum %q is not a number", value)
+				}
+
+				minimum = parsed / percentScale
+			}
+
+			return WriteBitbucketReport(w, items, minimum)
+		}),
+		NewExporter("bitbucket-annotations", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			annotationOpts, err := opts.annotationOptions()
+			if err != nil {
+				return err
+			}
+
+			return WriteBitbucketAnnotations(w, items, annotationOpts...)
+		}),
+		NewExporter("protobuf", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			rowOpts := []RowOption{WithRunCommit(opts["commit"])}
+
+			if value, found := opts["timestamp"]; found {
+				timestamp, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					return errors.Wrapf(ErrInvalidOption, "timestamp %q is not an RFC 3339 time", value)
+				}
+
+				rowOpts = append(rowOpts, WithRunTimestamp(timestamp))
+			}
+
+			return WriteProtobufRows(w, items, rowOpts...)
+		}),
+		NewExporter("prometheus", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			prometheusOpts := []PrometheusOption{}
+			if namespace, found := opts["namespace"]; found {
+				prometheusOpts = append(prometheusOpts, WithMetricsNamespace(namespace))
+			}
+
+			return WritePrometheusMetrics(w, items, prometheusOpts...)
+		}),
+		NewExporter("coverage-gutters", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			return WriteCoverageGutters(w, items, opts.editorOptions()...)
+		}),
+		NewExporter("editor-json", func(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+			return WriteEditorJSON(w, items, opts.editorOptions()...)
+		}),
+		NewExporter("json", func(w io.Writer, items []gocovparser.Coverage, _ Options) error {
+			breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(gocovparser.Results{Coverage: items, Breakdown: &breakdown}); err != nil {
+				return errors.Wrap(err, "failed to write json results")
+			}
+
+			return nil
+		}),
+	}
+}
+
+func (o Options) annotationOptions() ([]AnnotationOption, error) {
+	opts := []AnnotationOption{WithPathPrefix(o["path-prefix"])}
+
+	if level, found := o["level"]; found {
+		opts = append(opts, WithAnnotationLevel(level))
+	}
+
+	if diff, found := o["diff"]; found {
+		file, err := os.Open(diff)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open diff %q", diff)
+		}
+		defer file.Close()
+
+		changes, err := gocovparser.ParseUnifiedDiff(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse diff %q", diff)
+		}
+
+		opts = append(opts, WithChangedLines(changes))
+	}
+
+	return opts, nil
+}
+
+func (o Options) editorOptions() []EditorOption {
+	return []EditorOption{WithWorkspaceFolder(o["workspace"]), WithModuleFolder(o["module-dir"])}
+}
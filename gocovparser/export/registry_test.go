@@ -0,0 +1,158 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinExportersAreRegistered(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	names := export.Names()
+
+	assert.Subset(t, names, []string{
+		"bitbucket", "bitbucket-annotations", "checkrun", "cobertura", "codecov", "coverage-gutters", "editor-json", "github", "gitlab", "json", "lcov", "prometheus", "protobuf", "sonar",
+	})
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			exporter, err := export.Lookup(name)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+
+			// ACT
+			err = exporter.Write(&buf, items, export.Options{})
+
+			// ASSERT
+			require.NoError(t, err)
+			assert.Equal(t, name, exporter.Name())
+			assert.NotEmpty(t, buf.String())
+		})
+	}
+}
+
+func TestBuiltinExporterOptions(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	github, err := export.Lookup("github")
+	require.NoError(t, err)
+
+	bitbucket, err := export.Lookup("bitbucket")
+	require.NoError(t, err)
+
+	var annotations, report bytes.Buffer
+
+	// ACT
+	err = github.Write(&annotations, items, export.Options{"level": "notice", "path-prefix": "module"})
+	require.NoError(t, err)
+
+	err = bitbucket.Write(&report, items, export.Options{"minimum": "80"})
+	require.NoError(t, err)
+
+	invalidErr := bitbucket.Write(io.Discard, items, export.Options{"minimum": "high"})
+
+	// ASSERT
+	assert.Contains(t, annotations.String(), "::notice file=module/gocovparser/core.go,line=14")
+	assert.Contains(t, report.String(), `"result":"FAILED"`)
+	assert.ErrorIs(t, invalidErr, export.ErrInvalidOption)
+}
+
+func TestCanRegisterCustomExporter(t *testing.T) {
+	custom := export.NewExporter("test-files", func(w io.Writer, items []gocovparser.Coverage, opts export.Options) error {
+		for _, cov := range items {
+			if _, err := io.WriteString(w, opts["prefix"]+cov.Path+"\n"); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	// ACT
+	err := export.Register(custom)
+	duplicateErr := export.Register(custom)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.ErrorIs(t, duplicateErr, export.ErrFormatExists)
+	assert.Contains(t, export.Names(), "test-files")
+
+	exporter, err := export.Lookup("test-files")
+	require.NoError(t, err)
+
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Write(&buf, items, export.Options{"prefix": "> "}))
+	assert.Equal(t, "> gocovparser/core.go\n> gocovparser/export/lines.go\n", buf.String())
+}
+
+func TestLookupFailsForUnknownFormat(t *testing.T) {
+	// ACT
+	_, err := export.Lookup("unknown")
+
+	// ASSERT
+	assert.ErrorIs(t, err, export.ErrUnknownFormat)
+}
+
+func TestAnnotationExportersRestrictAnnotationsToDiff(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	diff := filepath.Join(t.TempDir(), "changes.diff")
+	require.NoError(t, os.WriteFile(diff, []byte(`diff --git gocovparser/core.go gocovparser/core.go
+--- gocovparser/core.go
++++ gocovparser/core.go
+@@ -20,0 +20,1 @@
++	return nil
+`), 0o600))
+
+	github, err := export.Lookup("github")
+	require.NoError(t, err)
+
+	var annotations bytes.Buffer
+
+	// ACT
+	err = github.Write(&annotations, items, export.Options{"diff": diff})
+	missingErr := github.Write(io.Discard, items, export.Options{"diff": filepath.Join(t.TempDir(), "missing.diff")})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t,
+		"::error file=gocovparser/core.go,line=20,endLine=20,title=Uncovered code::Line 20 is not covered by tests\n",
+		annotations.String(),
+	)
+	assert.Error(t, missingErr)
+}
+
+func TestAnnotationExportersRejectUnknownLevels(t *testing.T) {
+	items, err := gocovparser.Parse(annotationsFixture)
+	require.NoError(t, err)
+
+	for _, name := range []string{"github", "checkrun", "gitlab", "bitbucket-annotations"} {
+		t.Run(name, func(t *testing.T) {
+			exporter, err := export.Lookup(name)
+			require.NoError(t, err)
+
+			// ACT
+			err = exporter.Write(io.Discard, items, export.Options{"level": "fatal"})
+
+			// ASSERT
+			assert.ErrorIs(t, err, export.ErrInvalidOption)
+		})
+	}
+}
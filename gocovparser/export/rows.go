@@ -0,0 +1,136 @@
+package export
+
+import (
+	"encoding/binary"
+	"io"
+	"path"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// Protobuf wire types and field numbers of the CoverageRow message, see coverage_row.proto.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+	fieldShift = 3
+
+	rowFileName   = 1
+	rowRepo       = 2
+	rowPath       = 3
+	rowStatements = 4
+	rowCovered    = 5
+	rowCommit     = 6
+	rowTimestamp  = 7
+)
+
+// CoverageRow is the coverage of a file in a run, as a flat row for data warehouses.
+type CoverageRow struct {
+	FileName   string
+	Repo       string
+	Path       string
+	Statements int
+	Covered    int
+	Commit     string
+	Timestamp  time.Time
+}
+
+// RowOption configures the run the rows belong to.
+type RowOption func(*rowOptions)
+
+type rowOptions struct {
+	commit    string
+	timestamp time.Time
+}
+
+// WithRunCommit sets the commit the coverage was collected at.
+func WithRunCommit(commit string) RowOption {
+	return func(opts *rowOptions) {
+		opts.commit = commit
+	}
+}
+
+// WithRunTimestamp sets the time of the run. It defaults to the time of the export.
+func WithRunTimestamp(timestamp time.Time) RowOption {
+	return func(opts *rowOptions) {
+		opts.timestamp = timestamp
+	}
+}
+
+// GetCoverageRows returns one row per file of the coverage items, stamped with the commit and time of the run.
+func GetCoverageRows(items []gocovparser.Coverage, opts ...RowOption) []CoverageRow {
+	options := rowOptions{timestamp: time.Now()}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rows := make([]CoverageRow, 0, len(items))
+
+	for _, cov := range gocovparser.SortCoverage(items) {
+		rows = append(rows, CoverageRow{
+			FileName:   cov.FileName,
+			Repo:       path.Join(cov.Host, cov.Owner, cov.Repo),
+			Path:       cov.Path,
+			Statements: cov.TotalStatements(),
+			Covered:    cov.CoveredStatements(),
+			Commit:     options.commit,
+			Timestamp:  options.timestamp.UTC(),
+		})
+	}
+
+	return rows
+}
+
+// WriteProtobufRows writes a row per file as CoverageRow protobuf messages (see coverage_row.proto), each
+// prefixed with its varint encoded length, for ingestion into columnar stores such as BigQuery.
+func WriteProtobufRows(w io.Writer, items []gocovparser.Coverage, opts ...RowOption) error {
+	for _, row := range GetCoverageRows(items, opts...) {
+		message := row.appendProtobuf(nil)
+		delimited := binary.AppendUvarint(make([]byte, 0, len(message)+binary.MaxVarintLen64), uint64(len(message)))
+
+		if _, err := w.Write(append(delimited, message...)); err != nil {
+			return errors.Wrap(err, "failed to write protobuf rows")
+		}
+	}
+
+	return nil
+}
+
+// appendProtobuf appends the row encoded as a CoverageRow message. As in proto3, empty fields are left out.
+func (r CoverageRow) appendProtobuf(buf []byte) []byte {
+	buf = appendStringField(buf, rowFileName, r.FileName)
+	buf = appendStringField(buf, rowRepo, r.Repo)
+	buf = appendStringField(buf, rowPath, r.Path)
+	buf = appendVarintField(buf, rowStatements, uint64(r.Statements))
+	buf = appendVarintField(buf, rowCovered, uint64(r.Covered))
+	buf = appendStringField(buf, rowCommit, r.Commit)
+
+	if !r.Timestamp.IsZero() {
+		buf = appendVarintField(buf, rowTimestamp, uint64(r.Timestamp.UnixMicro()))
+	}
+
+	return buf
+}
+
+func appendStringField(buf []byte, field int, value string) []byte {
+	if value == "" {
+		return buf
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(field<<fieldShift|wireBytes))
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+
+	return append(buf, value...)
+}
+
+func appendVarintField(buf []byte, field int, value uint64) []byte {
+	if value == 0 {
+		return buf
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(field<<fieldShift|wireVarint))
+
+	return binary.AppendUvarint(buf, value)
+}
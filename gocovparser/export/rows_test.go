@@ -0,0 +1,104 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanGetCoverageRows(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// ACT
+	got := export.GetCoverageRows(items, export.WithRunCommit("abc123"), export.WithRunTimestamp(timestamp))
+
+	// ASSERT
+	assert.Equal(t, []export.CoverageRow{
+		{
+			FileName:   "github.com/heynemann/go-cov-parser/gocovparser/core.go",
+			Repo:       "github.com/heynemann/go-cov-parser",
+			Path:       "gocovparser/core.go",
+			Statements: 3,
+			Covered:    2,
+			Commit:     "abc123",
+			Timestamp:  timestamp,
+		},
+		{
+			FileName:   "github.com/heynemann/go-cov-parser/gocovparser/export/lines.go",
+			Repo:       "github.com/heynemann/go-cov-parser",
+			Path:       "gocovparser/export/lines.go",
+			Statements: 1,
+			Covered:    1,
+			Commit:     "abc123",
+			Timestamp:  timestamp,
+		},
+	}, got)
+}
+
+func TestCanWriteProtobufRows(t *testing.T) {
+	items, err := gocovparser.Parse("mode: set\nexample.com/o/r/a.go:1.1,2.2 3 1\nexample.com/o/r/b.go:1.1,2.2 1 0\n")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteProtobufRows(&buf, items, export.WithRunCommit("abc"), export.WithRunTimestamp(time.Unix(1, 0)))
+
+	// ASSERT
+	require.NoError(t, err)
+
+	first := []byte{58, 0x0a, 20}
+	first = append(first, "example.com/o/r/a.go"...)
+	first = append(first, 0x12, 15)
+	first = append(first, "example.com/o/r"...)
+	first = append(first, 0x1a, 4)
+	first = append(first, "a.go"...)
+	first = append(first, 0x20, 3, 0x28, 3, 0x32, 3)
+	first = append(first, "abc"...)
+	first = append(first, 0x38, 0xc0, 0x84, 0x3d)
+
+	// covered is zero, so it is left out
+	second := []byte{56, 0x0a, 20}
+	second = append(second, "example.com/o/r/b.go"...)
+	second = append(second, 0x12, 15)
+	second = append(second, "example.com/o/r"...)
+	second = append(second, 0x1a, 4)
+	second = append(second, "b.go"...)
+	second = append(second, 0x20, 1, 0x32, 3)
+	second = append(second, "abc"...)
+	second = append(second, 0x38, 0xc0, 0x84, 0x3d)
+
+	assert.Equal(t, append(first, second...), buf.Bytes())
+}
+
+func TestProtobufExporterOptions(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	exporter, err := export.Lookup("protobuf")
+	require.NoError(t, err)
+
+	var withOptions, direct bytes.Buffer
+
+	// ACT
+	err = exporter.Write(&withOptions, items, export.Options{"commit": "abc", "timestamp": "2024-05-01T12:00:00Z"})
+	invalidErr := exporter.Write(&bytes.Buffer{}, items, export.Options{"timestamp": "yesterday"})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, export.WriteProtobufRows(
+		&direct, items, export.WithRunCommit("abc"), export.WithRunTimestamp(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+	))
+	assert.Equal(t, direct.Bytes(), withOptions.Bytes())
+	assert.ErrorIs(t, invalidErr, export.ErrInvalidOption)
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+type sonarCoverage struct {
+	XMLName xml.Name    `xml:"coverage"`
+	Version int         `xml:"version,attr"`
+	Files   []sonarFile `xml:"file"`
+}
+
+type sonarFile struct {
+	Path  string      `xml:"path,attr"`
+	Lines []sonarLine `xml:"lineToCover"`
+}
+
+type sonarLine struct {
+	LineNumber      int  `xml:"lineNumber,attr"`
+	Covered         bool `xml:"covered,attr"`
+	BranchesToCover int  `xml:"branchesToCover,attr,omitempty"`
+
+	// CoveredBranches is a pointer so 0 is written for lines with branches: SonarQube requires both attributes.
+	CoveredBranches *int `xml:"coveredBranches,attr,omitempty"`
+}
+
+// SonarOption configures the SonarQube generic coverage export.
+type SonarOption func(*sonarOptions)
+
+type sonarOptions struct {
+	stripPrefix string
+}
+
+// WithStripPrefix writes file paths as the coverage file names without prefix (e.g. the module path),
+// instead of the path relative to the module root.
+func WithStripPrefix(prefix string) SonarOption {
+	return func(opts *sonarOptions) {
+		opts.stripPrefix = prefix
+	}
+}
+
+// WriteSonarGenericCoverage writes the coverage items in the SonarQube generic test coverage XML format.
+// As in the Cobertura export, lines spanned by several blocks report each block as a branch.
+func WriteSonarGenericCoverage(w io.Writer, items []gocovparser.Coverage, opts ...SonarOption) error {
+	options := sonarOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	report := sonarCoverage{Version: 1}
+
+	for _, cov := range items {
+		file := sonarFile{Path: options.pathOf(cov)}
+
+		for _, line := range linesOf(cov) {
+			sonarLine := sonarLine{
+				LineNumber: line.number,
+				Covered:    line.hits > 0,
+			}
+
+			if line.blocks > 1 {
+				sonarLine.BranchesToCover = line.blocks
+				coveredBranches := line.coveredBlocks
+				sonarLine.CoveredBranches = &coveredBranches
+			}
+
+			file.Lines = append(file.Lines, sonarLine)
+		}
+
+		report.Files = append(report.Files, file)
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return errors.Wrap(err, "failed to write sonar coverage report")
+	}
+
+	return nil
+}
+
+func (o sonarOptions) pathOf(cov gocovparser.Coverage) string {
+	if o.stripPrefix == "" {
+		return gocovparser.CanonicalPath(cov.Path)
+	}
+
+	return strings.TrimPrefix(strings.TrimPrefix(gocovparser.CanonicalPath(cov.FileName), o.stripPrefix), "/")
+}
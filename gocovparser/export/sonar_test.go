@@ -0,0 +1,87 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanWriteSonarGenericCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteSonarGenericCoverage(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+
+	expected := `<coverage version="1">
+  <file path="gocovparser/core.go">
+    <lineToCover lineNumber="10" covered="true"></lineToCover>
+    <lineToCover lineNumber="11" covered="true"></lineToCover>
+    <lineToCover lineNumber="12" covered="true" branchesToCover="2" coveredBranches="1"></lineToCover>
+    <lineToCover lineNumber="13" covered="false"></lineToCover>
+  </file>
+  <file path="gocovparser/export/lines.go">
+    <lineToCover lineNumber="5" covered="true"></lineToCover>
+  </file>
+</coverage>`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestSonarGenericCoverageWritesUncoveredBranches(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.20 2 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:12.20,13.3 1 0
+`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteSonarGenericCoverage(&buf, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(
+		t, buf.String(),
+		`<lineToCover lineNumber="12" covered="false" branchesToCover="2" coveredBranches="0"></lineToCover>`,
+	)
+	assert.Contains(t, buf.String(), `<lineToCover lineNumber="13" covered="false"></lineToCover>`)
+}
+
+func TestSonarGenericCoverageStripsPathPrefix(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = export.WriteSonarGenericCoverage(&buf, items, export.WithStripPrefix("github.com/heynemann"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `<file path="go-cov-parser/gocovparser/core.go">`)
+}
+
+func TestWriteSonarGenericCoverageFailsIfWriterFails(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	// ACT
+	err = export.WriteSonarGenericCoverage(failingWriter{}, items)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+type tracedExporter struct {
+	Exporter
+
+	ctx       context.Context
+	telemetry gocovparser.Telemetry
+}
+
+var _ Exporter = (*tracedExporter)(nil)
+
+// Trace wraps the exporter so each export is traced in a gocovparser.SpanExport span, child of the span of ctx,
+// and its metrics recorded.
+func Trace(ctx context.Context, exporter Exporter, telemetry gocovparser.Telemetry) Exporter {
+	return &tracedExporter{Exporter: exporter, ctx: ctx, telemetry: telemetry}
+}
+
+func (e *tracedExporter) Write(w io.Writer, items []gocovparser.Coverage, opts Options) error {
+	_, operation := e.telemetry.Start(e.ctx, gocovparser.SpanExport)
+	writer := &progressWriter{w: w, start: time.Now()}
+
+	err := e.Exporter.Write(writer, items, opts)
+
+	operation.End(
+		err,
+		gocovparser.Attribute{Key: gocovparser.AttributeFormat, Value: e.Name()},
+		gocovparser.Attribute{Key: gocovparser.AttributeFiles, Value: len(items)},
+		gocovparser.Attribute{Key: gocovparser.AttributeBytes, Value: writer.bytes},
+	)
+
+	return err
+}
@@ -0,0 +1,45 @@
+package export_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMeter struct {
+	values map[string]float64
+	attrs  []gocovparser.Attribute
+}
+
+func (m *recordingMeter) Record(_ context.Context, metric string, value float64, attrs ...gocovparser.Attribute) {
+	m.values[metric] += value
+	m.attrs = attrs
+}
+
+func TestTraceRecordsExportMetrics(t *testing.T) {
+	items, err := gocovparser.Parse(exportFixture)
+	require.NoError(t, err)
+
+	exporter, err := export.Lookup("lcov")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	meter := &recordingMeter{values: map[string]float64{}}
+
+	// ACT
+	err = export.Trace(context.Background(), exporter, gocovparser.Telemetry{Meter: meter}).Write(&out, items, nil)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, float64(len(items)), meter.values[gocovparser.MetricFiles])
+	assert.Equal(t, float64(out.Len()), meter.values[gocovparser.MetricBytes])
+	assert.Contains(t, meter.attrs, gocovparser.Attribute{Key: gocovparser.AttributeOperation, Value: gocovparser.SpanExport})
+}
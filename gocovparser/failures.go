@@ -1,6 +1,100 @@
 package gocovparser
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // ErrInvalidCoverageData happens when the data passed to gocovparser is either blank or not a coverage.out file content.
 var ErrInvalidCoverageData = errors.New("invalid coverage data - unable to parse")
+
+// ErrInconsistentCoverageBlocks happens when the same block is reported with different statement counts.
+var ErrInconsistentCoverageBlocks = errors.New("inconsistent coverage blocks - unable to merge")
+
+// ErrInconsistentCoverageMode happens when merging coverage collected with different modes.
+var ErrInconsistentCoverageMode = errors.New("inconsistent coverage modes - unable to merge")
+
+// ErrInvalidDiff happens when the diff passed to gocovparser is not a valid unified diff.
+var ErrInvalidDiff = errors.New("invalid unified diff - unable to parse")
+
+// ErrInvalidGitRef happens when a git reference passed to gocovparser starts with a dash, like a git option.
+var ErrInvalidGitRef = errors.New("invalid git reference - unable to diff")
+
+// ErrInvalidBlame happens when the output of `git blame --porcelain` can't be parsed.
+var ErrInvalidBlame = errors.New("invalid git blame output - unable to parse")
+
+// ErrUnsupportedSchemaVersion happens when serialized results were written with an unknown schema version.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version - unable to load results")
+
+// ErrGoModNotFound happens when no go.mod file is found in a directory or any of its parents.
+var ErrGoModNotFound = errors.New("go.mod not found")
+
+// ErrGoWorkNotFound happens when no go.work file is found in a directory or any of its parents.
+var ErrGoWorkNotFound = errors.New("go.work not found")
+
+// ErrDuplicateRepoFile happens when a file is added to a MultiRepoReport under two repository labels.
+var ErrDuplicateRepoFile = errors.New("file belongs to another repository - unable to add")
+
+// ErrUnknownParseGroup happens when ParseGroupByName receives a name that isn't a known parse group.
+var ErrUnknownParseGroup = errors.New("unknown parse group")
+
+// ErrStopWalk is returned by the callbacks of WalkBlocks and WalkLines to stop walking. The walk returns nil.
+var ErrStopWalk = errors.New("stop walk")
+
+// ErrSkipFile is returned by the callbacks of WalkBlocks and WalkLines to skip the rest of the current file.
+var ErrSkipFile = errors.New("skip file")
+
+// ErrMalformedLine happens when a line of the coverage data is neither a mode line nor a coverage block.
+// It matches ErrInvalidCoverageData with errors.Is.
+type ErrMalformedLine struct {
+	Line    int
+	Content string
+}
+
+func (e ErrMalformedLine) Error() string {
+	return fmt.Sprintf("malformed coverage line %d - unable to parse %q", e.Line, e.Content)
+}
+
+// Is reports malformed lines as invalid coverage data.
+func (e ErrMalformedLine) Is(target error) bool {
+	return target == ErrInvalidCoverageData
+}
+
+// ErrUnparsableFileName happens when a coverage file name can't be split in its module and path parts.
+// It matches ErrInvalidCoverageData with errors.Is.
+type ErrUnparsableFileName struct {
+	FileName string
+}
+
+func (e ErrUnparsableFileName) Error() string {
+	return fmt.Sprintf("unparsable coverage file name - %q", e.FileName)
+}
+
+// Is reports unparsable file names as invalid coverage data.
+func (e ErrUnparsableFileName) Is(target error) bool {
+	return target == ErrInvalidCoverageData
+}
+
+// ParseErrors are the errors skipped while parsing with WithLenientParsing.
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("%d coverage errors skipped: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Is returns whether any of the skipped errors matches target.
+func (e ParseErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
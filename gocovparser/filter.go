@@ -1,7 +1,9 @@
 package gocovparser
 
 import (
-	"fmt"
+	"bufio"
+	"os"
+	"path"
 	"regexp"
 	"strings"
 )
@@ -20,7 +22,7 @@ func PackageExcludeFilter(packageName string) Filter {
 }
 
 func (f *packageExcludeFilter) FilterCoverage(cov Coverage) bool {
-	fullAddr := fmt.Sprintf("%s/%s/%s/%s", cov.Host, cov.Owner, cov.Repo, cov.Path)
+	fullAddr := fileLocation{host: cov.Host, owner: cov.Owner, repo: cov.Repo, path: cov.Path}.fullPath()
 
 	return !strings.HasPrefix(fullAddr, f.packageName)
 }
@@ -68,3 +70,189 @@ func FilterCoverage(items []Coverage, filters ...Filter) ([]Coverage, error) {
 
 	return result, nil
 }
+
+type globExcludeFilter struct {
+	glob *regexp.Regexp
+}
+
+var _ Filter = (*globExcludeFilter)(nil)
+
+// GlobExcludeFilter excludes any coverage whose path or file name matches the glob pattern.
+// `*` and `?` match within a path segment and `**` matches any number of segments (e.g. `**/*.pb.go`).
+func GlobExcludeFilter(pattern string) Filter {
+	return &globExcludeFilter{
+		glob: regexp.MustCompile(globToRegex(pattern)),
+	}
+}
+
+func (f *globExcludeFilter) FilterCoverage(cov Coverage) bool {
+	return !f.glob.MatchString(cov.Path) && !f.glob.MatchString(cov.FileName)
+}
+
+func globToRegex(pattern string) string {
+	var builder strings.Builder
+
+	builder.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			builder.WriteString("[^/]*")
+		case pattern[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	builder.WriteString("$")
+
+	return builder.String()
+}
+
+// VendorExcludeFilter excludes any coverage of vendored code (files under a vendor directory).
+func VendorExcludeFilter() Filter {
+	return GlobExcludeFilter("**/vendor/**")
+}
+
+var generatedCodeRegex = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+type generatedCodeExcludeFilter struct {
+	sourceRoot string
+}
+
+var _ Filter = (*generatedCodeExcludeFilter)(nil)
+
+// GeneratedCodeExcludeFilter excludes any coverage of generated files. Classified coverage (see ClassifyCoverage) is
+// excluded when Generated. Otherwise files named as cgo shims or generator outputs (see IsGeneratedFileName) are
+// excluded, as are files with a `// Code generated ... DO NOT EDIT.` header, as defined by the go generate
+// conventions. Source files are read from their SourcePath under sourceRoot; files that can't be read are kept.
+func GeneratedCodeExcludeFilter(sourceRoot string) Filter {
+	return &generatedCodeExcludeFilter{
+		sourceRoot: sourceRoot,
+	}
+}
+
+func (f *generatedCodeExcludeFilter) FilterCoverage(cov Coverage) bool {
+	if cov.Classified {
+		return !cov.Generated
+	}
+
+	if IsGeneratedFileName(cov.FileName) {
+		return false
+	}
+
+	generated, err := isGeneratedFile(SourcePath(cov, f.sourceRoot))
+	if err != nil {
+		return true
+	}
+
+	return !generated
+}
+
+type missingFileExcludeFilter struct{}
+
+var _ Filter = (*missingFileExcludeFilter)(nil)
+
+// MissingFileExcludeFilter excludes any coverage of files classified as missing from the source tree, i.e. not
+// Exists (see ClassifyCoverage), such as deleted files still listed in stale profiles. Coverage that wasn't
+// classified is kept.
+func MissingFileExcludeFilter() Filter {
+	return &missingFileExcludeFilter{}
+}
+
+func (f *missingFileExcludeFilter) FilterCoverage(cov Coverage) bool {
+	return !cov.Classified || cov.Exists
+}
+
+// isGeneratedFile returns whether the go source file has a generated code comment before its package clause.
+func isGeneratedFile(filename string) (bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if generatedCodeRegex.MatchString(line) {
+			return true, nil
+		}
+
+		if strings.HasPrefix(line, "package ") {
+			return false, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+type packagePrefixFilter struct {
+	prefixes []string
+	keep     bool
+}
+
+var _ Filter = (*packagePrefixFilter)(nil)
+
+// SelectPackages returns the coverage of the packages under one of the import path prefixes, e.g.
+// `github.com/org/repo/internal/payment/...`. Prefixes must align on path segments, so `internal/pay` doesn't
+// select `internal/payment`. Prefixes are matched against the full import path of the package, and against its
+// path relative to the module (e.g. `internal/payment/...`). A trailing `/...` is optional.
+func SelectPackages(items []Coverage, prefixes ...string) []Coverage {
+	result, _ := FilterCoverage(items, &packagePrefixFilter{prefixes: packagePrefixes(prefixes), keep: true})
+
+	return result
+}
+
+// ExcludePackages returns the coverage of the packages not under any of the import path prefixes, matched as
+// SelectPackages does.
+func ExcludePackages(items []Coverage, prefixes ...string) []Coverage {
+	result, _ := FilterCoverage(items, &packagePrefixFilter{prefixes: packagePrefixes(prefixes), keep: false})
+
+	return result
+}
+
+func (f *packagePrefixFilter) FilterCoverage(cov Coverage) bool {
+	importPath := path.Dir(cov.FileName)
+	relativePath := path.Dir(cov.Path)
+
+	for _, prefix := range f.prefixes {
+		if hasPathPrefix(importPath, prefix) || hasPathPrefix(relativePath, prefix) {
+			return f.keep
+		}
+	}
+
+	return !f.keep
+}
+
+// packagePrefixes strips the `./`, `/...` and trailing slashes of package patterns.
+func packagePrefixes(patterns []string) []string {
+	prefixes := make([]string, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		prefix := strings.TrimSuffix(strings.TrimPrefix(CanonicalPath(pattern), "./"), "/...")
+		prefix = strings.TrimSuffix(prefix, "/")
+
+		if prefix == "..." || prefix == "." {
+			prefix = ""
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
+
+// hasPathPrefix returns whether the slash separated path is prefix, or under it. Every path is under "".
+func hasPathPrefix(value, prefix string) bool {
+	return prefix == "" || value == prefix || strings.HasPrefix(value, prefix+"/")
+}
@@ -3,9 +3,12 @@ package gocovparser_test
 //revive:disable:add-constant
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,3 +113,109 @@ func TestFileFilter(t *testing.T) {
 // coverage := got["total"]["total"]
 // require.Equal(t, float64(6919), math.Round(coverage*10000.0))
 // }
+
+func TestGlobFilter(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1
+github.com/heynemann/go-cov-parser/proto/api.pb.go:1.1,2.2 1 0
+github.com/heynemann/go-cov-parser/proto/v1/api.pb.go:1.1,2.2 1 0
+github.com/heynemann/go-cov-parser/mocks/mock_store.go:1.1,2.2 1 0
+github.com/heynemann/go-cov-parser/vendor/github.com/x/y/y.go:1.1,2.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.FilterCoverage(
+		items,
+		gocovparser.GlobExcludeFilter("**/*.pb.go"),
+		gocovparser.GlobExcludeFilter("mocks/mock_*.go"),
+		gocovparser.VendorExcludeFilter(),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "gocovparser/core.go", got[0].Path)
+}
+
+func TestGeneratedCodeFilter(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "pkg", "generated.go"),
+		[]byte("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pkg\n"),
+		0o600,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "pkg", "handwritten.go"),
+		[]byte("package pkg\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n"),
+		0o600,
+	))
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/pkg/generated.go:1.1,2.2 1 0
+github.com/heynemann/go-cov-parser/pkg/handwritten.go:1.1,2.2 1 1
+github.com/heynemann/go-cov-parser/pkg/missing.go:1.1,2.2 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.FilterCoverage(items, gocovparser.GeneratedCodeExcludeFilter(root))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "pkg/handwritten.go", got[0].Path)
+	require.Equal(t, "pkg/missing.go", got[1].Path)
+}
+
+const packagesFixture = `mode: set
+github.com/org/repo/internal/payment/charge.go:1.1,2.2 1 1
+github.com/org/repo/internal/payment/refund/refund.go:1.1,2.2 1 0
+github.com/org/repo/internal/paymentgateway/gateway.go:1.1,2.2 1 1
+github.com/org/repo/cmd/main.go:1.1,2.2 1 0
+`
+
+func fileNamesOf(items []gocovparser.Coverage) []string {
+	names := []string{}
+	for _, cov := range items {
+		names = append(names, cov.FileName)
+	}
+
+	return names
+}
+
+func TestSelectPackages(t *testing.T) {
+	items, err := gocovparser.Parse(packagesFixture)
+	require.NoError(t, err)
+
+	for name, prefixes := range map[string][]string{
+		"import path":   {"github.com/org/repo/internal/payment"},
+		"wildcard":      {"github.com/org/repo/internal/payment/..."},
+		"relative path": {"./internal/payment/..."},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// ACT
+			got := gocovparser.SelectPackages(items, prefixes...)
+
+			// ASSERT
+			assert.Equal(t, []string{
+				"github.com/org/repo/internal/payment/charge.go",
+				"github.com/org/repo/internal/payment/refund/refund.go",
+			}, fileNamesOf(got))
+		})
+	}
+}
+
+func TestExcludePackages(t *testing.T) {
+	items, err := gocovparser.Parse(packagesFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.ExcludePackages(items, "internal/payment/...", "cmd")
+
+	// ASSERT
+	assert.Equal(t, []string{"github.com/org/repo/internal/paymentgateway/gateway.go"}, fileNamesOf(got))
+}
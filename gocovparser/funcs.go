@@ -0,0 +1,175 @@
+package gocovparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// funcExtent is the position of a function declaration in a source file.
+type funcExtent struct {
+	name      string
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+
+	// exported is set for exported functions, and methods of exported types, outside of main packages.
+	exported bool
+
+	// complexity is the cyclomatic complexity of the function, including its function literals.
+	complexity int
+}
+
+// GroupByFunction groups coverage per fully-qualified function name (e.g. `github.com/owner/repo/pkg.(*Type).Method`).
+// Source files are read from their SourcePath under sourceRoot.
+func GroupByFunction(items []Coverage, sourceRoot string, opts ...PercentOption) (map[string]GroupDetail, error) {
+	options := newPercentOptions(opts)
+	result := make(map[string]GroupDetail)
+
+	for _, cov := range items {
+		filename := SourcePath(cov, sourceRoot)
+
+		funcs, err := findFuncs(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		pkg := path.Dir(cov.FileName)
+
+		for _, fn := range funcs {
+			key := fmt.Sprintf("%s.%s", pkg, fn.name)
+			detail := result[key]
+
+			for _, b := range cov.Blocks {
+				if !fn.contains(b) {
+					continue
+				}
+
+				detail.Total += b.NumStmt
+
+				if b.Count > 0 { // is covered
+					detail.Covered += b.NumStmt
+				}
+			}
+
+			detail.Percent = options.percentOf(detail.Covered, detail.Total)
+			result[key] = detail
+		}
+	}
+
+	return result, nil
+}
+
+func (f funcExtent) contains(b cover.ProfileBlock) bool {
+	if b.StartLine > f.endLine || (b.StartLine == f.endLine && b.StartCol >= f.endCol) {
+		return false
+	}
+
+	if b.EndLine < f.startLine || (b.EndLine == f.startLine && b.EndCol <= f.startCol) {
+		return false
+	}
+
+	return true
+}
+
+// findFuncs parses the go source file and returns the extent of each function declaration.
+func findFuncs(filename string) ([]funcExtent, error) {
+	fset, file, err := parseSource(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := []funcExtent{}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		start := fset.Position(fn.Pos())
+		end := fset.Position(fn.End())
+
+		funcs = append(funcs, funcExtent{
+			name:       funcName(fn),
+			startLine:  start.Line,
+			startCol:   start.Column,
+			endLine:    end.Line,
+			endCol:     end.Column,
+			exported:   file.Name.Name != "main" && isExportedFunc(fn),
+			complexity: cyclomaticComplexity(fn),
+		})
+	}
+
+	return funcs, nil
+}
+
+// funcName returns the function name, including the receiver type for methods (e.g. `(*Type).Method`).
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	return fmt.Sprintf("%s.%s", receiverName(fn.Recv.List[0].Type), fn.Name.Name)
+}
+
+func receiverName(expr ast.Expr) string {
+	switch typ := expr.(type) {
+	case *ast.StarExpr:
+		return fmt.Sprintf("(*%s)", receiverName(typ.X))
+	case *ast.IndexExpr: // generic receiver with one type parameter
+		return receiverName(typ.X)
+	case *ast.IndexListExpr: // generic receiver with many type parameters
+		return receiverName(typ.X)
+	case *ast.Ident:
+		return typ.Name
+	default:
+		return "?"
+	}
+}
+
+// isExportedFunc returns whether the function is exported, and for methods, whether its receiver type is exported.
+func isExportedFunc(fn *ast.FuncDecl) bool {
+	if !fn.Name.IsExported() {
+		return false
+	}
+
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return true
+	}
+
+	typ := fn.Recv.List[0].Type
+
+	for {
+		switch expr := typ.(type) {
+		case *ast.StarExpr:
+			typ = expr.X
+		case *ast.IndexExpr:
+			typ = expr.X
+		case *ast.IndexListExpr:
+			typ = expr.X
+		case *ast.Ident:
+			return expr.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// parseSource parses the go source file at filename.
+func parseSource(filename string) (*token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse source file %q", filename)
+	}
+
+	return fset, file, nil
+}
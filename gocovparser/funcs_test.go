@@ -0,0 +1,78 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const funcsSource = `package pkg
+
+type Thing struct{}
+
+func Exported(a int) int {
+	if a > 0 {
+		return a
+	}
+
+	return 0
+}
+
+func (t *Thing) Method() {
+	println("method")
+}
+
+func (t List[T]) Generic() {
+	println("generic")
+}
+`
+
+func TestCanGroupCoverageByFunction(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "thing.go"), []byte(funcsSource), 0o600))
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/pkg/thing.go:5.26,6.11 1 1
+github.com/heynemann/go-cov-parser/pkg/thing.go:6.11,8.3 1 0
+github.com/heynemann/go-cov-parser/pkg/thing.go:10.2,10.10 1 1
+github.com/heynemann/go-cov-parser/pkg/thing.go:13.26,15.2 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupByFunction(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	exported := got["github.com/heynemann/go-cov-parser/pkg.Exported"]
+	assert.Equal(t, 3, exported.Total)
+	assert.Equal(t, 2, exported.Covered)
+
+	method := got["github.com/heynemann/go-cov-parser/pkg.(*Thing).Method"]
+	assert.Equal(t, 1, method.Total)
+	assert.EqualValues(t, 1, method.Percent)
+
+	generic := got["github.com/heynemann/go-cov-parser/pkg.List.Generic"]
+	assert.Equal(t, gocovparser.GroupDetail{}, generic)
+}
+
+func TestGroupByFunctionFailsIfSourceIsMissing(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	_, err = gocovparser.GroupByFunction(items, t.TempDir())
+
+	// ASSERT
+	require.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package gate
+
+import "errors"
+
+// ErrBaselineNotFound happens when the base branch has no recorded coverage yet.
+var ErrBaselineNotFound = errors.New("baseline not found - no coverage recorded for the base branch")
+
+// ErrMissingBaseline happens when the gate is checked without a Baseline in its options.
+var ErrMissingBaseline = errors.New("missing baseline - the gate options have no baseline to compare to")
+
+// ErrForgeRequestFailed happens when GitHub or GitLab reject a request for the baseline.
+var ErrForgeRequestFailed = errors.New("forge request failed - unable to fetch the baseline")
@@ -0,0 +1,89 @@
+package gate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/pkg/errors"
+)
+
+// maximum number of bytes of the response body included in request errors.
+const maxErrorBody = 512
+
+// linkNextRegex matches the next page of a Link header, e.g. `<https://api.github.com/...&page=2>; rel="next"`.
+var linkNextRegex = regexp.MustCompile(`<([^>]*)>\s*;\s*rel="next"`)
+
+// fetch sends a GET request with the headers and returns the response body, ErrBaselineNotFound for 404 responses,
+// or ErrForgeRequestFailed.
+func fetch(ctx context.Context, client *http.Client, target string, headers map[string]string) ([]byte, error) {
+	body, _, err := fetchPage(ctx, client, target, headers)
+
+	return body, err
+}
+
+// fetchPage sends a GET request as fetch does, also returning the URL of the next page from the Link header of the
+// response, empty on the last page.
+func fetchPage(
+	ctx context.Context, client *http.Client, target string, headers map[string]string,
+) ([]byte, string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create baseline request")
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", errors.Wrap(ErrForgeRequestFailed, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", errors.Wrap(ErrForgeRequestFailed, err.Error())
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, "", errors.Wrapf(ErrBaselineNotFound, "%s returned %s", req.URL.Path, resp.Status)
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return nil, "", errors.Wrapf(ErrForgeRequestFailed, "%s: %s", resp.Status, truncate(body))
+	}
+
+	next := ""
+	if match := linkNextRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		next = match[1]
+	}
+
+	return body, next, nil
+}
+
+// decodeSnapshot unmarshals a JSON snapshot, e.g. written by `gocovparser gate --snapshot`.
+func decodeSnapshot(data []byte) (history.Snapshot, error) {
+	snapshot := history.Snapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return history.Snapshot{}, errors.Wrap(err, "invalid baseline snapshot")
+	}
+
+	return snapshot, nil
+}
+
+func truncate(body []byte) []byte {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+
+	return bytes.TrimSpace(body)
+}
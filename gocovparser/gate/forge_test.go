@@ -0,0 +1,149 @@
+package gate_test
+
+//revive:disable:add-constant
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/gate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotArchive(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(baselineOf("", "main", 0.8, 0.5))
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+
+	writer := zip.NewWriter(&archive)
+	file, err := writer.Create(name)
+	require.NoError(t, err)
+	_, err = file.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return archive.Bytes()
+}
+
+func TestGitHubArtifactBaseline(t *testing.T) {
+	archive := snapshotArchive(t, "snapshot.json")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/repos/org/repo/actions/artifacts":
+			assert.Equal(t, "coverage", r.URL.Query().Get("name"))
+			fmt.Fprintf(w, `{"artifacts": [
+				{"expired": false, "archive_download_url": "%[1]s/dev.zip", "workflow_run": {"head_branch": "dev"}},
+				{"expired": true, "archive_download_url": "%[1]s/old.zip", "workflow_run": {"head_branch": "main"}},
+				{"archive_download_url": "%[1]s/main.zip", "workflow_run": {"head_branch": "main", "head_sha": "abc"}}
+			]}`, server.URL)
+		case "/main.zip":
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseline := gate.NewGitHubArtifactBaseline(gate.GitHubOptions{
+		Repository: "org/repo", Name: "coverage", Token: "token", Endpoint: server.URL,
+	})
+
+	// ACT
+	snapshot, err := baseline.Fetch(context.Background(), "main")
+	_, missingErr := baseline.Fetch(context.Background(), "release")
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "abc", snapshot.Commit)
+	assert.InDelta(t, 0.8, snapshot.Breakdown.Coverage, 1e-9)
+	assert.ErrorIs(t, missingErr, gate.ErrBaselineNotFound)
+}
+
+func TestGitHubArtifactBaselineFollowsPagesAndSkipsForks(t *testing.T) {
+	archive := snapshotArchive(t, "snapshot.json")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/org/repo/actions/artifacts" && r.URL.Query().Get("page") == "":
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			next := server.URL + "/repos/org/repo/actions/artifacts?page=2"
+			w.Header().Set("Link", fmt.Sprintf(`<%[1]s>; rel="next", <%[1]s>; rel="last"`, next))
+			fmt.Fprintf(w, `{"artifacts": [
+				{"archive_download_url": "%s/fork.zip",
+				 "workflow_run": {"repository_id": 1, "head_repository_id": 2, "head_branch": "main"}}
+			]}`, server.URL)
+		case r.URL.Path == "/repos/org/repo/actions/artifacts" && r.URL.Query().Get("page") == "2":
+			fmt.Fprintf(w, `{"artifacts": [
+				{"archive_download_url": "%s/main.zip",
+				 "workflow_run": {"repository_id": 1, "head_repository_id": 1, "head_branch": "main", "head_sha": "def"}}
+			]}`, server.URL)
+		case r.URL.Path == "/main.zip":
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseline := gate.NewGitHubArtifactBaseline(gate.GitHubOptions{
+		Repository: "org/repo", Name: "coverage", Endpoint: server.URL,
+	})
+
+	// ACT
+	snapshot, err := baseline.Fetch(context.Background(), "main")
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "def", snapshot.Commit)
+}
+
+func TestGitLabArtifactBaseline(t *testing.T) {
+	data, err := json.Marshal(baselineOf("abc", "main", 0.8, 0.5))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("JOB-TOKEN") != "token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.EscapedPath() == "/api/v4/projects/group%2Fproject/jobs/artifacts/main/raw/coverage/snapshot.json" &&
+			r.URL.Query().Get("job") == "test":
+			_, _ = w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	opts := gate.GitLabOptions{
+		Project: "group/project", Job: "test", Path: "coverage/snapshot.json",
+		Token: "token", JobToken: true, Endpoint: server.URL,
+	}
+
+	// ACT
+	snapshot, err := gate.NewGitLabArtifactBaseline(opts).Fetch(context.Background(), "main")
+	_, missingErr := gate.NewGitLabArtifactBaseline(opts).Fetch(context.Background(), "release")
+
+	opts.JobToken = false
+	_, unauthorizedErr := gate.NewGitLabArtifactBaseline(opts).Fetch(context.Background(), "main")
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "abc", snapshot.Commit)
+	assert.ErrorIs(t, missingErr, gate.ErrBaselineNotFound)
+	assert.ErrorIs(t, unauthorizedErr, gate.ErrForgeRequestFailed)
+}
@@ -0,0 +1,195 @@
+// Package gate compares the coverage of a CI run to the coverage of its base branch, fetched from the history
+// store or from the artifacts of GitHub Actions or GitLab CI, and decides whether the run passes.
+package gate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/pkg/errors"
+)
+
+// dropTolerance keeps floating point errors from failing runs whose coverage didn't change.
+const dropTolerance = 1e-9
+
+// Baseline fetches the coverage snapshot of the base branch.
+type Baseline interface {
+	// Fetch returns the latest snapshot of the branch, or ErrBaselineNotFound.
+	Fetch(ctx context.Context, branch string) (history.Snapshot, error)
+}
+
+// Options configures the gate.
+type Options struct {
+	// Baseline the coverage is compared to. Required.
+	Baseline Baseline
+
+	// Branch is the base branch, e.g. main.
+	Branch string
+
+	// MaxDrop is the largest drop of the total coverage ratio allowed, e.g. 0.005 for half a percent.
+	MaxDrop float64
+
+	// Groups compared key by key, e.g. the package group. The baseline must have been grouped by them too.
+	Groups []gocovparser.ParseGroup
+
+	// MaxGroupDrop is the largest drop of the coverage ratio allowed for each key of Groups present in both runs.
+	MaxGroupDrop float64
+
+	// RequireBaseline fails runs without a baseline, instead of passing them (e.g. the first run of a branch).
+	RequireBaseline bool
+}
+
+// Result is the decision of the gate.
+type Result struct {
+	Passed bool
+
+	// Baseline is the snapshot of the base branch, nil if none was found.
+	Baseline *history.Snapshot
+
+	Head gocovparser.OverallCoverageBreakdown
+
+	// Delta is the change in total coverage ratio from the baseline, 0 without baseline.
+	Delta float64
+
+	// Failures explains each reason the run failed, sorted.
+	Failures []string
+
+	// Explanation is a Markdown summary of the decision, e.g. for a PR comment or a job summary.
+	Explanation string
+}
+
+// Check compares the coverage to the baseline of the base branch and returns whether it passes the gate.
+func Check(ctx context.Context, items []gocovparser.Coverage, opts Options) (Result, error) {
+	if opts.Baseline == nil {
+		return Result{}, ErrMissingBaseline
+	}
+
+	head, err := gocovparser.GroupCoverage(items, opts.Groups...)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to group coverage")
+	}
+
+	result := Result{Head: gocovparser.GetTotalCoverageBreakdown(items), Failures: []string{}}
+
+	baseline, err := opts.Baseline.Fetch(ctx, opts.Branch)
+
+	switch {
+	case errors.Is(err, ErrBaselineNotFound):
+		if opts.RequireBaseline {
+			result.Failures = append(result.Failures, fmt.Sprintf("no baseline found for branch %q", opts.Branch))
+		}
+	case err != nil:
+		return Result{}, err
+	default:
+		result.Baseline = &baseline
+		result.Delta = result.Head.Coverage - baseline.Breakdown.Coverage
+		result.Failures = compare(head, result, baseline, opts)
+	}
+
+	result.Passed = len(result.Failures) == 0
+	result.Explanation = explain(head, result, opts)
+
+	return result, nil
+}
+
+// compare returns the failures of the run against the baseline.
+func compare(head gocovparser.ParseGroupResult, result Result, baseline history.Snapshot, opts Options) []string {
+	failures := []string{}
+
+	if result.Delta < -opts.MaxDrop-dropTolerance {
+		failures = append(failures, fmt.Sprintf(
+			"total coverage dropped by %.2f%% (from %.2f%% to %.2f%%), more than the %.2f%% allowed",
+			-result.Delta*100, baseline.Breakdown.Coverage*100, result.Head.Coverage*100, opts.MaxDrop*100,
+		))
+	}
+
+	for _, group := range opts.Groups {
+		for key, coverage := range head[group.Name] {
+			base, found := baseline.Groups[group.Name][key]
+			if !found || coverage-base >= -opts.MaxGroupDrop-dropTolerance {
+				continue
+			}
+
+			failures = append(failures, fmt.Sprintf(
+				"%s %s coverage dropped by %.2f%% (from %.2f%% to %.2f%%), more than the %.2f%% allowed",
+				group.Name, key, (base-coverage)*100, base*100, coverage*100, opts.MaxGroupDrop*100,
+			))
+		}
+	}
+
+	sort.Strings(failures)
+
+	return failures
+}
+
+// explain renders the decision in Markdown, with the coverage table of the first group.
+func explain(head gocovparser.ParseGroupResult, result Result, opts Options) string {
+	var builder strings.Builder
+
+	if result.Passed {
+		builder.WriteString("### ✅ Coverage gate passed\n\n")
+	} else {
+		builder.WriteString("### ❌ Coverage gate failed\n\n")
+	}
+
+	if result.Baseline == nil {
+		fmt.Fprintf(
+			&builder, "Total coverage is %.2f%%. No baseline was found for `%s`.\n", result.Head.Coverage*100, opts.Branch,
+		)
+	} else {
+		fmt.Fprintf(
+			&builder, "Total coverage is %.2f%% (%+.2f%% from `%s` at `%s`).\n",
+			result.Head.Coverage*100, result.Delta*100, opts.Branch, result.Baseline.Commit,
+		)
+	}
+
+	if len(result.Failures) > 0 {
+		builder.WriteString("\n")
+
+		for _, failure := range result.Failures {
+			fmt.Fprintf(&builder, "- %s\n", failure)
+		}
+	}
+
+	if len(opts.Groups) > 0 {
+		markdownOpts := []report.MarkdownOption{report.WithTitle("Coverage"), report.WithGroup(opts.Groups[0].Name)}
+		if result.Baseline != nil {
+			markdownOpts = append(markdownOpts, report.WithBaseline(result.Baseline.Groups, result.Baseline.Breakdown))
+		}
+
+		builder.WriteString("\n" + report.RenderMarkdown(head, result.Head, markdownOpts...))
+	}
+
+	return builder.String()
+}
+
+type historyBaseline struct {
+	store history.Store
+}
+
+var _ Baseline = (*historyBaseline)(nil)
+
+// HistoryBaseline returns the baseline recorded in the history store, i.e. its latest snapshot of the branch (see
+// history.LatestOfBranch).
+func HistoryBaseline(store history.Store) Baseline {
+	return &historyBaseline{store: store}
+}
+
+func (b *historyBaseline) Fetch(_ context.Context, branch string) (history.Snapshot, error) {
+	snapshots, err := b.store.Snapshots()
+	if err != nil {
+		return history.Snapshot{}, err
+	}
+
+	snapshot, found := history.LatestOfBranch(snapshots, branch)
+	if !found {
+		return history.Snapshot{}, errors.Wrapf(ErrBaselineNotFound, "branch %q", branch)
+	}
+
+	return snapshot, nil
+}
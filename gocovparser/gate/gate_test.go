@@ -0,0 +1,128 @@
+package gate_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/gate"
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headFixture has 3 of its 4 statements covered: pkg/a 100%, pkg/b 50%.
+const headFixture = `mode: set
+github.com/heynemann/go-cov-parser/pkg/a/a.go:1.1,2.2 2 1
+github.com/heynemann/go-cov-parser/pkg/b/b.go:1.1,2.2 1 1
+github.com/heynemann/go-cov-parser/pkg/b/b.go:3.1,4.2 1 0
+`
+
+func headItems(t *testing.T) []gocovparser.Coverage {
+	t.Helper()
+
+	items, err := gocovparser.Parse(headFixture)
+	require.NoError(t, err)
+
+	return items
+}
+
+func baselineStore(t *testing.T, snapshots ...history.Snapshot) history.Store {
+	t.Helper()
+
+	store := history.NewJSONLinesStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	for _, snapshot := range snapshots {
+		require.NoError(t, store.Append(snapshot))
+	}
+
+	return store
+}
+
+func baselineOf(commit, branch string, total, b float64) history.Snapshot {
+	return history.Snapshot{
+		Commit:    commit,
+		Branch:    branch,
+		Breakdown: gocovparser.OverallCoverageBreakdown{Coverage: total},
+		Groups: gocovparser.ParseGroupResult{"package": {
+			"github.com/heynemann/go-cov-parser/pkg/a": 1,
+			"github.com/heynemann/go-cov-parser/pkg/b": b,
+		}},
+	}
+}
+
+func TestCheckPassesWithinAllowedDrop(t *testing.T) {
+	store := baselineStore(t, baselineOf("old", "main", 0.76, 0.5))
+
+	// ACT
+	result, err := gate.Check(context.Background(), headItems(t), gate.Options{
+		Baseline: gate.HistoryBaseline(store),
+		Branch:   "main",
+		MaxDrop:  0.01,
+		Groups:   []gocovparser.ParseGroup{gocovparser.PackageParseGroup},
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.True(t, result.Passed)
+	assert.Empty(t, result.Failures)
+	require.NotNil(t, result.Baseline)
+	assert.Equal(t, "old", result.Baseline.Commit)
+	assert.InDelta(t, -0.01, result.Delta, 1e-9)
+	assert.Contains(t, result.Explanation, "Coverage gate passed")
+	assert.Contains(t, result.Explanation, "`main` at `old`")
+}
+
+func TestCheckFailsWhenCoverageDrops(t *testing.T) {
+	store := baselineStore(t, baselineOf("old", "main", 0.9, 0.8), baselineOf("dev", "dev", 0.5, 0.5))
+
+	// ACT
+	result, err := gate.Check(context.Background(), headItems(t), gate.Options{
+		Baseline:     gate.HistoryBaseline(store),
+		Branch:       "main",
+		MaxDrop:      0.1,
+		Groups:       []gocovparser.ParseGroup{gocovparser.PackageParseGroup},
+		MaxGroupDrop: 0.2,
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		"package github.com/heynemann/go-cov-parser/pkg/b coverage dropped by 30.00% (from 80.00% to 50.00%), " +
+			"more than the 20.00% allowed",
+		"total coverage dropped by 15.00% (from 90.00% to 75.00%), more than the 10.00% allowed",
+	}, result.Failures)
+	assert.Contains(t, result.Explanation, "Coverage gate failed")
+	assert.Contains(t, result.Explanation, "- total coverage dropped by 15.00%")
+}
+
+func TestCheckWithoutBaseline(t *testing.T) {
+	store := baselineStore(t, baselineOf("dev", "dev", 0.9, 0.8))
+	opts := gate.Options{Baseline: gate.HistoryBaseline(store), Branch: "main"}
+
+	// ACT
+	passed, passedErr := gate.Check(context.Background(), headItems(t), opts)
+
+	opts.RequireBaseline = true
+	failed, failedErr := gate.Check(context.Background(), headItems(t), opts)
+
+	// ASSERT
+	require.NoError(t, passedErr)
+	require.NoError(t, failedErr)
+	assert.True(t, passed.Passed)
+	assert.Nil(t, passed.Baseline)
+	assert.Contains(t, passed.Explanation, "No baseline was found for `main`")
+	assert.False(t, failed.Passed)
+	assert.Equal(t, []string{`no baseline found for branch "main"`}, failed.Failures)
+}
+
+func TestCheckFailsWithoutBaselineSource(t *testing.T) {
+	// ACT
+	_, err := gate.Check(context.Background(), headItems(t), gate.Options{Branch: "main"})
+
+	// ASSERT
+	assert.ErrorIs(t, err, gate.ErrMissingBaseline)
+}
@@ -0,0 +1,168 @@
+package gate
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/pkg/errors"
+)
+
+// DefaultGitHubEndpoint is the GitHub REST API.
+const DefaultGitHubEndpoint = "https://api.github.com"
+
+// githubPageSize is the number of artifacts listed per page, the maximum allowed by the API.
+const githubPageSize = 100
+
+// GitHubOptions configures the baseline fetched from GitHub Actions artifacts.
+type GitHubOptions struct {
+	// Repository is the owner/repo the artifacts belong to, e.g. from GITHUB_REPOSITORY.
+	Repository string
+
+	// Name of the artifact holding the snapshot, as uploaded by actions/upload-artifact.
+	Name string
+
+	// File is the name of the snapshot in the artifact. Defaults to the only file of the artifact.
+	File string
+
+	// Token authenticates the requests, e.g. GITHUB_TOKEN with the actions:read permission.
+	Token string
+
+	// Endpoint of the API, for GitHub Enterprise. Defaults to DefaultGitHubEndpoint.
+	Endpoint string
+
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type githubBaseline struct {
+	opts GitHubOptions
+}
+
+var _ Baseline = (*githubBaseline)(nil)
+
+// NewGitHubArtifactBaseline returns the baseline uploaded as an artifact by the latest workflow run of the branch.
+// Runs of pull requests from forks are skipped, even if their head branch has the same name.
+func NewGitHubArtifactBaseline(opts GitHubOptions) Baseline {
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultGitHubEndpoint
+	}
+
+	return &githubBaseline{opts: opts}
+}
+
+type githubArtifacts struct {
+	Artifacts []githubArtifact `json:"artifacts"`
+}
+
+type githubArtifact struct {
+	Expired     bool   `json:"expired"`
+	DownloadURL string `json:"archive_download_url"`
+	WorkflowRun struct {
+		RepositoryID     int64  `json:"repository_id"`
+		HeadRepositoryID int64  `json:"head_repository_id"`
+		HeadBranch       string `json:"head_branch"`
+		HeadSHA          string `json:"head_sha"`
+	} `json:"workflow_run"`
+}
+
+func (b *githubBaseline) headers() map[string]string {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if b.opts.Token != "" {
+		headers["Authorization"] = "Bearer " + b.opts.Token
+	}
+
+	return headers
+}
+
+func (b *githubBaseline) Fetch(ctx context.Context, branch string) (history.Snapshot, error) {
+	artifact, err := b.latestArtifact(ctx, branch)
+	if err != nil {
+		return history.Snapshot{}, err
+	}
+
+	archive, err := fetch(ctx, b.opts.Client, artifact.DownloadURL, b.headers())
+	if err != nil {
+		return history.Snapshot{}, err
+	}
+
+	snapshot, err := b.unzip(archive)
+	if err != nil {
+		return history.Snapshot{}, err
+	}
+
+	if snapshot.Commit == "" {
+		snapshot.Commit = artifact.WorkflowRun.HeadSHA
+	}
+
+	return snapshot, nil
+}
+
+// latestArtifact returns the newest artifact uploaded by a run of the branch in the repository itself, following
+// the pages of the artifacts list.
+func (b *githubBaseline) latestArtifact(ctx context.Context, branch string) (githubArtifact, error) {
+	query := url.Values{"name": {b.opts.Name}, "per_page": {strconv.Itoa(githubPageSize)}}
+	target := strings.TrimSuffix(b.opts.Endpoint, "/") + "/repos/" + b.opts.Repository + "/actions/artifacts?" +
+		query.Encode()
+
+	for target != "" {
+		body, next, err := fetchPage(ctx, b.opts.Client, target, b.headers())
+		if err != nil {
+			return githubArtifact{}, err
+		}
+
+		list := githubArtifacts{}
+		if err := json.Unmarshal(body, &list); err != nil {
+			return githubArtifact{}, errors.Wrap(ErrForgeRequestFailed, "invalid artifacts list: "+err.Error())
+		}
+
+		// artifacts are listed newest first
+		for _, artifact := range list.Artifacts {
+			run := artifact.WorkflowRun
+			if !artifact.Expired && run.HeadBranch == branch && run.HeadRepositoryID == run.RepositoryID {
+				return artifact, nil
+			}
+		}
+
+		target = next
+	}
+
+	return githubArtifact{}, errors.Wrapf(ErrBaselineNotFound, "no artifact %q for branch %q", b.opts.Name, branch)
+}
+
+// unzip decodes the snapshot file of the artifact archive.
+func (b *githubBaseline) unzip(archive []byte) (history.Snapshot, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return history.Snapshot{}, errors.Wrap(ErrForgeRequestFailed, "invalid artifact archive: "+err.Error())
+	}
+
+	for _, file := range reader.File {
+		if b.opts.File != "" && file.Name != b.opts.File {
+			continue
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return history.Snapshot{}, errors.Wrapf(err, "failed to open %q in artifact", file.Name)
+		}
+
+		data, err := io.ReadAll(content)
+		content.Close()
+
+		if err != nil {
+			return history.Snapshot{}, errors.Wrapf(err, "failed to read %q in artifact", file.Name)
+		}
+
+		return decodeSnapshot(data)
+	}
+
+	return history.Snapshot{}, errors.Wrapf(ErrBaselineNotFound, "no file %q in artifact %q", b.opts.File, b.opts.Name)
+}
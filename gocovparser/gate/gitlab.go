@@ -0,0 +1,75 @@
+package gate
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+)
+
+// DefaultGitLabEndpoint is GitLab.com.
+const DefaultGitLabEndpoint = "https://gitlab.com"
+
+// GitLabOptions configures the baseline fetched from GitLab CI job artifacts.
+type GitLabOptions struct {
+	// Project is the ID or the path of the project, e.g. from CI_PROJECT_ID.
+	Project string
+
+	// Job is the name of the job whose artifacts hold the snapshot.
+	Job string
+
+	// Path of the snapshot in the artifacts of the job.
+	Path string
+
+	// Token authenticates the requests, sent as a PRIVATE-TOKEN header, or as a JOB-TOKEN header if JobToken is
+	// set (e.g. CI_JOB_TOKEN).
+	Token    string
+	JobToken bool
+
+	// Endpoint of the instance. Defaults to DefaultGitLabEndpoint.
+	Endpoint string
+
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type gitlabBaseline struct {
+	opts GitLabOptions
+}
+
+var _ Baseline = (*gitlabBaseline)(nil)
+
+// NewGitLabArtifactBaseline returns the baseline stored in the artifacts of the latest successful pipeline of the
+// branch.
+func NewGitLabArtifactBaseline(opts GitLabOptions) Baseline {
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultGitLabEndpoint
+	}
+
+	return &gitlabBaseline{opts: opts}
+}
+
+func (b *gitlabBaseline) Fetch(ctx context.Context, branch string) (history.Snapshot, error) {
+	target := strings.TrimSuffix(b.opts.Endpoint, "/") + "/api/v4/projects/" + url.PathEscape(b.opts.Project) +
+		"/jobs/artifacts/" + url.PathEscape(branch) + "/raw/" + b.opts.Path + "?" +
+		url.Values{"job": {b.opts.Job}}.Encode()
+
+	headers := map[string]string{}
+
+	switch {
+	case b.opts.Token == "":
+	case b.opts.JobToken:
+		headers["JOB-TOKEN"] = b.opts.Token
+	default:
+		headers["PRIVATE-TOKEN"] = b.opts.Token
+	}
+
+	data, err := fetch(ctx, b.opts.Client, target, headers)
+	if err != nil {
+		return history.Snapshot{}, err
+	}
+
+	return decodeSnapshot(data)
+}
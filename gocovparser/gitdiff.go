@@ -0,0 +1,161 @@
+package gocovparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	hunkOldCountPosition = 1
+	hunkStartPosition    = 2
+	hunkCountPosition    = 3
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// diffHunk is a hunk header: the first new line and the number of old and new lines of its body.
+type diffHunk struct {
+	start    int
+	oldLines int
+	newLines int
+}
+
+// CoverageForDiff computes the coverage of the lines changed in repoPath since baseRef.
+func CoverageForDiff(items []Coverage, repoPath, baseRef string) (DiffCoverageResult, error) {
+	changes, err := ChangedLines(repoPath, baseRef)
+	if err != nil {
+		return DiffCoverageResult{}, err
+	}
+
+	return DiffCoverage(items, changes), nil
+}
+
+// ChangedLines runs `git diff --unified=0 baseRef -- '*.go'` in repoPath and returns the added or modified lines per
+// Go file. References starting with a dash fail with ErrInvalidGitRef, so they can't be read as git options.
+func ChangedLines(repoPath, baseRef string) (map[string][]LineRange, error) {
+	if strings.HasPrefix(baseRef, "-") {
+		return nil, errors.Wrapf(ErrInvalidGitRef, "%q", baseRef)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(
+		"git", "-C", repoPath,
+		"diff", "--unified=0", "--no-color", "--no-ext-diff", "--no-prefix", baseRef, "--", "*.go",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run git diff against %q: %s", baseRef, strings.TrimSpace(stderr.String()))
+	}
+
+	return ParseUnifiedDiff(&stdout)
+}
+
+// ParseUnifiedDiff reads a unified diff (as generated by `git diff --no-prefix`) and returns
+// the added or modified lines per file. Removed files are ignored. File headers are only recognised between hunks,
+// so added lines that look like headers (`+++ ...`) are part of the hunk they belong to.
+func ParseUnifiedDiff(r io.Reader) (map[string][]LineRange, error) {
+	result := make(map[string][]LineRange)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+
+	file := ""
+
+	// the old and new lines left in the body of the current hunk
+	oldLines, newLines := 0, 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if oldLines > 0 || newLines > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLines--
+			case strings.HasPrefix(line, "-"):
+				oldLines--
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file" doesn't count as a line
+			default:
+				oldLines--
+				newLines--
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = diffFileName(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@ "):
+			hunk, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+
+			oldLines, newLines = hunk.oldLines, hunk.newLines
+
+			if file != "" && hunk.newLines > 0 {
+				result[file] = append(result[file], LineRange{Start: hunk.start, End: hunk.start + hunk.newLines - 1})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidDiff, err.Error())
+	}
+
+	return result, nil
+}
+
+func diffFileName(name string) string {
+	// strip the timestamp some diff tools append after a tab
+	name = strings.SplitN(name, "\t", 2)[0]
+
+	if name == "/dev/null" {
+		return ""
+	}
+
+	return name
+}
+
+// parseHunkHeader returns the first new line and the line counts of a hunk. Omitted counts are 1.
+func parseHunkHeader(line string) (diffHunk, error) {
+	match := hunkHeaderRegex.FindStringSubmatch(line)
+	if len(match) == 0 {
+		return diffHunk{}, errors.Wrapf(ErrInvalidDiff, "invalid hunk header %q", line)
+	}
+
+	start, err := strconv.Atoi(match[hunkStartPosition])
+	if err != nil {
+		return diffHunk{}, errors.Wrapf(ErrInvalidDiff, "invalid hunk header %q", line)
+	}
+
+	oldLines, err := hunkCount(match[hunkOldCountPosition])
+	if err != nil {
+		return diffHunk{}, errors.Wrapf(ErrInvalidDiff, "invalid hunk header %q", line)
+	}
+
+	newLines, err := hunkCount(match[hunkCountPosition])
+	if err != nil {
+		return diffHunk{}, errors.Wrapf(ErrInvalidDiff, "invalid hunk header %q", line)
+	}
+
+	return diffHunk{start: start, oldLines: oldLines, newLines: newLines}, nil
+}
+
+func hunkCount(count string) (int, error) {
+	if count == "" {
+		return 1, nil
+	}
+
+	return strconv.Atoi(count)
+}
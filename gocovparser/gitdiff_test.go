@@ -0,0 +1,162 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git gocovparser/core.go gocovparser/core.go
+index 1111111..2222222 100644
+--- gocovparser/core.go
++++ gocovparser/core.go
+@@ -10,0 +11,3 @@ func Parse(coverageData string) ([]Coverage, error) {
++	a := 1
++	b := 2
++	c := 3
+@@ -20 +23 @@ func Parse(coverageData string) ([]Coverage, error) {
+-	old
++	new
+@@ -30,2 +33,0 @@ func Parse(coverageData string) ([]Coverage, error) {
+-	removed
+-	removed
+diff --git gocovparser/old.go gocovparser/old.go
+deleted file mode 100644
+--- gocovparser/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package gocovparser
+-
+`
+
+	// ACT
+	got, err := gocovparser.ParseUnifiedDiff(strings.NewReader(diff))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]gocovparser.LineRange{
+		"gocovparser/core.go": {{Start: 11, End: 13}, {Start: 23, End: 23}},
+	}, got)
+}
+
+func TestParseUnifiedDiffKeepsHeaderLikeLinesInTheirHunk(t *testing.T) {
+	diff := `--- notes.md
++++ notes.md
+@@ -1,2 +1,3 @@
+--- old
++++ new
++++ more
+ context
+\ No newline at end of file
+@@ -10 +11 @@
+-a
++b
+`
+
+	// ACT
+	got, err := gocovparser.ParseUnifiedDiff(strings.NewReader(diff))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]gocovparser.LineRange{
+		"notes.md": {{Start: 1, End: 3}, {Start: 11, End: 11}},
+	}, got)
+}
+
+func TestParseUnifiedDiffReadsLongLines(t *testing.T) {
+	diff := "+++ assets/app.min.js\n@@ -1 +1 @@\n-" + strings.Repeat("a", 100000) + "\n+" + strings.Repeat("b", 100000) + "\n"
+
+	// ACT
+	got, err := gocovparser.ParseUnifiedDiff(strings.NewReader(diff))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]gocovparser.LineRange{"assets/app.min.js": {{Start: 1, End: 1}}}, got)
+}
+
+func TestParseUnifiedDiffFailsForInvalidHunk(t *testing.T) {
+	diff := "+++ gocovparser/core.go\n@@ invalid @@\n"
+
+	// ACT
+	_, err := gocovparser.ParseUnifiedDiff(strings.NewReader(diff))
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidDiff)
+}
+
+func TestCanComputeCoverageForGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@test"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	file := filepath.Join(repo, "pkg", "core.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("package pkg\n\nfunc A() {\n}\n"), 0o600))
+
+	asset := filepath.Join(repo, "app.min.js")
+	require.NoError(t, os.WriteFile(asset, []byte(strings.Repeat("a", 100000)+"\n"), 0o600))
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	require.NoError(t, os.WriteFile(file, []byte("package pkg\n\nfunc A() {\n}\n\nfunc B() {\n\tprintln()\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(asset, []byte(strings.Repeat("b", 100000)+"\n"), 0o600))
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/pkg/core.go:3.10,4.2 0 1
+github.com/heynemann/go-cov-parser/pkg/core.go:6.10,8.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.CoverageForDiff(items, repo, "HEAD")
+	changes, changesErr := gocovparser.ChangedLines(repo, "HEAD")
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Total.Total)
+	assert.Equal(t, 0, got.Total.Covered)
+	require.NoError(t, changesErr)
+	assert.Equal(t, map[string][]gocovparser.LineRange{"pkg/core.go": {{Start: 5, End: 8}}}, changes)
+}
+
+func TestChangedLinesFailsForUnknownRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	// ACT
+	_, err := gocovparser.ChangedLines(t.TempDir(), "does-not-exist")
+
+	// ASSERT
+	require.Error(t, err)
+}
+
+func TestChangedLinesRejectsOptionsAsRef(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ChangedLines(t.TempDir(), "--output=/tmp/diff")
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidGitRef)
+}
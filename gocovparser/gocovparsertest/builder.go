@@ -0,0 +1,112 @@
+// Package gocovparsertest helps testing tools built on gocovparser: it builds coverage fixtures, generates fake
+// profiles and compares exporter outputs to golden files.
+package gocovparsertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"golang.org/x/tools/cover"
+)
+
+// Builder builds coverage profiles file by file, e.g.:
+//
+//	items := gocovparsertest.NewBuilder().
+//		File("github.com/owner/repo/pkg/a.go").Covered(3, 5, 2).Uncovered(6, 8, 1).
+//		File("github.com/owner/repo/pkg/b.go").Covered(1, 2, 1).
+//		Coverage(t)
+type Builder struct {
+	mode  string
+	files []*FileBuilder
+}
+
+// FileBuilder adds the blocks of a file to a Builder. Its Builder methods continue with the whole profile.
+type FileBuilder struct {
+	*Builder
+
+	name   string
+	blocks []cover.ProfileBlock
+}
+
+// NewBuilder returns a builder of a profile in the set mode.
+func NewBuilder() *Builder {
+	return &Builder{mode: gocovparser.ModeSet}
+}
+
+// Mode sets the coverage mode of the profile, e.g. gocovparser.ModeCount.
+func (b *Builder) Mode(mode string) *Builder {
+	b.mode = mode
+
+	return b
+}
+
+// File returns the builder of the blocks of the file, e.g. `github.com/owner/repo/pkg/a.go`, adding it to the
+// profile on first use.
+func (b *Builder) File(fileName string) *FileBuilder {
+	for _, file := range b.files {
+		if file.name == fileName {
+			return file
+		}
+	}
+
+	file := &FileBuilder{Builder: b, name: fileName}
+	b.files = append(b.files, file)
+
+	return file
+}
+
+// Profile returns the profile, as written by `go test -coverprofile`.
+func (b *Builder) Profile() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "mode: %s\n", b.mode)
+
+	for _, file := range b.files {
+		for _, block := range file.blocks {
+			fmt.Fprintf(
+				&builder, "%s:%d.%d,%d.%d %d %d\n",
+				file.name, block.StartLine, block.StartCol, block.EndLine, block.EndCol, block.NumStmt, block.Count,
+			)
+		}
+	}
+
+	return builder.String()
+}
+
+// Coverage parses the profile with the options, failing the test if it can't be parsed.
+func (b *Builder) Coverage(t testing.TB, opts ...gocovparser.ParseOption) []gocovparser.Coverage {
+	t.Helper()
+
+	items, err := gocovparser.Parse(b.Profile(), opts...)
+	if err != nil {
+		t.Fatalf("failed to parse built profile: %v", err)
+	}
+
+	return items
+}
+
+// Block adds a block of statements from the start of startLine to the end of endLine, executed count times.
+func (f *FileBuilder) Block(startLine, endLine, statements, count int) *FileBuilder {
+	return f.ProfileBlock(cover.ProfileBlock{
+		StartLine: startLine, StartCol: 1, EndLine: endLine, EndCol: 2, NumStmt: statements, Count: count,
+	})
+}
+
+// Covered adds a block of statements executed once.
+func (f *FileBuilder) Covered(startLine, endLine, statements int) *FileBuilder {
+	return f.Block(startLine, endLine, statements, 1)
+}
+
+// Uncovered adds a block of statements never executed.
+func (f *FileBuilder) Uncovered(startLine, endLine, statements int) *FileBuilder {
+	return f.Block(startLine, endLine, statements, 0)
+}
+
+// ProfileBlock adds the block as is, e.g. for blocks sharing a line.
+func (f *FileBuilder) ProfileBlock(block cover.ProfileBlock) *FileBuilder {
+	f.blocks = append(f.blocks, block)
+
+	return f
+}
@@ -0,0 +1,103 @@
+package gocovparsertest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+// Defaults of GenerateOptions.
+const (
+	DefaultModule   = "example.com/generated"
+	DefaultPackages = 3
+	DefaultFiles    = 3
+	DefaultBlocks   = 10
+)
+
+// shape of the generated blocks: each spans 2 lines out of 3, with up to 3 statements executed up to 10 times.
+const (
+	linesPerBlock        = 3
+	maxStatementsInBlock = 3
+	maxCount             = 10
+)
+
+// GenerateOptions configures GenerateProfile.
+type GenerateOptions struct {
+	// Module path prefixing the file names. Defaults to DefaultModule.
+	Module string
+
+	// Mode of the profile. Defaults to gocovparser.ModeSet.
+	Mode string
+
+	// Packages, Files per package and Blocks per file of the profile. Default to DefaultPackages, DefaultFiles and
+	// DefaultBlocks.
+	Packages int
+	Files    int
+	Blocks   int
+
+	// Coverage is the probability of each block to be covered, from 0 to 1. Zero, the default, generates a profile
+	// without covered blocks.
+	Coverage float64
+
+	// Seed makes the profile reproducible: profiles generated with the same options are equal.
+	Seed int64
+}
+
+// GenerateProfile returns a fake profile with random statement counts and covered blocks, e.g. for property tests
+// or benchmarks of tools built on gocovparser.
+func GenerateProfile(opts GenerateOptions) string {
+	opts = opts.withDefaults()
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "mode: %s\n", opts.Mode)
+
+	for pkg := 0; pkg < opts.Packages; pkg++ {
+		for file := 0; file < opts.Files; file++ {
+			for block := 0; block < opts.Blocks; block++ {
+				count := 0
+				if random.Float64() < opts.Coverage {
+					count = 1
+					if opts.Mode != gocovparser.ModeSet {
+						count += random.Intn(maxCount)
+					}
+				}
+
+				start := block*linesPerBlock + 1
+				fmt.Fprintf(
+					&builder, "%s/pkg%d/file%d.go:%d.2,%d.10 %d %d\n",
+					opts.Module, pkg, file, start, start+1, random.Intn(maxStatementsInBlock)+1, count,
+				)
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+func (opts GenerateOptions) withDefaults() GenerateOptions {
+	if opts.Module == "" {
+		opts.Module = DefaultModule
+	}
+
+	if opts.Mode == "" {
+		opts.Mode = gocovparser.ModeSet
+	}
+
+	if opts.Packages < 1 {
+		opts.Packages = DefaultPackages
+	}
+
+	if opts.Files < 1 {
+		opts.Files = DefaultFiles
+	}
+
+	if opts.Blocks < 1 {
+		opts.Blocks = DefaultBlocks
+	}
+
+	return opts
+}
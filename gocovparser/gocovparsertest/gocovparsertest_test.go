@@ -0,0 +1,107 @@
+package gocovparsertest_test
+
+//revive:disable:add-constant
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/heynemann/go-cov-parser/gocovparser/gocovparsertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func builtProfile() *gocovparsertest.Builder {
+	return gocovparsertest.NewBuilder().
+		File("github.com/owner/repo/pkg/a.go").Covered(3, 5, 2).Uncovered(6, 8, 1).
+		File("github.com/owner/repo/pkg/b.go").Covered(1, 2, 1).
+		File("github.com/owner/repo/pkg/a.go").Block(10, 11, 1, 0).Builder
+}
+
+func TestBuilderBuildsProfile(t *testing.T) {
+	// ACT
+	profile := builtProfile().Mode(gocovparser.ModeCount).Profile()
+
+	// ASSERT
+	assert.Equal(t, `mode: count
+github.com/owner/repo/pkg/a.go:3.1,5.2 2 1
+github.com/owner/repo/pkg/a.go:6.1,8.2 1 0
+github.com/owner/repo/pkg/a.go:10.1,11.2 1 0
+github.com/owner/repo/pkg/b.go:1.1,2.2 1 1
+`, profile)
+}
+
+func TestBuilderBuildsCoverage(t *testing.T) {
+	// ACT
+	items := builtProfile().Coverage(t)
+
+	// ASSERT
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+	assert.Equal(t, 2, breakdown.Files)
+	assert.Equal(t, 5, breakdown.Statements)
+	assert.Equal(t, 3, breakdown.CoveredStatements)
+}
+
+func TestGenerateProfileIsReproducible(t *testing.T) {
+	opts := gocovparsertest.GenerateOptions{Packages: 2, Files: 2, Blocks: 5, Coverage: 0.5, Seed: 42}
+
+	// ACT
+	first := gocovparsertest.GenerateProfile(opts)
+	second := gocovparsertest.GenerateProfile(opts)
+	none := gocovparsertest.GenerateProfile(gocovparsertest.GenerateOptions{Seed: 42})
+
+	// ASSERT
+	assert.Equal(t, first, second)
+
+	items, err := gocovparser.Parse(first)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+	assert.Equal(t, 20, gocovparser.GetTotalCoverageBreakdown(items).Blocks)
+
+	items, err = gocovparser.Parse(none)
+	require.NoError(t, err)
+	assert.Len(t, items, gocovparsertest.DefaultPackages*gocovparsertest.DefaultFiles)
+	assert.Zero(t, gocovparser.GetTotalCoverageBreakdown(items).CoveredStatements)
+}
+
+func TestAssertExportGolden(t *testing.T) {
+	exporter, err := export.Lookup("lcov")
+	require.NoError(t, err)
+
+	// ACT
+	matched := gocovparsertest.AssertExportGolden(
+		t, exporter, builtProfile().Coverage(t), export.Options{}, filepath.Join("testdata", "lcov.golden"),
+	)
+
+	// ASSERT
+	assert.True(t, matched)
+}
+
+// recordingTB records the errors reported through it.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGoldenReportsDifferences(t *testing.T) {
+	fake := &recordingTB{}
+
+	// ACT
+	matched := gocovparsertest.AssertGolden(fake, filepath.Join("testdata", "lcov.golden"), []byte("TN:\ndifferent"))
+
+	// ASSERT
+	assert.False(t, matched)
+	require.Len(t, fake.errors, 1)
+	assert.Contains(t, fake.errors[0], "line 2:\n- SF:pkg/a.go\n+ different\n")
+	assert.Contains(t, fake.errors[0], "line 3:\n- DA:3,1\n")
+	assert.NotContains(t, fake.errors[0], "line 1:")
+}
@@ -0,0 +1,100 @@
+package gocovparsertest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+)
+
+// UpdateGoldenEnv is the environment variable that makes golden comparisons write the golden files instead, e.g.
+// `GOCOVPARSER_UPDATE_GOLDEN=1 go test ./...` after an intended change of the outputs.
+const UpdateGoldenEnv = "GOCOVPARSER_UPDATE_GOLDEN"
+
+// AssertGolden fails the test if got differs from the content of the golden file at path (e.g.
+// testdata/lcov.golden), or writes it there when UpdateGoldenEnv is set.
+func AssertGolden(t testing.TB, path string, got []byte) bool {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to write golden file %q: %v", path, err)
+		}
+
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %q (set %s=1 to create it): %v", path, UpdateGoldenEnv, err)
+	}
+
+	if bytes.Equal(want, got) {
+		return true
+	}
+
+	t.Errorf("output differs from golden file %q (- golden, + got):\n%s", path, goldenDiff(string(want), string(got)))
+
+	return false
+}
+
+// goldenDiff compares want and got line by line, listing the lines that differ.
+func goldenDiff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	var diff strings.Builder
+
+	for index := 0; index < len(wantLines) || index < len(gotLines); index++ {
+		wantLine, inWant := lineAt(wantLines, index)
+		gotLine, inGot := lineAt(gotLines, index)
+
+		if inWant && inGot && wantLine == gotLine {
+			continue
+		}
+
+		fmt.Fprintf(&diff, "line %d:\n", index+1)
+
+		if inWant {
+			fmt.Fprintf(&diff, "- %s\n", wantLine)
+		}
+
+		if inGot {
+			fmt.Fprintf(&diff, "+ %s\n", gotLine)
+		}
+	}
+
+	return diff.String()
+}
+
+func lineAt(lines []string, index int) (string, bool) {
+	if index >= len(lines) {
+		return "", false
+	}
+
+	return lines[index], true
+}
+
+// AssertExportGolden writes the items with the exporter and compares the output to the golden file at path, as
+// AssertGolden does.
+func AssertExportGolden(
+	t testing.TB, exporter export.Exporter, items []gocovparser.Coverage, opts export.Options, path string,
+) bool {
+	t.Helper()
+
+	var output bytes.Buffer
+
+	if err := exporter.Write(&output, items, opts); err != nil {
+		t.Fatalf("failed to export %s: %v", exporter.Name(), err)
+	}
+
+	return AssertGolden(t, path, output.Bytes())
+}
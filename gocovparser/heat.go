@@ -0,0 +1,123 @@
+package gocovparser
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// GetHeatMap returns count-weighted coverage metrics per file name, preserving block execution counts
+// instead of treating coverage as binary.
+func GetHeatMap(items []Coverage) map[string]FileHeat {
+	lines := GetLineCoverage(items)
+	result := make(map[string]FileHeat, len(items))
+
+	for _, cov := range items {
+		heat := result[cov.FileName]
+
+		for _, b := range cov.Blocks {
+			heat.TotalHits += b.Count
+
+			if b.Count > heat.MaxHits {
+				heat.MaxHits = b.Count
+			}
+		}
+
+		result[cov.FileName] = heat
+	}
+
+	for fileName, heat := range result {
+		heat.Lines = make(map[int]LineHeat, len(lines[fileName]))
+
+		for number, line := range lines[fileName] {
+			heat.Lines[number] = LineHeat{
+				Hits: line.Hits,
+				Heat: normalizeHeat(line.Hits, heat.MaxHits),
+			}
+		}
+
+		result[fileName] = heat
+	}
+
+	return result
+}
+
+// normalizeHeat returns a logarithmic heat (0 to 1) for count, relative to maxCount, as `go tool cover -html` does.
+func normalizeHeat(count, maxCount int) float64 {
+	switch {
+	case count <= 0:
+		return 0
+	case maxCount <= 1:
+		return 1
+	default:
+		return math.Log(float64(count)) / math.Log(float64(maxCount))
+	}
+}
+
+// GetHotUncoveredNeighbors returns uncovered blocks that sit next to executed blocks, ordered by how often
+// the neighbor was executed, so heavily exercised code with untested branches surfaces first.
+// A limit lower than 1 returns every uncovered neighbor.
+func GetHotUncoveredNeighbors(items []Coverage, limit int) []UncoveredNeighbor {
+	result := []UncoveredNeighbor{}
+
+	for _, cov := range items {
+		for _, uncovered := range cov.Blocks {
+			if uncovered.Count > 0 || uncovered.NumStmt == 0 {
+				continue
+			}
+
+			neighbor, found := hottestNeighbor(cov, uncovered)
+			if !found {
+				continue
+			}
+
+			result = append(result, UncoveredNeighbor{
+				FileName:  cov.FileName,
+				Uncovered: uncovered,
+				Neighbor:  neighbor,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Neighbor.Count != result[j].Neighbor.Count {
+			return result[i].Neighbor.Count > result[j].Neighbor.Count
+		}
+
+		if result[i].FileName != result[j].FileName {
+			return result[i].FileName < result[j].FileName
+		}
+
+		return result[i].Uncovered.StartLine < result[j].Uncovered.StartLine
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+// hottestNeighbor returns the executed block with the highest count on the lines touching or adjacent to the block.
+func hottestNeighbor(cov Coverage, block cover.ProfileBlock) (cover.ProfileBlock, bool) {
+	hottest := cover.ProfileBlock{}
+	found := false
+
+	for _, candidate := range cov.Blocks {
+		if candidate.Count == 0 {
+			continue
+		}
+
+		if candidate.EndLine < block.StartLine-1 || candidate.StartLine > block.EndLine+1 {
+			continue
+		}
+
+		if !found || candidate.Count > hottest.Count {
+			hottest = candidate
+			found = true
+		}
+	}
+
+	return hottest, found
+}
@@ -0,0 +1,58 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const heatFixture = `
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.20 2 1000
+github.com/heynemann/go-cov-parser/gocovparser/core.go:12.20,14.3 1 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:15.2,15.10 1 10
+github.com/heynemann/go-cov-parser/gocovparser/core.go:30.1,31.2 1 0
+github.com/heynemann/go-cov-parser/gocovparser/filter.go:1.1,2.10 1 5
+github.com/heynemann/go-cov-parser/gocovparser/filter.go:3.1,3.10 1 0
+`
+
+func TestCanGetHeatMap(t *testing.T) {
+	items, err := gocovparser.Parse(heatFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetHeatMap(items)
+
+	// ASSERT
+	core := got["github.com/heynemann/go-cov-parser/gocovparser/core.go"]
+	assert.Equal(t, 1010, core.TotalHits)
+	assert.Equal(t, 1000, core.MaxHits)
+	assert.Equal(t, gocovparser.LineHeat{Hits: 1000, Heat: 1}, core.Lines[10])
+	assert.Equal(t, gocovparser.LineHeat{Hits: 0, Heat: 0}, core.Lines[13])
+	assert.InDelta(t, 1.0/3.0, core.Lines[15].Heat, 1e-9)
+	assert.NotContains(t, core.Lines, 20)
+}
+
+func TestCanGetHotUncoveredNeighbors(t *testing.T) {
+	items, err := gocovparser.Parse(heatFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetHotUncoveredNeighbors(items, 0)
+
+	// ASSERT
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/gocovparser/core.go", got[0].FileName)
+	assert.Equal(t, 12, got[0].Uncovered.StartLine)
+	assert.Equal(t, 1000, got[0].Neighbor.Count)
+
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/gocovparser/filter.go", got[1].FileName)
+	assert.Equal(t, 5, got[1].Neighbor.Count)
+
+	assert.Len(t, gocovparser.GetHotUncoveredNeighbors(items, 1), 1)
+}
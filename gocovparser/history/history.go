@@ -0,0 +1,43 @@
+// Package history records coverage snapshots of CI runs and queries coverage trends from them.
+package history
+
+import (
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// Snapshot is the coverage of a commit at a point in time.
+type Snapshot struct {
+	Commit    string                               `json:"commit"`
+	Branch    string                               `json:"branch,omitempty"`
+	Timestamp time.Time                            `json:"timestamp"`
+	Breakdown gocovparser.OverallCoverageBreakdown `json:"breakdown"`
+	Groups    gocovparser.ParseGroupResult         `json:"groups,omitempty"`
+}
+
+// Store persists snapshots.
+type Store interface {
+	// Append records a snapshot.
+	Append(snapshot Snapshot) error
+
+	// Snapshots returns every recorded snapshot, in the order they were appended.
+	Snapshots() ([]Snapshot, error)
+}
+
+// NewSnapshot computes the snapshot of commit from the coverage, grouped in the given groups. Set the Branch of the
+// snapshot to find it as the baseline of the branch (see LatestOfBranch).
+func NewSnapshot(commit string, items []gocovparser.Coverage, groups ...gocovparser.ParseGroup) (Snapshot, error) {
+	grouped, err := gocovparser.GroupCoverage(items, groups...)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(err, "failed to group coverage")
+	}
+
+	return Snapshot{
+		Commit:    commit,
+		Timestamp: time.Now().UTC(),
+		Breakdown: gocovparser.GetTotalCoverageBreakdown(items),
+		Groups:    grouped,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package history_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const historyFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:38.53,42.2 3 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 1 0
+`
+
+func snapshotAt(commit string, minutes int, coverage float64) history.Snapshot {
+	return history.Snapshot{
+		Commit:    commit,
+		Timestamp: time.Date(2023, 1, 1, 0, minutes, 0, 0, time.UTC),
+		Breakdown: gocovparser.OverallCoverageBreakdown{Coverage: coverage},
+		Groups:    gocovparser.ParseGroupResult{"package": {"pkg": coverage / 2}},
+	}
+}
+
+func TestCanCreateSnapshot(t *testing.T) {
+	items, err := gocovparser.Parse(historyFixture)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := history.NewSnapshot("abc123", items, gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", got.Commit)
+	assert.False(t, got.Timestamp.IsZero())
+	assert.EqualValues(t, 0.75, got.Breakdown.Coverage)
+	assert.EqualValues(t, 0.75, got.Groups["total"]["total"])
+}
+
+func TestCanAppendAndReadJSONLinesStore(t *testing.T) {
+	store := history.NewJSONLinesStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	first := snapshotAt("a", 1, 0.5)
+	second := snapshotAt("b", 2, 0.75)
+
+	empty, err := store.Snapshots()
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// ACT
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+	got, err := store.Snapshots()
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []history.Snapshot{first, second}, got)
+}
+
+func TestJSONLinesStoreFailsForInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"commit\":\"a\"}\nnot json\n"), 0o600))
+
+	// ACT
+	_, err := history.NewJSONLinesStore(path).Snapshots()
+
+	// ASSERT
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,88 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// maximum size in bytes of a serialized snapshot, as per-file groups of large repositories get big.
+const maxSnapshotSize = 16 << 20
+
+// jsonLinesStore stores snapshots in a file with one JSON document per line.
+type jsonLinesStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ Store = (*jsonLinesStore)(nil)
+
+// NewJSONLinesStore returns a store appending snapshots as JSON lines to the file at path.
+// The file is created on the first append.
+func NewJSONLinesStore(path string) Store {
+	return &jsonLinesStore{path: path}
+}
+
+func (s *jsonLinesStore) Append(snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize snapshot")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open history file %q", s.path)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+
+		return errors.Wrapf(err, "failed to append to history file %q", s.path)
+	}
+
+	return errors.Wrapf(file.Close(), "failed to close history file %q", s.path)
+}
+
+func (s *jsonLinesStore) Snapshots() ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Snapshot{}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open history file %q", s.path)
+	}
+	defer file.Close()
+
+	snapshots := []Snapshot{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxSnapshotSize)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		snapshot := Snapshot{}
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "invalid snapshot at line %d of %q", lineNumber, s.path)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read history file %q", s.path)
+	}
+
+	return snapshots, nil
+}
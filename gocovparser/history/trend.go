@@ -0,0 +1,99 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// TrendPoint is the coverage of a commit at a point in time.
+type TrendPoint struct {
+	Commit    string
+	Timestamp time.Time
+	Coverage  float64
+}
+
+// Trend returns the total coverage of the snapshots, sorted by timestamp.
+func Trend(snapshots []Snapshot) []TrendPoint {
+	points := make([]TrendPoint, 0, len(snapshots))
+
+	for _, snapshot := range sortedByTime(snapshots) {
+		points = append(points, TrendPoint{
+			Commit:    snapshot.Commit,
+			Timestamp: snapshot.Timestamp,
+			Coverage:  snapshot.Breakdown.Coverage,
+		})
+	}
+
+	return points
+}
+
+// GroupTrend returns the coverage of key in group (e.g. a package), sorted by timestamp.
+// Snapshots without the key are left out.
+func GroupTrend(snapshots []Snapshot, group, key string) []TrendPoint {
+	points := []TrendPoint{}
+
+	for _, snapshot := range sortedByTime(snapshots) {
+		coverage, found := snapshot.Groups[group][key]
+		if !found {
+			continue
+		}
+
+		points = append(points, TrendPoint{
+			Commit:    snapshot.Commit,
+			Timestamp: snapshot.Timestamp,
+			Coverage:  coverage,
+		})
+	}
+
+	return points
+}
+
+// LastCommits returns the latest snapshot of each of the last n commits, sorted by timestamp.
+// A negative n returns every commit.
+func LastCommits(snapshots []Snapshot, n int) []Snapshot {
+	latest := make(map[string]int)
+	sorted := sortedByTime(snapshots)
+
+	for index, snapshot := range sorted {
+		latest[snapshot.Commit] = index
+	}
+
+	result := make([]Snapshot, 0, len(latest))
+
+	for index, snapshot := range sorted {
+		if latest[snapshot.Commit] == index {
+			result = append(result, snapshot)
+		}
+	}
+
+	if n >= 0 && len(result) > n {
+		result = result[len(result)-n:]
+	}
+
+	return result
+}
+
+// LatestOfBranch returns the latest snapshot of the branch. Snapshots recorded without a branch belong to every
+// branch.
+func LatestOfBranch(snapshots []Snapshot, branch string) (Snapshot, bool) {
+	sorted := sortedByTime(snapshots)
+
+	for index := len(sorted) - 1; index >= 0; index-- {
+		if sorted[index].Branch == "" || sorted[index].Branch == branch {
+			return sorted[index], true
+		}
+	}
+
+	return Snapshot{}, false
+}
+
+func sortedByTime(snapshots []Snapshot) []Snapshot {
+	sorted := make([]Snapshot, len(snapshots))
+	copy(sorted, snapshots)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	return sorted
+}
@@ -0,0 +1,72 @@
+package history_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanComputeTrend(t *testing.T) {
+	snapshots := []history.Snapshot{snapshotAt("b", 2, 0.75), snapshotAt("a", 1, 0.5)}
+
+	// ACT
+	got := history.Trend(snapshots)
+
+	// ASSERT
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Commit)
+	assert.EqualValues(t, 0.5, got[0].Coverage)
+	assert.Equal(t, "b", got[1].Commit)
+	assert.Equal(t, snapshots[0].Timestamp, got[1].Timestamp)
+}
+
+func TestCanComputeGroupTrend(t *testing.T) {
+	snapshots := []history.Snapshot{snapshotAt("a", 1, 0.5), snapshotAt("b", 2, 0.75)}
+	snapshots[1].Groups = nil
+
+	// ACT
+	got := history.GroupTrend(snapshots, "package", "pkg")
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.EqualValues(t, 0.25, got[0].Coverage)
+}
+
+func TestCanGetLastCommits(t *testing.T) {
+	snapshots := []history.Snapshot{
+		snapshotAt("a", 1, 0.5),
+		snapshotAt("b", 2, 0.6),
+		snapshotAt("b", 3, 0.7),
+		snapshotAt("c", 4, 0.8),
+	}
+
+	// ACT
+	got := history.LastCommits(snapshots, 2)
+
+	// ASSERT
+	require.Len(t, got, 2)
+	assert.Equal(t, "b", got[0].Commit)
+	assert.EqualValues(t, 0.7, got[0].Breakdown.Coverage)
+	assert.Equal(t, "c", got[1].Commit)
+	assert.Len(t, history.LastCommits(snapshots, -1), 3)
+}
+
+func TestLatestOfBranch(t *testing.T) {
+	main := snapshotAt("a", 1, 0.8)
+	main.Branch = "main"
+	feature := snapshotAt("b", 2, 0.7)
+	feature.Branch = "feature"
+
+	// ACT
+	latest, found := history.LatestOfBranch([]history.Snapshot{feature, main}, "main")
+	_, missing := history.LatestOfBranch([]history.Snapshot{feature}, "main")
+
+	// ASSERT
+	assert.True(t, found)
+	assert.Equal(t, "a", latest.Commit)
+	assert.False(t, missing)
+}
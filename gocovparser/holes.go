@@ -0,0 +1,102 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// GetCoverageHoles returns the contiguous regions of uncovered statements of every file, largest first, so tests
+// can be added where they have the most impact. A region spans consecutive uncovered blocks of a file and ends at
+// the next executed block. A limit lower than 1 returns every region.
+func GetCoverageHoles(items []Coverage, limit int) []CoverageHole {
+	holes := []CoverageHole{}
+
+	for _, cov := range items {
+		holes = append(holes, fileHoles(cov)...)
+	}
+
+	return rankHoles(holes, limit)
+}
+
+// GroupCoverageHoles returns the largest coverage holes per key of the group (e.g. ByPackage), as
+// GetCoverageHoles does, keeping at most limit holes per key.
+func GroupCoverageHoles(items []Coverage, group ParseGroup, limit int) map[string][]CoverageHole {
+	grouped := make(map[string][]CoverageHole)
+
+	for _, cov := range items {
+		key := group.Key(cov)
+		grouped[key] = append(grouped[key], fileHoles(cov)...)
+	}
+
+	result := make(map[string][]CoverageHole, len(grouped))
+
+	for key, holes := range grouped {
+		if len(holes) > 0 {
+			result[key] = rankHoles(holes, limit)
+		}
+	}
+
+	return result
+}
+
+// fileHoles returns the regions of consecutive uncovered blocks of the file, in position order.
+// Blocks without statements neither extend nor end a region.
+func fileHoles(cov Coverage) []CoverageHole {
+	blocks := make([]cover.ProfileBlock, len(cov.Blocks))
+	copy(blocks, cov.Blocks)
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blockLess(blocks[i], blocks[j])
+	})
+
+	holes := []CoverageHole{}
+	var current *CoverageHole
+
+	for _, block := range blocks {
+		switch {
+		case block.NumStmt == 0:
+			continue
+		case block.Count > 0:
+			current = nil
+
+			continue
+		case current == nil:
+			holes = append(holes, CoverageHole{
+				FileName: cov.FileName,
+				Path:     cov.Path,
+				Lines:    LineRange{Start: block.StartLine, End: block.EndLine},
+			})
+			current = &holes[len(holes)-1]
+		}
+
+		current.Statements += block.NumStmt
+
+		if block.EndLine > current.Lines.End {
+			current.Lines.End = block.EndLine
+		}
+	}
+
+	return holes
+}
+
+// rankHoles sorts holes by decreasing statements, then by file and line, and keeps the first limit ones.
+func rankHoles(holes []CoverageHole, limit int) []CoverageHole {
+	sort.SliceStable(holes, func(i, j int) bool {
+		if holes[i].Statements != holes[j].Statements {
+			return holes[i].Statements > holes[j].Statements
+		}
+
+		if holes[i].FileName != holes[j].FileName {
+			return holes[i].FileName < holes[j].FileName
+		}
+
+		return holes[i].Lines.Start < holes[j].Lines.Start
+	})
+
+	if limit > 0 && len(holes) > limit {
+		holes = holes[:limit]
+	}
+
+	return holes
+}
@@ -0,0 +1,52 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const holesProfile = `
+mode: set
+example.com/org/repo/a/a.go:1.1,3.2 2 0
+example.com/org/repo/a/a.go:4.1,6.2 3 0
+example.com/org/repo/a/a.go:7.1,8.2 1 1
+example.com/org/repo/a/a.go:9.1,9.20 0 0
+example.com/org/repo/a/a.go:10.1,12.2 1 0
+example.com/org/repo/b/b.go:1.1,20.2 4 0
+`
+
+func TestCanGetCoverageHoles(t *testing.T) {
+	items, err := gocovparser.Parse(holesProfile)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetCoverageHoles(items, 0)
+
+	// ASSERT
+	assert.Equal(t, []gocovparser.CoverageHole{
+		{FileName: "example.com/org/repo/a/a.go", Path: "a/a.go", Lines: gocovparser.LineRange{Start: 1, End: 6}, Statements: 5},
+		{FileName: "example.com/org/repo/b/b.go", Path: "b/b.go", Lines: gocovparser.LineRange{Start: 1, End: 20}, Statements: 4},
+		{FileName: "example.com/org/repo/a/a.go", Path: "a/a.go", Lines: gocovparser.LineRange{Start: 10, End: 12}, Statements: 1},
+	}, got)
+
+	assert.Len(t, gocovparser.GetCoverageHoles(items, 1), 1)
+}
+
+func TestCanGroupCoverageHoles(t *testing.T) {
+	items, err := gocovparser.Parse(holesProfile)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GroupCoverageHoles(items, gocovparser.ByPackage(), 1)
+
+	// ASSERT
+	require.Len(t, got, 2)
+	assert.Equal(t, 5, got["example.com/org/repo/a"][0].Statements)
+	assert.Equal(t, 4, got["example.com/org/repo/b"][0].Statements)
+	assert.Len(t, got["example.com/org/repo/a"], 1)
+}
@@ -0,0 +1,139 @@
+package gocovparser
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+const (
+	ignoreDirective      = "coverage:ignore"
+	ignoreStartDirective = "coverage:ignore-start"
+	ignoreEndDirective   = "coverage:ignore-end"
+)
+
+// WithIgnoreDirectives drops the blocks marked by `//coverage:ignore` directives in the source files
+// found under sourceRoot, as ApplyIgnoreDirectives does.
+func WithIgnoreDirectives(sourceRoot string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.ignoreRoot = sourceRoot
+	}
+}
+
+// ApplyIgnoreDirectives drops the blocks starting on lines ignored by source directives and records the number
+// of dropped statements in ExcludedStatements. Source files are read from their SourcePath under sourceRoot; files
+// that don't exist are left untouched.
+//
+// A `//coverage:ignore` comment ignores the statement or declaration starting on its line or, when alone on its
+// line, the one starting on the next line. Lines between `//coverage:ignore-start` and `//coverage:ignore-end`
+// are ignored as well.
+func ApplyIgnoreDirectives(items []Coverage, sourceRoot string) ([]Coverage, error) {
+	result := make([]Coverage, 0, len(items))
+
+	for _, cov := range items {
+		filename := SourcePath(cov, sourceRoot)
+
+		if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+			result = append(result, cov)
+
+			continue
+		}
+
+		fset, file, err := parseSource(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		ignored := ignoredLines(fset, file)
+		blocks := make([]cover.ProfileBlock, 0, len(cov.Blocks))
+
+		for _, b := range cov.Blocks {
+			if ignored[b.StartLine] {
+				cov.ExcludedStatements += b.NumStmt
+
+				continue
+			}
+
+			blocks = append(blocks, b)
+		}
+
+		cov.Blocks = blocks
+		result = append(result, cov)
+	}
+
+	return result, nil
+}
+
+// ignoredLines returns the set of lines ignored by coverage directives in the file.
+func ignoredLines(fset *token.FileSet, file *ast.File) map[int]bool {
+	ignored := make(map[int]bool)
+	startLine := 0
+
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			directive := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			line := fset.Position(comment.Pos()).Line
+
+			switch directive {
+			case ignoreStartDirective:
+				startLine = line
+			case ignoreEndDirective:
+				if startLine > 0 {
+					markLines(ignored, startLine, line)
+				}
+
+				startLine = 0
+			case ignoreDirective:
+				from, to := ignoredNode(fset, file, line)
+				markLines(ignored, from, to)
+			}
+		}
+	}
+
+	if startLine > 0 {
+		markLines(ignored, startLine, fset.File(file.Pos()).LineCount())
+	}
+
+	return ignored
+}
+
+// ignoredNode returns the lines of the outermost statement or declaration starting on line, or on the next line.
+// If there is none, only the directive line is ignored.
+func ignoredNode(fset *token.FileSet, file *ast.File, line int) (int, int) {
+	for _, target := range []int{line, line + 1} {
+		var found ast.Node
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			if found != nil || node == nil {
+				return false
+			}
+
+			switch node.(type) {
+			case ast.Stmt, ast.Decl:
+				if fset.Position(node.Pos()).Line == target {
+					found = node
+
+					return false
+				}
+			}
+
+			return true
+		})
+
+		if found != nil {
+			return target, fset.Position(found.End()).Line
+		}
+	}
+
+	return line, line
+}
+
+func markLines(lines map[int]bool, from, to int) {
+	for line := from; line <= to; line++ {
+		lines[line] = true
+	}
+}
@@ -0,0 +1,116 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ignoreSource = `package pkg
+
+func Handle(err error) int {
+	if err != nil { //coverage:ignore
+		panic(err)
+	}
+
+	// coverage:ignore
+	if err == nil {
+		return 1
+	}
+
+	//coverage:ignore-start
+	println("debug")
+	//coverage:ignore-end
+
+	return 0
+}
+`
+
+const ignoreCoverage = `
+mode: set
+github.com/heynemann/go-cov-parser/pkg/ignore.go:3.28,4.17 1 1
+github.com/heynemann/go-cov-parser/pkg/ignore.go:4.17,6.3 1 0
+github.com/heynemann/go-cov-parser/pkg/ignore.go:9.2,9.16 1 1
+github.com/heynemann/go-cov-parser/pkg/ignore.go:9.16,11.3 1 1
+github.com/heynemann/go-cov-parser/pkg/ignore.go:14.2,14.18 2 0
+github.com/heynemann/go-cov-parser/pkg/ignore.go:17.2,17.10 1 1
+`
+
+func writeIgnoreSource(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "ignore.go"), []byte(ignoreSource), 0o600))
+
+	return root
+}
+
+func TestCanApplyIgnoreDirectives(t *testing.T) {
+	root := writeIgnoreSource(t)
+	items, err := gocovparser.Parse(ignoreCoverage)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ApplyIgnoreDirectives(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Len(t, got[0].Blocks, 2)
+	assert.Equal(t, 5, got[0].ExcludedStatements)
+	assert.Len(t, items[0].Blocks, 6)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(got)
+	assert.Equal(t, 2, breakdown.Statements)
+	assert.Equal(t, 5, breakdown.ExcludedStatements)
+}
+
+func TestCanParseWithIgnoreDirectives(t *testing.T) {
+	root := writeIgnoreSource(t)
+
+	// ACT
+	got, err := gocovparser.Parse(ignoreCoverage, gocovparser.WithIgnoreDirectives(root))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 5, got[0].ExcludedStatements)
+}
+
+func TestCanParseWithIgnoreDirectivesOfWorkspaceModules(t *testing.T) {
+	root := writeWorkspace(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "api", "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "pkg", "ignore.go"), []byte(ignoreSource), 0o600))
+
+	coverage := strings.ReplaceAll(ignoreCoverage, "github.com/heynemann/go-cov-parser/", "example.dev/mono/api/")
+
+	// ACT
+	got, err := gocovparser.Parse(
+		coverage, gocovparser.WithGoWork(filepath.Join(root, "go.work")), gocovparser.WithIgnoreDirectives(t.TempDir()),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 5, got[0].ExcludedStatements)
+}
+
+func TestApplyIgnoreDirectivesKeepsFilesWithoutSource(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ApplyIgnoreDirectives(items, t.TempDir())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, items, got)
+}
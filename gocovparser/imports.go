@@ -0,0 +1,130 @@
+package gocovparser
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+const lcovDataFields = 2
+
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			FileName string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// ParseLCOV parses an LCOV tracefile into coverage items.
+// Each covered line (DA record) becomes a single statement block and files are identified by their SF path.
+func ParseLCOV(r io.Reader) ([]Coverage, error) {
+	files := [][]Coverage{}
+	scanner := bufio.NewScanner(r)
+
+	var current *Coverage
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = &Coverage{Mode: ModeCount}
+			current.FileName = strings.TrimPrefix(line, "SF:")
+			current.Path = current.FileName
+		case strings.HasPrefix(line, "DA:"):
+			if current == nil {
+				return nil, errors.Wrapf(ErrInvalidCoverageData, "line %d: DA record outside of a file record", lineNumber)
+			}
+
+			block, err := parseLCOVData(strings.TrimPrefix(line, "DA:"))
+			if err != nil {
+				return nil, errors.Wrapf(ErrInvalidCoverageData, "line %d: %s", lineNumber, err.Error())
+			}
+
+			current.Blocks = append(current.Blocks, block)
+		case line == "end_of_record":
+			if current != nil {
+				files = append(files, []Coverage{*current})
+			}
+
+			current = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidCoverageData, err.Error())
+	}
+
+	return MergeCoverage(files...)
+}
+
+func parseLCOVData(data string) (cover.ProfileBlock, error) {
+	fields := strings.Split(data, ",")
+	if len(fields) < lcovDataFields {
+		return cover.ProfileBlock{}, errors.Errorf("invalid DA record %q", data)
+	}
+
+	number, err := strconv.Atoi(fields[0])
+	if err != nil || number < 1 {
+		return cover.ProfileBlock{}, errors.Errorf("invalid line number in DA record %q", data)
+	}
+
+	hits, err := strconv.Atoi(fields[1])
+	if err != nil || hits < 0 {
+		return cover.ProfileBlock{}, errors.Errorf("invalid hit count in DA record %q", data)
+	}
+
+	return lineBlock(number, hits), nil
+}
+
+// ParseCobertura parses a Cobertura XML report into coverage items.
+// Each line becomes a single statement block and files are identified by the class filename.
+func ParseCobertura(r io.Reader) ([]Coverage, error) {
+	report := coberturaReport{}
+
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return nil, errors.Wrapf(ErrInvalidCoverageData, err.Error())
+	}
+
+	files := [][]Coverage{}
+
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			cov := Coverage{
+				FileName: class.FileName,
+				Path:     class.FileName,
+				Mode:     ModeCount,
+			}
+
+			for _, line := range class.Lines {
+				cov.Blocks = append(cov.Blocks, lineBlock(line.Number, line.Hits))
+			}
+
+			files = append(files, []Coverage{cov})
+		}
+	}
+
+	return MergeCoverage(files...)
+}
+
+// lineBlock returns a single statement block spanning the whole line.
+func lineBlock(line, count int) cover.ProfileBlock {
+	return cover.ProfileBlock{
+		StartLine: line,
+		StartCol:  1,
+		EndLine:   line,
+		EndCol:    1,
+		NumStmt:   1,
+		Count:     count,
+	}
+}
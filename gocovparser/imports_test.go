@@ -0,0 +1,112 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanParseLCOV(t *testing.T) {
+	data := `TN:
+SF:src/app.js
+FN:1,main
+DA:1,3
+DA:2,0
+DA:4,1,abcdef
+LF:3
+LH:2
+end_of_record
+TN:
+SF:src/util.js
+DA:7,0
+end_of_record
+`
+
+	// ACT
+	got, err := gocovparser.ParseLCOV(strings.NewReader(data))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "src/app.js", got[0].FileName)
+	assert.Equal(t, "src/app.js", got[0].Path)
+	require.Len(t, got[0].Blocks, 3)
+	assert.Equal(t, 4, got[0].Blocks[2].StartLine)
+	assert.Equal(t, 1, got[0].Blocks[2].Count)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(got)
+	assert.Equal(t, 4, breakdown.Statements)
+	assert.Equal(t, 2, breakdown.CoveredStatements)
+}
+
+func TestParseLCOVFailsForInvalidRecords(t *testing.T) {
+	tests := map[string]string{
+		"data outside of file": "DA:1,1\n",
+		"missing hits":         "SF:a.js\nDA:1\n",
+		"invalid line":         "SF:a.js\nDA:x,1\n",
+		"negative hits":        "SF:a.js\nDA:1,-1\n",
+	}
+
+	for name, data := range tests {
+		data := data
+
+		t.Run(name, func(t *testing.T) {
+			// ACT
+			_, err := gocovparser.ParseLCOV(strings.NewReader(data))
+
+			// ASSERT
+			require.Error(t, err)
+			assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+		})
+	}
+}
+
+func TestCanParseCobertura(t *testing.T) {
+	data := `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <packages>
+    <package name="app">
+      <classes>
+        <class name="App" filename="src/App.java">
+          <lines>
+            <line number="3" hits="2"/>
+            <line number="4" hits="0"/>
+          </lines>
+        </class>
+        <class name="App$Inner" filename="src/App.java">
+          <lines>
+            <line number="10" hits="1"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+
+	// ACT
+	got, err := gocovparser.ParseCobertura(strings.NewReader(data))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "src/App.java", got[0].FileName)
+	assert.Len(t, got[0].Blocks, 3)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(got)
+	assert.EqualValues(t, 2.0/3.0, breakdown.Coverage)
+}
+
+func TestParseCoberturaFailsForInvalidXML(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseCobertura(strings.NewReader("<coverage"))
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
@@ -0,0 +1,186 @@
+package gocovparser
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// SchemaVersion is the version of the JSON representation of coverage results.
+// It is bumped whenever a change to the representation is not backwards compatible.
+const SchemaVersion = 1
+
+// Results bundles parsed coverage and its aggregations so they can be cached as JSON between CI steps.
+type Results struct {
+	Coverage  []Coverage
+	Groups    ParseGroupResult
+	Breakdown *OverallCoverageBreakdown
+}
+
+type jsonResults struct {
+	SchemaVersion int                       `json:"schemaVersion"`
+	Coverage      []Coverage                `json:"coverage"`
+	Groups        ParseGroupResult          `json:"groups,omitempty"`
+	Breakdown     *OverallCoverageBreakdown `json:"breakdown,omitempty"`
+}
+
+type jsonCoverage struct {
+	FileName   string      `json:"fileName"`
+	Host       string      `json:"host"`
+	Owner      string      `json:"owner"`
+	Repo       string      `json:"repo"`
+	Path       string      `json:"path"`
+	Blocks     []jsonBlock `json:"blocks"`
+	Excluded   int         `json:"excludedStatements,omitempty"`
+	Mode       string      `json:"mode,omitempty"`
+	Module     string      `json:"module,omitempty"`
+	Local      string      `json:"localPath,omitempty"`
+	Classified bool        `json:"classified,omitempty"`
+	Generated  bool        `json:"generated,omitempty"`
+	Exists     bool        `json:"exists,omitempty"`
+}
+
+type jsonBlock struct {
+	StartLine  int `json:"startLine"`
+	StartCol   int `json:"startCol"`
+	EndLine    int `json:"endLine"`
+	EndCol     int `json:"endCol"`
+	Statements int `json:"statements"`
+	Count      int `json:"count"`
+}
+
+type jsonBreakdown struct {
+	Files              int     `json:"files"`
+	Blocks             int     `json:"blocks"`
+	CoveredBlocks      int     `json:"coveredBlocks"`
+	Statements         int     `json:"statements"`
+	CoveredStatements  int     `json:"coveredStatements"`
+	Coverage           float64 `json:"coverage"`
+	ExcludedStatements int     `json:"excludedStatements,omitempty"`
+}
+
+// MarshalJSON writes the results along with the schema version. Coverage items are written sorted by file name.
+func (r Results) MarshalJSON() ([]byte, error) {
+	items := SortCoverage(r.Coverage)
+
+	return json.Marshal(jsonResults{
+		SchemaVersion: SchemaVersion,
+		Coverage:      items,
+		Groups:        r.Groups,
+		Breakdown:     r.Breakdown,
+	})
+}
+
+// UnmarshalJSON loads results, failing with ErrUnsupportedSchemaVersion for unknown schema versions.
+func (r *Results) UnmarshalJSON(data []byte) error {
+	decoded := jsonResults{}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.Wrap(err, "failed to unmarshal results")
+	}
+
+	if decoded.SchemaVersion != SchemaVersion {
+		return errors.Wrapf(ErrUnsupportedSchemaVersion, "got version %d, expected %d", decoded.SchemaVersion, SchemaVersion)
+	}
+
+	*r = Results{
+		Coverage:  decoded.Coverage,
+		Groups:    decoded.Groups,
+		Breakdown: decoded.Breakdown,
+	}
+
+	return nil
+}
+
+// MarshalJSON writes the coverage with stable field names.
+func (c Coverage) MarshalJSON() ([]byte, error) {
+	blocks := make([]jsonBlock, 0, len(c.Blocks))
+
+	for _, b := range c.Blocks {
+		blocks = append(blocks, jsonBlock{
+			StartLine:  b.StartLine,
+			StartCol:   b.StartCol,
+			EndLine:    b.EndLine,
+			EndCol:     b.EndCol,
+			Statements: b.NumStmt,
+			Count:      b.Count,
+		})
+	}
+
+	return json.Marshal(jsonCoverage{
+		FileName: c.FileName,
+		Host:     c.Host,
+		Owner:    c.Owner,
+		Repo:     c.Repo,
+		Path:     c.Path,
+		Blocks:   blocks,
+		Excluded: c.ExcludedStatements,
+		Mode:     c.Mode,
+		Module:   c.Module,
+		Local:    c.LocalPath,
+
+		Classified: c.Classified,
+		Generated:  c.Generated,
+		Exists:     c.Exists,
+	})
+}
+
+// UnmarshalJSON loads coverage written by MarshalJSON.
+func (c *Coverage) UnmarshalJSON(data []byte) error {
+	decoded := jsonCoverage{}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.Wrap(err, "failed to unmarshal coverage")
+	}
+
+	blocks := make([]cover.ProfileBlock, 0, len(decoded.Blocks))
+
+	for _, b := range decoded.Blocks {
+		blocks = append(blocks, cover.ProfileBlock{
+			StartLine: b.StartLine,
+			StartCol:  b.StartCol,
+			EndLine:   b.EndLine,
+			EndCol:    b.EndCol,
+			NumStmt:   b.Statements,
+			Count:     b.Count,
+		})
+	}
+
+	*c = Coverage{
+		FileName: decoded.FileName,
+		Host:     decoded.Host,
+		Owner:    decoded.Owner,
+		Repo:     decoded.Repo,
+		Path:     decoded.Path,
+		Blocks:   blocks,
+
+		ExcludedStatements: decoded.Excluded,
+		Mode:               decoded.Mode,
+		Module:             decoded.Module,
+		LocalPath:          decoded.Local,
+		Classified:         decoded.Classified,
+		Generated:          decoded.Generated,
+		Exists:             decoded.Exists,
+	}
+
+	return nil
+}
+
+// MarshalJSON writes the breakdown with stable field names.
+func (b OverallCoverageBreakdown) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonBreakdown(b))
+}
+
+// UnmarshalJSON loads a breakdown written by MarshalJSON.
+func (b *OverallCoverageBreakdown) UnmarshalJSON(data []byte) error {
+	decoded := jsonBreakdown{}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.Wrap(err, "failed to unmarshal coverage breakdown")
+	}
+
+	*b = OverallCoverageBreakdown(decoded)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanRoundTripResultsThroughJSON(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	groups, err := gocovparser.GroupCoverage(items, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup)
+	require.NoError(t, err)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+	results := gocovparser.Results{
+		Coverage:  items,
+		Groups:    groups,
+		Breakdown: &breakdown,
+	}
+
+	// ACT
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+
+	got := gocovparser.Results{}
+	err = json.Unmarshal(data, &got)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, results, got)
+}
+
+func TestResultsJSONHasStableSchema(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture5(t))
+	require.NoError(t, err)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items)
+
+	// ACT
+	data, err := json.Marshal(gocovparser.Results{Coverage: items, Breakdown: &breakdown})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"schemaVersion": 1,
+		"coverage": [{
+			"fileName": "go.uber.org/zap/writer.go",
+			"host": "go.uber.org",
+			"owner": "zap",
+			"repo": "",
+			"path": "writer.go",
+			"blocks": [{"startLine": 50, "startCol": 65, "endLine": 52, "endCol": 16, "statements": 2, "count": 1}],
+			"mode": "set"
+		}],
+		"breakdown": {
+			"files": 1,
+			"blocks": 1,
+			"coveredBlocks": 1,
+			"statements": 2,
+			"coveredStatements": 2,
+			"coverage": 1
+		}
+	}`, string(data))
+}
+
+func TestUnmarshalResultsFailsForUnknownSchemaVersion(t *testing.T) {
+	got := gocovparser.Results{}
+
+	// ACT
+	err := json.Unmarshal([]byte(`{"schemaVersion": 999, "coverage": []}`), &got)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrUnsupportedSchemaVersion)
+}
@@ -0,0 +1,56 @@
+package gocovparser
+
+// GetLineCoverage expands coverage blocks into a per-file (by FileName) map of line coverage.
+// Lines touched by several blocks keep the highest count, and are partial if only some blocks were executed.
+func GetLineCoverage(items []Coverage) map[string]FileLineCoverage {
+	return GetLineCoverageWithStrategy(items, MergeDefault)
+}
+
+// GetLineCoverageWithStrategy computes the line coverage as GetLineCoverage does, combining the hits of the blocks
+// touching each line with strategy, e.g. MergeSum to total the executions of overlapping blocks.
+func GetLineCoverageWithStrategy(items []Coverage, strategy MergeStrategy) map[string]FileLineCoverage {
+	strategy = strategy.lines()
+	result := make(map[string]FileLineCoverage, len(items))
+
+	for _, cov := range items {
+		lines, found := result[cov.FileName]
+		if !found {
+			lines = make(FileLineCoverage)
+			result[cov.FileName] = lines
+		}
+
+		for _, b := range cov.Blocks {
+			if b.NumStmt == 0 {
+				continue
+			}
+
+			for line := b.StartLine; line <= b.EndLine; line++ {
+				current, seen := lines[line]
+				lines[line] = mergeLine(current, seen, b.Count, strategy)
+			}
+		}
+	}
+
+	return result
+}
+
+func mergeLine(current LineCoverage, seen bool, count int, strategy MergeStrategy) LineCoverage {
+	status, covered := LineUncovered, 0
+	if count > 0 {
+		status, covered = LineCovered, 1
+	}
+
+	if !seen {
+		return LineCoverage{Hits: strategy.combine(0, count), Status: status, Blocks: 1, CoveredBlocks: covered}
+	}
+
+	if current.Status != status {
+		current.Status = LinePartial
+	}
+
+	current.Hits = strategy.combine(current.Hits, count)
+	current.Blocks++
+	current.CoveredBlocks += covered
+
+	return current
+}
@@ -0,0 +1,62 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanGetLineCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,12.20 2 4
+github.com/heynemann/go-cov-parser/gocovparser/core.go:12.20,14.3 1 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,20.30 1 7
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.30,21.3 1 9
+github.com/heynemann/go-cov-parser/gocovparser/core.go:30.1,31.2 0 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got := gocovparser.GetLineCoverage(items)
+
+	// ASSERT
+	require.Contains(t, got, "github.com/heynemann/go-cov-parser/gocovparser/core.go")
+
+	lines := got["github.com/heynemann/go-cov-parser/gocovparser/core.go"]
+	assert.Equal(t, gocovparser.FileLineCoverage{
+		10: {Hits: 4, Status: gocovparser.LineCovered, Blocks: 1, CoveredBlocks: 1},
+		11: {Hits: 4, Status: gocovparser.LineCovered, Blocks: 1, CoveredBlocks: 1},
+		12: {Hits: 4, Status: gocovparser.LinePartial, Blocks: 2, CoveredBlocks: 1},
+		13: {Hits: 0, Status: gocovparser.LineUncovered, Blocks: 1},
+		14: {Hits: 0, Status: gocovparser.LineUncovered, Blocks: 1},
+		20: {Hits: 9, Status: gocovparser.LineCovered, Blocks: 2, CoveredBlocks: 2},
+		21: {Hits: 9, Status: gocovparser.LineCovered, Blocks: 1, CoveredBlocks: 1},
+	}, lines)
+}
+
+func TestLineStatusString(t *testing.T) {
+	assert.Equal(t, "uncovered", gocovparser.LineUncovered.String())
+	assert.Equal(t, "partial", gocovparser.LinePartial.String())
+	assert.Equal(t, "covered", gocovparser.LineCovered.String())
+	assert.Equal(t, "unknown", gocovparser.LineStatus(42).String())
+}
+
+func TestGetLineCoverageWithStrategy(t *testing.T) {
+	items, err := gocovparser.Parse("mode: count\ngithub.com/a/b/c.go:1.1,2.5 1 2\ngithub.com/a/b/c.go:2.5,3.2 1 3\n")
+	require.NoError(t, err)
+
+	// ACT
+	maxed := gocovparser.GetLineCoverage(items)["github.com/a/b/c.go"]
+	summed := gocovparser.GetLineCoverageWithStrategy(items, gocovparser.MergeSum)["github.com/a/b/c.go"]
+	flags := gocovparser.GetLineCoverageWithStrategy(items, gocovparser.MergeBooleanOr)["github.com/a/b/c.go"]
+
+	// ASSERT
+	assert.Equal(t, []int{2, 3, 3}, []int{maxed[1].Hits, maxed[2].Hits, maxed[3].Hits})
+	assert.Equal(t, []int{2, 5, 3}, []int{summed[1].Hits, summed[2].Hits, summed[3].Hits})
+	assert.Equal(t, []int{1, 1, 1}, []int{flags[1].Hits, flags[2].Hits, flags[3].Hits})
+}
@@ -0,0 +1,253 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// MergeStrategy is how the counts of a block or line reported several times are combined.
+type MergeStrategy int
+
+const (
+	// MergeDefault sums the counts of blocks, as `go tool covdata merge` does, or combines them with a logical or
+	// in set mode. Lines touched by several blocks keep the highest count. This is the default.
+	MergeDefault MergeStrategy = iota
+
+	// MergeSum sums counts, e.g. to total the executions of every test shard.
+	MergeSum
+
+	// MergeMax keeps the highest count, e.g. so retried jobs don't count executions twice.
+	MergeMax
+
+	// MergeBooleanOr counts 1 if any count is above zero and 0 otherwise, only telling whether code ran.
+	MergeBooleanOr
+)
+
+// String returns the name of the merge strategy.
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeDefault:
+		return "default"
+	case MergeSum:
+		return "sum"
+	case MergeMax:
+		return "max"
+	case MergeBooleanOr:
+		return "or"
+	default:
+		return "unknown"
+	}
+}
+
+// WithMergeStrategy sets how the counts of blocks reported several times in a profile (e.g. in concatenated
+// profiles, or in files remapped to the same name) are combined. Defaults to MergeDefault.
+func WithMergeStrategy(strategy MergeStrategy) ParseOption {
+	return func(opts *parseOptions) {
+		opts.mergeStrategy = strategy
+	}
+}
+
+// blocks returns the strategy of MergeDefault for blocks of the mode.
+func (s MergeStrategy) blocks(mode string) MergeStrategy {
+	switch {
+	case s != MergeDefault:
+		return s
+	case mode == ModeSet:
+		return MergeBooleanOr
+	default:
+		return MergeSum
+	}
+}
+
+// lines returns the strategy of MergeDefault for lines.
+func (s MergeStrategy) lines() MergeStrategy {
+	if s == MergeDefault {
+		return MergeMax
+	}
+
+	return s
+}
+
+// combine returns the count of a and b, MergeDefault summing them.
+func (s MergeStrategy) combine(a, b int) int {
+	switch s {
+	case MergeMax:
+		if b > a {
+			return b
+		}
+
+		return a
+	case MergeBooleanOr:
+		if a > 0 || b > 0 {
+			return 1
+		}
+
+		return 0
+	default:
+		return a + b
+	}
+}
+
+// MergeCoverage merges several coverage results (e.g. from test shards) into one.
+// Blocks reported for the same file and position have their counts summed, as `go tool covdata merge` does,
+// or combined with a logical or in set mode. Coverage collected in different modes is merged as concatenated
+// profiles are: in set mode if any of it is, since counts can't be recovered from set coverage. Unknown modes
+// fail with ErrInconsistentCoverageMode.
+func MergeCoverage(items ...[]Coverage) ([]Coverage, error) {
+	return MergeCoverageWithStrategy(MergeDefault, items...)
+}
+
+// MergeCoverageWithStrategy merges coverage as MergeCoverage does, combining the counts of blocks at the same
+// position with strategy.
+func MergeCoverageWithStrategy(strategy MergeStrategy, items ...[]Coverage) ([]Coverage, error) {
+	files := make(map[string]*Coverage)
+	mode := ""
+
+	for _, set := range items {
+		for _, cov := range set {
+			switch {
+			case cov.Mode == "" || cov.Mode == mode:
+			case mode == "":
+				mode = cov.Mode
+			default:
+				merged, ok := segmentsMode(mode, cov.Mode)
+				if !ok {
+					return nil, errors.Wrapf(ErrInconsistentCoverageMode, "%q and %q in %q", mode, cov.Mode, cov.FileName)
+				}
+
+				mode = merged
+			}
+
+			merged, found := files[cov.FileName]
+			if !found {
+				merged = &Coverage{
+					FileName:  cov.FileName,
+					Host:      cov.Host,
+					Owner:     cov.Owner,
+					Repo:      cov.Repo,
+					Path:      cov.Path,
+					Module:    cov.Module,
+					LocalPath: cov.LocalPath,
+				}
+				files[cov.FileName] = merged
+			}
+
+			merged.Blocks = append(merged.Blocks, cov.Blocks...)
+			merged.Classified = merged.Classified || cov.Classified
+			merged.Generated = merged.Generated || cov.Generated
+			merged.Exists = merged.Exists || cov.Exists
+
+			// excluded statements come from the same source file, so they are not summed
+			if cov.ExcludedStatements > merged.ExcludedStatements {
+				merged.ExcludedStatements = cov.ExcludedStatements
+			}
+		}
+	}
+
+	result := make([]Coverage, 0, len(files))
+
+	for _, merged := range files {
+		merged.Mode = mode
+
+		blocks, err := mergeBlocks(merged.Blocks, mode, strategy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge coverage for %q", merged.FileName)
+		}
+
+		merged.Blocks = blocks
+		result = append(result, *merged)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FileName < result[j].FileName
+	})
+
+	return result, nil
+}
+
+// mergeBlocks sorts blocks by position and combines the counts of blocks at the same position with strategy.
+func mergeBlocks(blocks []cover.ProfileBlock, mode string, strategy MergeStrategy) ([]cover.ProfileBlock, error) {
+	if len(blocks) == 0 {
+		return blocks, nil
+	}
+
+	strategy = strategy.blocks(mode)
+
+	// go test writes blocks in order, so sorting is usually not needed
+	if !blocksSorted(blocks) {
+		sort.SliceStable(blocks, func(i, j int) bool {
+			return blockLess(blocks[i], blocks[j])
+		})
+	}
+
+	result := blocks[:0]
+
+	for _, b := range blocks {
+		// combining with zero first makes counts consistent with the strategy, e.g. flags for MergeBooleanOr
+		b.Count = strategy.combine(0, b.Count)
+
+		if len(result) == 0 || !samePosition(result[len(result)-1], b) {
+			result = append(result, b)
+
+			continue
+		}
+
+		last := &result[len(result)-1]
+
+		if last.NumStmt != b.NumStmt {
+			return nil, errors.Wrapf(
+				ErrInconsistentCoverageBlocks,
+				"block %d.%d,%d.%d has %d and %d statements",
+				b.StartLine, b.StartCol, b.EndLine, b.EndCol, last.NumStmt, b.NumStmt,
+			)
+		}
+
+		last.Count = strategy.combine(last.Count, b.Count)
+	}
+
+	return result, nil
+}
+
+func blocksSorted(blocks []cover.ProfileBlock) bool {
+	for index := 1; index < len(blocks); index++ {
+		if blockLess(blocks[index], blocks[index-1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func blockLess(a, b cover.ProfileBlock) bool {
+	if a.StartLine != b.StartLine {
+		return a.StartLine < b.StartLine
+	}
+
+	if a.StartCol != b.StartCol {
+		return a.StartCol < b.StartCol
+	}
+
+	if a.EndLine != b.EndLine {
+		return a.EndLine < b.EndLine
+	}
+
+	return a.EndCol < b.EndCol
+}
+
+func samePosition(a, b cover.ProfileBlock) bool {
+	return a.StartLine == b.StartLine &&
+		a.StartCol == b.StartCol &&
+		a.EndLine == b.EndLine &&
+		a.EndCol == b.EndCol
+}
+
+// blockPosition identifies a block of a file by its position.
+type blockPosition struct {
+	startLine, startCol, endLine, endCol int
+}
+
+func positionOf(block cover.ProfileBlock) blockPosition {
+	return blockPosition{block.StartLine, block.StartCol, block.EndLine, block.EndCol}
+}
@@ -0,0 +1,179 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestCanMergeCoverage(t *testing.T) {
+	shard1, err := gocovparser.Parse(`
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:38.53,42.2 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 2 0
+`)
+	require.NoError(t, err)
+
+	shard2, err := gocovparser.Parse(`
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 2 3
+github.com/heynemann/go-cov-parser/gocovparser/filter.go:10.1,12.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.MergeCoverage(shard1, shard2)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/gocovparser/core.go", got[0].FileName)
+	assert.Equal(t, "go-cov-parser", got[0].Repo)
+	require.Len(t, got[0].Blocks, 2)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+	assert.Equal(t, 3, got[0].Blocks[1].Count)
+
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/gocovparser/filter.go", got[1].FileName)
+
+	breakdown := gocovparser.GetTotalCoverageBreakdown(got)
+	assert.Equal(t, 5, breakdown.Statements)
+	assert.Equal(t, 4, breakdown.CoveredStatements)
+	assert.EqualValues(t, 0.8, breakdown.Coverage)
+}
+
+func TestMergeCoverageDoesNotModifyInput(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	before, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.MergeCoverage(items, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, before, items)
+	assert.Equal(t, 2*items[0].Blocks[0].Count, got[0].Blocks[0].Count)
+}
+
+func TestMergeCoverageFailsForInconsistentBlocks(t *testing.T) {
+	items := []gocovparser.Coverage{
+		{
+			FileName: "github.com/heynemann/go-cov-parser/gocovparser/core.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 1}},
+		},
+	}
+	other := []gocovparser.Coverage{
+		{
+			FileName: "github.com/heynemann/go-cov-parser/gocovparser/core.go",
+			Blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 3, Count: 1}},
+		},
+	}
+
+	// ACT
+	_, err := gocovparser.MergeCoverage(items, other)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInconsistentCoverageBlocks)
+}
+
+func TestMergeCoverageCombinesSetModeCounts(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:38.53,42.2 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 2 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.MergeCoverage(items, items)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, gocovparser.ModeSet, got[0].Mode)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+	assert.Equal(t, 0, got[0].Blocks[1].Count)
+}
+
+func TestMergeCoverageMergesMixedModesInSetMode(t *testing.T) {
+	count, err := gocovparser.Parse("mode: count\ngithub.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 4\n")
+	require.NoError(t, err)
+
+	set, err := gocovparser.Parse("mode: set\ngithub.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 0\n")
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.MergeCoverage(count, set)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, gocovparser.ModeSet, got[0].Mode)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+}
+
+func TestMergeCoverageFailsForUnknownModes(t *testing.T) {
+	set, err := gocovparser.Parse("mode: set\ngithub.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1\n")
+	require.NoError(t, err)
+
+	unknown := []gocovparser.Coverage{set[0]}
+	unknown[0].Mode = "regonly"
+
+	// ACT
+	_, err = gocovparser.MergeCoverage(set, unknown)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInconsistentCoverageMode)
+}
+
+func TestMergeCoverageWithStrategy(t *testing.T) {
+	shard1, err := gocovparser.Parse("mode: count\ngithub.com/a/b/c.go:1.1,2.2 1 2\ngithub.com/a/b/c.go:3.1,4.2 1 0\n")
+	require.NoError(t, err)
+
+	shard2, err := gocovparser.Parse("mode: count\ngithub.com/a/b/c.go:1.1,2.2 1 5\ngithub.com/a/b/c.go:3.1,4.2 1 0\n")
+	require.NoError(t, err)
+
+	tests := map[gocovparser.MergeStrategy][]int{
+		gocovparser.MergeDefault:   {7, 0},
+		gocovparser.MergeSum:       {7, 0},
+		gocovparser.MergeMax:       {5, 0},
+		gocovparser.MergeBooleanOr: {1, 0},
+	}
+
+	for strategy, expected := range tests {
+		t.Run(strategy.String(), func(t *testing.T) {
+			// ACT
+			got, err := gocovparser.MergeCoverageWithStrategy(strategy, shard1, shard2)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			require.Len(t, got[0].Blocks, 2)
+			assert.Equal(t, expected, []int{got[0].Blocks[0].Count, got[0].Blocks[1].Count})
+		})
+	}
+}
+
+func TestParseWithMergeStrategy(t *testing.T) {
+	data := "mode: count\ngithub.com/a/b/c.go:1.1,2.2 1 2\ngithub.com/a/b/c.go:1.1,2.2 1 3\n"
+
+	// ACT
+	summed, sumErr := gocovparser.Parse(data)
+	maxed, maxErr := gocovparser.Parse(data, gocovparser.WithMergeStrategy(gocovparser.MergeMax))
+
+	// ASSERT
+	require.NoError(t, sumErr)
+	require.NoError(t, maxErr)
+	assert.Equal(t, 5, summed[0].Blocks[0].Count)
+	assert.Equal(t, 3, maxed[0].Blocks[0].Count)
+}
@@ -1,6 +1,19 @@
 package gocovparser
 
-import "golang.org/x/tools/cover"
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/tools/cover"
+)
+
+// Coverage modes, as passed to `go test -covermode`.
+const (
+	ModeSet    = "set"
+	ModeCount  = "count"
+	ModeAtomic = "atomic"
+)
 
 // ParseGroup to group coverage data by.
 type ParseGroup struct {
@@ -9,11 +22,580 @@ type ParseGroup struct {
 
 	// KeyFunc that returns the grouping key to use based on the coverage line.
 	KeyFunc func(string) string
+
+	// CoverageKeyFunc returns the grouping key of a coverage item, for keys that depend on more than its file name
+	// (e.g. its Repo or its Blocks). It takes precedence over KeyFunc when set.
+	CoverageKeyFunc func(Coverage) string
 }
 
 // ParseGroupResult represents results of a Group Coverage operation.
 type ParseGroupResult map[string]map[string]float64
 
+// GroupDetail holds the statement counts and coverage of a single group bucket.
+type GroupDetail struct {
+	// Covered is the number of statements covered by tests.
+	Covered int
+
+	// Total is the number of statements in the bucket.
+	Total int
+
+	// Percent is the ratio of covered statements (0 to 1).
+	Percent float64
+}
+
+// GroupStats holds the statement and line counts of a group bucket.
+type GroupStats struct {
+	// Statements is the number of statements in the bucket.
+	Statements int
+
+	// Covered is the number of statements covered by tests.
+	Covered int
+
+	// Lines is the number of source lines with statements.
+	Lines int
+
+	// CoveredLines is the number of lines executed by at least one block.
+	CoveredLines int
+
+	// Percent is the ratio of covered statements (0 to 1).
+	Percent float64
+}
+
+// DetailedGroupResult holds the stats of each key of each group, by group name and key.
+type DetailedGroupResult map[string]map[string]GroupStats
+
+// OverallCoverageBreakdown represents the aggregated coverage numbers of a set of coverage items.
+type OverallCoverageBreakdown struct {
+	// Files is the number of files with coverage data.
+	Files int
+
+	// Blocks is the number of coverage blocks.
+	Blocks int
+
+	// CoveredBlocks is the number of blocks executed at least once.
+	CoveredBlocks int
+
+	// Statements is the number of statements.
+	Statements int
+
+	// CoveredStatements is the number of statements executed at least once.
+	CoveredStatements int
+
+	// Coverage is the ratio of covered statements (0 to 1).
+	Coverage float64
+
+	// ExcludedStatements is the number of statements dropped by `//coverage:ignore` directives.
+	ExcludedStatements int
+}
+
+// FileBreakdown is the coverage breakdown of a single file.
+type FileBreakdown struct {
+	FileName           string
+	Path               string
+	Blocks             int
+	CoveredBlocks      int
+	Statements         int
+	CoveredStatements  int
+	Coverage           float64
+	ExcludedStatements int
+
+	// UncoveredRanges are the ranges of lines not executed by any block.
+	UncoveredRanges []LineRange
+}
+
+// CoverageNode is a node of the coverage tree: the root, a repository, a directory or a file.
+// Each node aggregates the statements of every file below it.
+type CoverageNode struct {
+	// Name of the node, e.g. the repository module path, a directory name or a file name.
+	Name string
+
+	// Path from the root to the node, e.g. github.com/owner/repo/dir/file.go.
+	Path string
+
+	IsFile   bool
+	Detail   GroupDetail
+	Children []*CoverageNode
+}
+
+// LineRange is an inclusive range of line numbers in a source file.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Contains returns whether line is within the range.
+func (r LineRange) Contains(line int) bool {
+	return line >= r.Start && line <= r.End
+}
+
+// String formats the range as "start-end", or just the line for single line ranges.
+func (r LineRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// DiffCoverageResult holds coverage restricted to the blocks touching changed lines.
+type DiffCoverageResult struct {
+	// Files holds the coverage of the blocks touching changed lines per file name.
+	Files map[string]GroupDetail
+
+	// Total holds the coverage of all blocks touching changed lines.
+	Total GroupDetail
+}
+
+// LineStatus is the coverage state of a single source line.
+type LineStatus int
+
+const (
+	// LineUncovered means no block touching the line was executed.
+	LineUncovered LineStatus = iota
+
+	// LinePartial means some, but not all, blocks touching the line were executed.
+	LinePartial
+
+	// LineCovered means every block touching the line was executed.
+	LineCovered
+)
+
+// String returns the name of the line status.
+func (s LineStatus) String() string {
+	switch s {
+	case LineUncovered:
+		return "uncovered"
+	case LinePartial:
+		return "partial"
+	case LineCovered:
+		return "covered"
+	default:
+		return "unknown"
+	}
+}
+
+// BlockChange is how the coverage of a block changed between two runs.
+type BlockChange int
+
+const (
+	// BlockNewlyCovered means the block was not executed in base but was in head.
+	BlockNewlyCovered BlockChange = iota
+
+	// BlockNewlyUncovered means the block was executed in base but not in head.
+	BlockNewlyUncovered
+
+	// BlockCountChanged means the block was executed in both runs, a different number of times.
+	BlockCountChanged
+
+	// BlockAdded means the block is only in head.
+	BlockAdded
+
+	// BlockRemoved means the block is only in base.
+	BlockRemoved
+)
+
+// String returns the name of the block change.
+func (c BlockChange) String() string {
+	switch c {
+	case BlockNewlyCovered:
+		return "newly covered"
+	case BlockNewlyUncovered:
+		return "newly uncovered"
+	case BlockCountChanged:
+		return "count changed"
+	case BlockAdded:
+		return "added"
+	case BlockRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// BlockDelta is a block whose coverage changed between two runs.
+type BlockDelta struct {
+	// Block is the block in head, or in base when it was removed.
+	Block cover.ProfileBlock
+
+	BaseCount int
+	HeadCount int
+	Change    BlockChange
+}
+
+// LineCoverage is the coverage of a single source line.
+type LineCoverage struct {
+	// Hits is the highest execution count of the blocks touching the line.
+	Hits int
+
+	// Status is the tri-state coverage of the line.
+	Status LineStatus
+
+	// Blocks is the number of blocks touching the line.
+	Blocks int
+
+	// CoveredBlocks is the number of executed blocks touching the line.
+	CoveredBlocks int
+}
+
+// FileLineCoverage maps line numbers to their coverage.
+type FileLineCoverage map[int]LineCoverage
+
+// PolicyRule declares the minimum coverage of the keys of a group.
+type PolicyRule struct {
+	// Group is the name of the parse group the rule applies to (e.g. "package" or "total").
+	Group string
+
+	// Key is the group key the rule applies to. It matches keys equal to it or ending in "/" + Key.
+	// An empty Key applies the rule to every key in the group.
+	Key string
+
+	// Minimum coverage ratio required (0 to 1).
+	Minimum float64
+
+	// MaxUncovered is the maximum number of uncovered statements of a key. Zero doesn't limit them.
+	MaxUncovered int
+
+	// MinStatements ignores keys with fewer statements, e.g. so tiny packages can't fail percentage rules.
+	MinStatements int
+}
+
+// Policy is a set of coverage rules that must hold for a coverage result.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// PolicyViolation describes a policy rule that does not hold for a group key.
+type PolicyViolation struct {
+	Rule PolicyRule
+
+	// Key is the group key that violates the rule. Empty if no key matched the rule.
+	Key string
+
+	// Coverage is the actual coverage ratio of the key.
+	Coverage float64
+
+	// Uncovered is the number of uncovered statements of the key, when it exceeds the MaxUncovered of the rule.
+	Uncovered int
+}
+
+// CoverageDelta is the change of a coverage ratio between two runs.
+type CoverageDelta struct {
+	Base  float64
+	Head  float64
+	Delta float64
+}
+
+// FileComparison compares the coverage of a single file between two runs.
+type FileComparison struct {
+	Base GroupDetail
+	Head GroupDetail
+
+	// Delta is the change in coverage ratio from base to head.
+	Delta float64
+
+	// NewUncoveredLines lists lines uncovered in head that were not uncovered in base.
+	NewUncoveredLines []int
+}
+
+// CoverageComparison is the result of comparing two coverage runs.
+type CoverageComparison struct {
+	Base OverallCoverageBreakdown
+	Head OverallCoverageBreakdown
+
+	// Delta is the change in total coverage ratio from base to head.
+	Delta float64
+
+	// Files compares each file in either run, by file name.
+	Files map[string]FileComparison
+
+	// Groups holds the coverage deltas per group name and key.
+	Groups map[string]map[string]CoverageDelta
+
+	// Regressions lists the files present in both runs whose coverage dropped, sorted by name.
+	Regressions []string
+}
+
+// TaggedCoverage is a coverage run labeled with the build tag it was collected with.
+type TaggedCoverage struct {
+	Tag   string
+	Items []Coverage
+}
+
+// TagOverlap breaks down the statements covered by two tagged runs. Every detail is relative to the statements
+// of either run, so Union is what running both achieves.
+type TagOverlap struct {
+	First  GroupDetail
+	Second GroupDetail
+
+	// OnlyFirst and OnlySecond are the statements covered by a single run.
+	OnlyFirst  GroupDetail
+	OnlySecond GroupDetail
+
+	Intersection GroupDetail
+	Union        GroupDetail
+}
+
+// TagComparison is the result of comparing the coverage of two tagged runs.
+type TagComparison struct {
+	// First and Second are the tags of the compared runs.
+	First  string
+	Second string
+
+	Total TagOverlap
+
+	// Files holds the overlap of each file in either run, by file name.
+	Files map[string]TagOverlap
+}
+
+// LineHeat is the execution count of a source line and its normalized heat.
+type LineHeat struct {
+	// Hits is the highest execution count of the blocks touching the line.
+	Hits int
+
+	// Heat is the logarithmically normalized execution count (0 to 1), relative to the hottest line of the file.
+	Heat float64
+}
+
+// FileHeat holds count-weighted coverage metrics of a file.
+type FileHeat struct {
+	// TotalHits is the sum of the execution counts of all blocks in the file.
+	TotalHits int
+
+	// MaxHits is the highest execution count of a block in the file.
+	MaxHits int
+
+	// Lines maps line numbers with statements to their heat.
+	Lines map[int]LineHeat
+}
+
+// UncoveredNeighbor is an uncovered block adjacent to an executed block.
+type UncoveredNeighbor struct {
+	FileName string
+
+	// Uncovered is the block that was never executed.
+	Uncovered cover.ProfileBlock
+
+	// Neighbor is the hottest executed block adjacent to Uncovered.
+	Neighbor cover.ProfileBlock
+}
+
+// CoverageHole is a contiguous region of uncovered statements of a file.
+type CoverageHole struct {
+	FileName string
+
+	// Path of the file relative to its module (or repository) root.
+	Path string
+
+	// Lines spans the uncovered region, from the first line of its first block to the last line of its last block.
+	Lines LineRange
+
+	// Statements is the number of uncovered statements in the region.
+	Statements int
+}
+
+// FunctionRisk is the coverage of a function weighted by its cyclomatic complexity.
+type FunctionRisk struct {
+	// Function is the fully-qualified name of the function, as in GroupByFunction.
+	Function string
+
+	FileName string
+
+	// Path of the file relative to its module (or repository) root.
+	Path string
+
+	// Lines spans the function declaration.
+	Lines LineRange
+
+	// Complexity is the cyclomatic complexity of the function.
+	Complexity int
+
+	// Statements is the number of statements of the function, and Covered the number of them covered by tests.
+	Statements int
+	Covered    int
+
+	// Coverage is the ratio of covered statements (0 to 1).
+	Coverage float64
+
+	// Risk is the complexity weighted by the ratio of uncovered statements: an untested function scores its
+	// complexity, a fully covered one 0.
+	Risk float64
+}
+
+// RiskReport is the coverage of the functions of a set of coverage items, weighted by their complexity.
+type RiskReport struct {
+	// Coverage is the ratio of covered statements of the functions (0 to 1).
+	Coverage float64
+
+	// RiskAdjustedCoverage is the ratio of covered statements, each weighted by the complexity of its function.
+	RiskAdjustedCoverage float64
+
+	// Functions ranked by decreasing Risk, so the most complex untested functions come first.
+	Functions []FunctionRisk
+}
+
+// CoverageBucket counts the files whose coverage is at least Min and below Max. The last bucket includes Max.
+type CoverageBucket struct {
+	Min   float64
+	Max   float64
+	Files int
+}
+
+// CoverageDistribution describes how coverage is spread over files, e.g. to tell whether a 75% total hides many
+// untested files. Coverage values are ratios (0 to 1) of the files with statements.
+type CoverageDistribution struct {
+	// Files is the number of files with statements.
+	Files int
+
+	// Buckets is a histogram of the file coverage, in ten buckets of 10%.
+	Buckets []CoverageBucket
+
+	Min    float64
+	Max    float64
+	Mean   float64
+	Median float64
+	P10    float64
+	P90    float64
+
+	// Uncovered is the number of files with no covered statement.
+	Uncovered int
+
+	// FullyCovered is the number of files with every statement covered.
+	FullyCovered int
+}
+
+// BranchDetail holds the number of branches and how many of them were taken.
+type BranchDetail struct {
+	Branches int
+	Taken    int
+
+	// Percent is the ratio of taken branches (0 to 1).
+	Percent float64
+}
+
+// BranchBreakdown holds the estimated branch coverage per file name and overall.
+type BranchBreakdown struct {
+	Files map[string]BranchDetail
+	Total BranchDetail
+}
+
+// APIDetail holds the coverage of the exported and unexported functions of a package.
+type APIDetail struct {
+	Exported   GroupDetail
+	Unexported GroupDetail
+}
+
+// APIBreakdown holds the coverage of the exported API per package import path and overall.
+type APIBreakdown struct {
+	Packages map[string]APIDetail
+	Total    APIDetail
+}
+
+// TestCase identifies a top level test of a package.
+type TestCase struct {
+	Package string
+	Name    string
+}
+
+// String returns the label of the test in a TestIndex: its package import path and name joined by a dot
+// (e.g. `github.com/owner/repo/pkg.TestA`), or its name alone when the package is not known.
+func (tc TestCase) String() string {
+	if tc.Package == "" {
+		return tc.Name
+	}
+
+	return tc.Package + "." + tc.Name
+}
+
+// StaleFile describes a file whose coverage does not match its source.
+type StaleFile struct {
+	FileName string
+	Path     string
+
+	// Missing is set when the source file does not exist.
+	Missing bool
+
+	// Lines is the number of lines of the source file.
+	Lines int
+
+	// InvalidBlocks are the blocks that start or end outside of the source file lines.
+	InvalidBlocks []cover.ProfileBlock
+}
+
+// StalenessReport is the result of validating coverage against the source files it was collected from.
+type StalenessReport struct {
+	// Files is the number of validated files.
+	Files int
+
+	// Stale are the files whose coverage does not match their source, sorted by file name.
+	Stale []StaleFile
+}
+
+// Module is a go module of a workspace.
+type Module struct {
+	// Path of the module, as declared in its go.mod file.
+	Path string
+
+	// Dir is the directory of the module.
+	Dir string
+}
+
+// BlameLine is the commit that last changed a source line, as reported by `git blame`.
+type BlameLine struct {
+	Commit     string
+	Author     string
+	AuthorMail string
+	AuthorTime time.Time
+}
+
+// BlameCount is the number of uncovered lines and statements attributed to an author, commit or age.
+type BlameCount struct {
+	Lines      int
+	Statements int
+}
+
+// BlameAge is the uncovered code last changed within MaxAge (and before the previous bucket).
+type BlameAge struct {
+	// MaxAge of the bucket. Zero for the last bucket, holding older code.
+	MaxAge time.Duration
+	BlameCount
+
+	// Percent is the ratio (0 to 1) of the uncovered lines in the bucket.
+	Percent float64
+}
+
+// BlameAttribution aggregates uncovered code by who last changed it and when, e.g. to report that 80% of the
+// uncovered lines were added in the last 30 days.
+type BlameAttribution struct {
+	Total BlameCount
+
+	// ByAuthor is keyed by author email, or name if the email is blank.
+	ByAuthor map[string]BlameCount
+
+	// ByCommit is keyed by commit hash. Uncommitted changes are attributed to the zero hash.
+	ByCommit map[string]BlameCount
+
+	// ByAge holds a bucket per age of the options, and a last bucket for older code.
+	ByAge []BlameAge
+}
+
+// Progress is a snapshot of a long running operation, such as parsing or exporting coverage.
+type Progress struct {
+	// Operation is OperationParse or OperationExport.
+	Operation string
+
+	// Files is the number of files processed so far.
+	Files int
+
+	// Bytes is the number of bytes read (when parsing) or written (when exporting) so far.
+	Bytes int64
+
+	// Elapsed is the time since the operation started.
+	Elapsed time.Duration
+
+	// Done is set on the last report of the operation.
+	Done bool
+}
+
 // Filter interface for filtering coverage by.
 type Filter interface {
 	FilterCoverage(Coverage) bool
@@ -27,5 +609,33 @@ type Coverage struct {
 	Host  string
 	Owner string
 	Repo  string
-	Path  string
+
+	// Path of the file relative to its module (or repository) root.
+	// When the module is known (see WithModuleRoot), it is the file location relative to the go.mod directory.
+	Path string
+
+	// ExcludedStatements is the number of statements dropped by `//coverage:ignore` directives.
+	ExcludedStatements int
+
+	// Module is the path of the module the file belongs to, when known (see WithModulePaths, WithGoMod,
+	// WithModuleRoot and WithGoWork).
+	Module string
+
+	// LocalPath is the location of the source file on disk, when the directory of its module is known
+	// (see WithGoMod, WithModuleRoot and WithGoWork).
+	LocalPath string
+
+	// Mode the profile was collected in: ModeSet, ModeCount or ModeAtomic. Counts above one are only
+	// meaningful in count and atomic modes.
+	Mode string
+
+	// Classified is set once the coverage was classified (see ClassifyCoverage and WithClassification). Generated
+	// and Exists are only meaningful when it is set.
+	Classified bool
+
+	// Generated is set for cgo shims and generated files, when classified.
+	Generated bool
+
+	// Exists is set when the source file was found, when classified.
+	Exists bool
 }
@@ -0,0 +1,306 @@
+package gocovparser
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gopkgInHost = "gopkg.in"
+
+	// number of path segments in host/file.go coverage file names.
+	hostAndFileParts = 2
+
+	// number of path segments in a host/owner/repo module path.
+	modulePathParts = 3
+)
+
+var gopkgInRepoRegex = regexp.MustCompile(`\.v\d+$`)
+
+// fileLocation is a coverage file name split in its module and path parts.
+type fileLocation struct {
+	host  string
+	owner string
+	repo  string
+	path  string
+
+	// module is the known module path the file belongs to, if any.
+	module string
+}
+
+// ParseOption configures how coverage data is parsed.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	modules     []string
+	goModFiles  []string
+	goWorkFiles []string
+	moduleRoots []string
+
+	// moduleDirs maps module paths to their directory, when read from a go.mod or go.work file.
+	moduleDirs  map[string]string
+	ignoreRoot  string
+	concurrency int
+	lenient     bool
+
+	lenientPaths    bool
+	normalize       bool
+	caseInsensitive bool
+
+	cache         Cache
+	remapper      *PathRemapper
+	mergeStrategy MergeStrategy
+
+	classify     bool
+	classifyRoot string
+
+	logger   Logger
+	progress ProgressFunc
+	tracker  *progressTracker
+
+	telemetry Telemetry
+	ctx       context.Context
+}
+
+// WithModulePaths declares the module paths the coverage files belong to, so file names are split
+// at the module boundary instead of guessing a host/owner/repo layout.
+func WithModulePaths(modules ...string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.modules = append(opts.modules, modules...)
+	}
+}
+
+// WithGoMod reads the module path from the go.mod file at goModPath, as WithModulePaths does.
+func WithGoMod(goModPath string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.goModFiles = append(opts.goModFiles, goModPath)
+	}
+}
+
+// WithModuleRoot discovers the go.mod file in dir or its closest parent and reads its module path, as WithGoMod does.
+// Coverage paths are then relative to the module root, so joining them with the module root gives their on-disk location.
+func WithModuleRoot(dir string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.moduleRoots = append(opts.moduleRoots, dir)
+	}
+}
+
+// WithLenientPaths accepts file names that can't be split in host, owner, repo and path parts (e.g. absolute paths
+// or `_/` paths of packages outside a module), keeping the whole file name as Path with empty Host, Owner and Repo.
+func WithLenientPaths() ParseOption {
+	return func(opts *parseOptions) {
+		opts.lenientPaths = true
+	}
+}
+
+func newParseOptions(opts []ParseOption) (parseOptions, error) {
+	options := parseOptions{moduleDirs: map[string]string{}, ctx: context.Background()}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for _, dir := range options.moduleRoots {
+		goModPath, err := FindGoMod(dir)
+		if err != nil {
+			return parseOptions{}, err
+		}
+
+		options.goModFiles = append(options.goModFiles, goModPath)
+	}
+
+	for _, goModPath := range options.goModFiles {
+		module, err := ReadModulePath(goModPath)
+		if err != nil {
+			return parseOptions{}, err
+		}
+
+		options.modules = append(options.modules, module)
+		options.moduleDirs[module] = filepath.Dir(goModPath)
+	}
+
+	for _, goWorkPath := range options.goWorkFiles {
+		modules, err := ReadGoWork(goWorkPath)
+		if err != nil {
+			return parseOptions{}, err
+		}
+
+		for _, module := range modules {
+			options.modules = append(options.modules, module.Path)
+			options.moduleDirs[module.Path] = module.Dir
+		}
+	}
+
+	// longest module paths first so nested modules win over their parents
+	sort.SliceStable(options.modules, func(i, j int) bool {
+		return len(options.modules[i]) > len(options.modules[j])
+	})
+
+	return options, nil
+}
+
+// FindGoMod returns the path of the go.mod file in dir or in its closest parent directory.
+func FindGoMod(dir string) (string, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve directory %q", dir)
+	}
+
+	for {
+		goModPath := filepath.Join(current, "go.mod")
+
+		info, err := os.Stat(goModPath)
+		if err == nil && !info.IsDir() {
+			return goModPath, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", errors.Wrapf(ErrGoModNotFound, "searched from %q", dir)
+		}
+
+		current = parent
+	}
+}
+
+// ReadModulePath returns the module path declared in the go.mod file at goModPath.
+func ReadModulePath(goModPath string) (string, error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open go.mod file %q", goModPath)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if index := strings.Index(line, "//"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+
+		module := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if unquoted, err := strconv.Unquote(module); err == nil {
+			module = unquoted
+		}
+
+		if module != "" {
+			return module, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", errors.Wrapf(err, "failed to read go.mod file %q", goModPath)
+	}
+
+	return "", errors.Errorf("no module directive found in %q", goModPath)
+}
+
+// splitFileName splits a coverage file name in its host, owner, repo and path parts.
+// File names under one of the known modules are split at the module boundary, with Path relative to the module root.
+// File names starting with a domain follow the host/owner/repo/path layout of code forges, while file names
+// without a domain (standard library or local modules) only have a Path.
+func splitFileName(fileName string, modules []string) (fileLocation, bool) {
+	if !isValidFileName(fileName) {
+		return fileLocation{}, false
+	}
+
+	for _, module := range modules {
+		if strings.HasPrefix(fileName, module+"/") {
+			location := splitModulePath(module)
+			location.path = strings.TrimPrefix(fileName, module+"/")
+			location.module = module
+
+			return location, true
+		}
+	}
+
+	parts := strings.Split(fileName, "/")
+
+	switch {
+	case len(parts) == 1 || !strings.Contains(parts[0], "."):
+		return fileLocation{path: fileName}, true
+	case len(parts) == hostAndFileParts:
+		return fileLocation{host: parts[0], path: parts[1]}, true
+	case parts[0] == gopkgInHost:
+		return splitGopkgIn(parts), true
+	}
+
+	match := parseLineRegex.FindStringSubmatch(fileName)
+	if len(match) == 0 {
+		return fileLocation{}, false
+	}
+
+	return fileLocation{
+		host:  match[hostPosition],
+		owner: match[ownerPosition],
+		repo:  match[repoPosition],
+		path:  match[pathPosition],
+	}, true
+}
+
+// splitModulePath splits a module path (e.g. github.com/owner/repo/v2) in its host, owner and repo parts.
+func splitModulePath(module string) fileLocation {
+	if strings.HasPrefix(module, gopkgInHost+"/") {
+		return splitGopkgIn(strings.Split(module, "/"))
+	}
+
+	parts := strings.SplitN(module, "/", modulePathParts)
+	location := fileLocation{host: parts[0]}
+
+	if len(parts) > 1 {
+		location.owner = parts[1]
+	}
+
+	if len(parts) > 2 {
+		location.repo = parts[2]
+	}
+
+	return location
+}
+
+// splitGopkgIn splits gopkg.in/pkg.vN/... and gopkg.in/owner/pkg.vN/... paths.
+func splitGopkgIn(parts []string) fileLocation {
+	if gopkgInRepoRegex.MatchString(parts[1]) {
+		return fileLocation{host: parts[0], repo: parts[1], path: path.Join(parts[2:]...)}
+	}
+
+	if len(parts) > 2 && gopkgInRepoRegex.MatchString(parts[2]) {
+		return fileLocation{host: parts[0], owner: parts[1], repo: parts[2], path: path.Join(parts[3:]...)}
+	}
+
+	return fileLocation{host: parts[0], owner: parts[1], path: path.Join(parts[2:]...)}
+}
+
+// isValidFileName returns whether the file name looks like an import path based coverage file name.
+func isValidFileName(fileName string) bool {
+	if fileName == "" || strings.ContainsAny(fileName, `\:`) {
+		return false
+	}
+
+	if path.Clean(fileName) != fileName || path.IsAbs(fileName) {
+		return false
+	}
+
+	first := strings.Split(fileName, "/")[0]
+
+	return first != "." && first != ".." && first != "_"
+}
+
+// fullPath returns the import path of the file from its location parts.
+func (l fileLocation) fullPath() string {
+	return path.Join(l.host, l.owner, l.repo, l.path)
+}
@@ -0,0 +1,183 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanParseModulePathLayouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		opts     []gocovparser.ParseOption
+		expected gocovparser.Coverage
+	}{
+		{
+			name:     "forge path",
+			fileName: "github.com/heynemann/go-cov-parser/gocovparser/core.go",
+			expected: gocovparser.Coverage{Host: "github.com", Owner: "heynemann", Repo: "go-cov-parser", Path: "gocovparser/core.go"},
+		},
+		{
+			name:     "stdlib-style path",
+			fileName: "fmt/print.go",
+			expected: gocovparser.Coverage{Path: "fmt/print.go"},
+		},
+		{
+			name:     "local module path",
+			fileName: "mymodule/pkg/foo.go",
+			expected: gocovparser.Coverage{Path: "mymodule/pkg/foo.go"},
+		},
+		{
+			name:     "domain root file",
+			fileName: "example.com/x.go",
+			expected: gocovparser.Coverage{Host: "example.com", Path: "x.go"},
+		},
+		{
+			name:     "gopkg.in path",
+			fileName: "gopkg.in/yaml.v3/decode.go",
+			expected: gocovparser.Coverage{Host: "gopkg.in", Repo: "yaml.v3", Path: "decode.go"},
+		},
+		{
+			name:     "gopkg.in path with owner",
+			fileName: "gopkg.in/check/foo.v1/sub/check.go",
+			expected: gocovparser.Coverage{Host: "gopkg.in", Owner: "check", Repo: "foo.v1", Path: "sub/check.go"},
+		},
+		{
+			name:     "vanity module with deep path",
+			fileName: "k8s.io/apimachinery/pkg/util/wait/wait.go",
+			opts:     []gocovparser.ParseOption{gocovparser.WithModulePaths("k8s.io/apimachinery")},
+			expected: gocovparser.Coverage{Host: "k8s.io", Owner: "apimachinery", Path: "pkg/util/wait/wait.go", Module: "k8s.io/apimachinery"},
+		},
+		{
+			name:     "major version module",
+			fileName: "github.com/owner/repo/v2/pkg/a.go",
+			opts:     []gocovparser.ParseOption{gocovparser.WithModulePaths("github.com/owner/repo/v2")},
+			expected: gocovparser.Coverage{Host: "github.com", Owner: "owner", Repo: "repo/v2", Path: "pkg/a.go", Module: "github.com/owner/repo/v2"},
+		},
+		{
+			name:     "nested module wins over parent module",
+			fileName: "example.com/x/tools/a.go",
+			opts:     []gocovparser.ParseOption{gocovparser.WithModulePaths("example.com/x", "example.com/x/tools")},
+			expected: gocovparser.Coverage{Host: "example.com", Owner: "x", Repo: "tools", Path: "a.go", Module: "example.com/x/tools"},
+		},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			// ACT
+			got, err := gocovparser.Parse("mode: set\n"+testcase.fileName+":1.1,2.2 1 1\n", testcase.opts...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+
+			testcase.expected.FileName = testcase.fileName
+			testcase.expected.Blocks = got[0].Blocks
+			testcase.expected.Mode = gocovparser.ModeSet
+			assert.Equal(t, testcase.expected, got[0])
+		})
+	}
+}
+
+func TestParseFailsForInvalidFileNames(t *testing.T) {
+	for _, fileName := range []string{"/abs/path/a.go", "./rel/a.go", "_/local/a.go", `C:\src\a.go`, "a//b.go"} {
+		t.Run(fileName, func(t *testing.T) {
+			// ACT
+			_, err := gocovparser.Parse("mode: set\n" + fileName + ":1.1,2.2 1 1\n")
+
+			// ASSERT
+			require.Error(t, err)
+			assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+		})
+	}
+}
+
+func TestCanParseInvalidFileNamesWithLenientPaths(t *testing.T) {
+	for _, fileName := range []string{"/abs/path/a.go", "./rel/a.go", "_/local/a.go", `C:\src\a.go`, "a//b.go"} {
+		t.Run(fileName, func(t *testing.T) {
+			// ACT
+			got, err := gocovparser.Parse("mode: set\n"+fileName+":1.1,2.2 1 1\n", gocovparser.WithLenientPaths())
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, gocovparser.CanonicalPath(fileName), got[0].FileName)
+			assert.Equal(t, gocovparser.CanonicalPath(fileName), got[0].Path)
+			assert.Empty(t, got[0].Host)
+			assert.Empty(t, got[0].Owner)
+			assert.Empty(t, got[0].Repo)
+		})
+	}
+}
+
+func TestCanParseUsingGoMod(t *testing.T) {
+	goMod := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(goMod, []byte("// comment\nmodule \"go.example.dev/deep/path/mod\" // trailing\n\ngo 1.19\n"), 0o600))
+
+	// ACT
+	got, err := gocovparser.Parse(
+		"mode: set\ngo.example.dev/deep/path/mod/pkg/a.go:1.1,2.2 1 1\n",
+		gocovparser.WithGoMod(goMod),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "go.example.dev", got[0].Host)
+	assert.Equal(t, "deep", got[0].Owner)
+	assert.Equal(t, "path/mod", got[0].Repo)
+	assert.Equal(t, "pkg/a.go", got[0].Path)
+}
+
+func TestReadModulePathFailures(t *testing.T) {
+	noModule := filepath.Join(t.TempDir(), "go.mod")
+	require.NoError(t, os.WriteFile(noModule, []byte("go 1.19\n"), 0o600))
+
+	_, err := gocovparser.ReadModulePath(noModule)
+	require.Error(t, err)
+
+	_, err = gocovparser.Parse(CoverageFixture(t), gocovparser.WithGoMod(filepath.Join(t.TempDir(), "missing")))
+	require.Error(t, err)
+}
+
+func TestCanParseUsingDiscoveredModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "pkg", "deep")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.dev/mod\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, "a.go"), []byte("package deep\n"), 0o600))
+
+	// ACT
+	got, err := gocovparser.Parse(
+		"mode: set\nexample.dev/mod/pkg/deep/a.go:1.1,1.10 1 1\n",
+		gocovparser.WithModuleRoot(nested),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "pkg/deep/a.go", got[0].Path)
+	assert.FileExists(t, filepath.Join(root, filepath.FromSlash(got[0].Path)))
+}
+
+func TestFindGoMod(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	_, err := gocovparser.FindGoMod(nested)
+	assert.ErrorIs(t, err, gocovparser.ErrGoModNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "go.mod"), []byte("module a\n"), 0o600))
+
+	got, err := gocovparser.FindGoMod(nested)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "a", "go.mod"), got)
+}
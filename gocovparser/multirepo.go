@@ -0,0 +1,121 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// RepositoryParseGroupName is the name of the parse group of MultiRepoReport.ParseGroup.
+const RepositoryParseGroupName = "repository"
+
+// MultiRepoReport aggregates the coverage of several repositories (e.g. the coverage.out artifacts of many
+// services) labeled by repository, to report per repository and organization wide.
+type MultiRepoReport struct {
+	repos map[string][]Coverage
+	files map[string]string
+}
+
+// NewMultiRepoReport returns an empty report. Use Add to add the coverage of each repository.
+func NewMultiRepoReport() *MultiRepoReport {
+	return &MultiRepoReport{
+		repos: map[string][]Coverage{},
+		files: map[string]string{},
+	}
+}
+
+// Add adds the coverage of the repository labeled label. Coverage added several times under the same label (e.g.
+// unit and integration profiles) is merged. Files already added under another label fail with ErrDuplicateRepoFile.
+func (r *MultiRepoReport) Add(label string, items []Coverage) error {
+	for _, cov := range items {
+		if repo, found := r.files[cov.FileName]; found && repo != label {
+			return errors.Wrapf(ErrDuplicateRepoFile, "%q of %q was added with %q", cov.FileName, label, repo)
+		}
+	}
+
+	merged, err := MergeCoverage(r.repos[label], items)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add coverage of %q", label)
+	}
+
+	for _, cov := range merged {
+		r.files[cov.FileName] = label
+	}
+
+	r.repos[label] = merged
+
+	return nil
+}
+
+// Labels returns the labels of the repositories, sorted.
+func (r *MultiRepoReport) Labels() []string {
+	labels := make([]string, 0, len(r.repos))
+	for label := range r.repos {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
+
+// Repo returns the coverage of the repository labeled label.
+func (r *MultiRepoReport) Repo(label string) []Coverage {
+	return r.repos[label]
+}
+
+// Coverage returns the coverage of every repository, sorted by file name.
+func (r *MultiRepoReport) Coverage() []Coverage {
+	items := make([]Coverage, 0, len(r.files))
+	for _, label := range r.Labels() {
+		items = append(items, r.repos[label]...)
+	}
+
+	return SortCoverage(items)
+}
+
+// ParseGroup returns a parse group keyed by the repository labels, named RepositoryParseGroupName, so the coverage
+// of the report can be grouped and rendered by repository.
+func (r *MultiRepoReport) ParseGroup() ParseGroup {
+	return NewParseGroup(RepositoryParseGroupName, func(cov Coverage) string {
+		return r.files[cov.FileName]
+	})
+}
+
+// Breakdowns returns the coverage breakdown of each repository, by label.
+func (r *MultiRepoReport) Breakdowns(opts ...PercentOption) map[string]OverallCoverageBreakdown {
+	result := make(map[string]OverallCoverageBreakdown, len(r.repos))
+	for label, items := range r.repos {
+		result[label] = GetTotalCoverageBreakdown(items, opts...)
+	}
+
+	return result
+}
+
+// Total returns the organization wide coverage breakdown, of every repository.
+func (r *MultiRepoReport) Total(opts ...PercentOption) OverallCoverageBreakdown {
+	return GetTotalCoverageBreakdown(r.Coverage(), opts...)
+}
+
+// GroupCoverage groups the coverage of every repository by repository and in the groups, e.g. to compare the
+// packages of every service.
+func (r *MultiRepoReport) GroupCoverage(groups ...ParseGroup) (ParseGroupResult, error) {
+	return GroupCoverage(r.Coverage(), append([]ParseGroup{r.ParseGroup()}, groups...)...)
+}
+
+// Results bundles the coverage, its grouping by repository and in the groups, and the organization wide breakdown,
+// e.g. to write them as JSON.
+func (r *MultiRepoReport) Results(groups ...ParseGroup) (Results, error) {
+	grouped, err := r.GroupCoverage(groups...)
+	if err != nil {
+		return Results{}, err
+	}
+
+	total := r.Total()
+
+	return Results{
+		Coverage:  r.Coverage(),
+		Groups:    grouped,
+		Breakdown: &total,
+	}, nil
+}
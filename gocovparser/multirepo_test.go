@@ -0,0 +1,93 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multiRepoFixture(t *testing.T) *gocovparser.MultiRepoReport {
+	t.Helper()
+
+	billing, err := gocovparser.Parse("mode: set\ngithub.com/org/billing/pkg/a.go:1.1,2.2 3 1\n")
+	require.NoError(t, err)
+
+	billingIntegration, err := gocovparser.Parse("mode: set\ngithub.com/org/billing/pkg/b.go:1.1,2.2 1 0\n")
+	require.NoError(t, err)
+
+	search, err := gocovparser.Parse(
+		"mode: set\ngithub.com/org/search/a.go:1.1,2.2 2 0\ngithub.com/org/search/a.go:3.1,4.2 2 1\n",
+	)
+	require.NoError(t, err)
+
+	repos := gocovparser.NewMultiRepoReport()
+	require.NoError(t, repos.Add("billing", billing))
+	require.NoError(t, repos.Add("billing", billingIntegration))
+	require.NoError(t, repos.Add("search", search))
+
+	return repos
+}
+
+func TestMultiRepoReportBreakdowns(t *testing.T) {
+	repos := multiRepoFixture(t)
+
+	// ACT
+	breakdowns := repos.Breakdowns()
+	total := repos.Total()
+
+	// ASSERT
+	assert.Equal(t, []string{"billing", "search"}, repos.Labels())
+	assert.Len(t, repos.Repo("billing"), 2)
+	assert.Equal(t, 4, breakdowns["billing"].Statements)
+	assert.Equal(t, 3, breakdowns["billing"].CoveredStatements)
+	assert.Equal(t, 4, breakdowns["search"].Statements)
+	assert.Equal(t, 2, breakdowns["search"].CoveredStatements)
+	assert.Equal(t, 8, total.Statements)
+	assert.Equal(t, 5, total.CoveredStatements)
+	assert.Equal(t, 3, total.Files)
+}
+
+func TestMultiRepoReportGroupsByRepository(t *testing.T) {
+	repos := multiRepoFixture(t)
+
+	// ACT
+	got, err := repos.GroupCoverage(gocovparser.ByPackage())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.75, got[gocovparser.RepositoryParseGroupName]["billing"])
+	assert.EqualValues(t, 0.5, got[gocovparser.RepositoryParseGroupName]["search"])
+	assert.EqualValues(t, 0.75, got["package"]["github.com/org/billing/pkg"])
+}
+
+func TestMultiRepoReportResultsAsJSON(t *testing.T) {
+	repos := multiRepoFixture(t)
+
+	results, err := repos.Results()
+	require.NoError(t, err)
+
+	// ACT
+	data, err := json.Marshal(results)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"repository":{"billing":0.75,"search":0.5}`)
+	assert.Contains(t, string(data), `"coveredStatements":5`)
+}
+
+func TestMultiRepoReportRejectsFileOfAnotherRepository(t *testing.T) {
+	repos := multiRepoFixture(t)
+	items, err := gocovparser.Parse("mode: set\ngithub.com/org/search/a.go:1.1,2.2 2 1\n")
+	require.NoError(t, err)
+
+	// ACT
+	err = repos.Add("billing", items)
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrDuplicateRepoFile)
+}
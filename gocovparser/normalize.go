@@ -0,0 +1,254 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// columnsPerLine weighs lines against columns when measuring the length of a block.
+const columnsPerLine = 1 << 16
+
+// WithBlockNormalization normalizes the blocks of every file with NormalizeBlocks, so profiles with duplicate
+// or overlapping blocks don't count the same statements twice.
+func WithBlockNormalization() ParseOption {
+	return func(opts *parseOptions) {
+		opts.normalize = true
+	}
+}
+
+// NormalizeBlocks returns the blocks sorted by position, without duplicates and without overlaps.
+//
+// Blocks at the same position are combined as MergeCoverage does (counts are summed, or combined with a logical or
+// in set mode); if their statements differ, the highest is kept. Overlapping blocks are split at each other's
+// boundaries. The statements of a block are distributed between its parts proportionally to their length, and each
+// part keeps the highest statements and count of the blocks covering it. Parts without statements are dropped.
+// Blocks without length (ending where, or before, they start) can't be split and are kept as they are.
+func NormalizeBlocks(blocks []cover.ProfileBlock, mode string) []cover.ProfileBlock {
+	return normalizeBlocks(blocks, mode, MergeDefault)
+}
+
+// normalizeBlocks normalizes blocks as NormalizeBlocks does, combining blocks at the same position with strategy.
+func normalizeBlocks(blocks []cover.ProfileBlock, mode string, strategy MergeStrategy) []cover.ProfileBlock {
+	blocks = dedupeBlocks(blocks, strategy.blocks(mode))
+	if !overlapping(blocks) {
+		return blocks
+	}
+
+	blocks, empty := splitEmptyBlocks(blocks)
+
+	result := splitOverlaps(blocks)
+	if len(empty) == 0 {
+		return result
+	}
+
+	result = append(result, empty...)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return blockLess(result[i], result[j])
+	})
+
+	return result
+}
+
+// splitEmptyBlocks separates the blocks ending where, or before, they start from the others.
+func splitEmptyBlocks(blocks []cover.ProfileBlock) (spanning, empty []cover.ProfileBlock) {
+	spanning = make([]cover.ProfileBlock, 0, len(blocks))
+
+	for _, b := range blocks {
+		if (position{line: b.StartLine, col: b.StartCol}).before(position{line: b.EndLine, col: b.EndCol}) {
+			spanning = append(spanning, b)
+		} else {
+			empty = append(empty, b)
+		}
+	}
+
+	return spanning, empty
+}
+
+// splitOverlaps splits the sorted blocks, which must all end after they start, at each other's boundaries.
+func splitOverlaps(blocks []cover.ProfileBlock) []cover.ProfileBlock {
+	if !overlapping(blocks) {
+		return blocks
+	}
+
+	boundaries := blockBoundaries(blocks)
+	parts := make([]cover.ProfileBlock, len(boundaries)-1)
+
+	for index := range parts {
+		parts[index] = cover.ProfileBlock{
+			StartLine: boundaries[index].line,
+			StartCol:  boundaries[index].col,
+			EndLine:   boundaries[index+1].line,
+			EndCol:    boundaries[index+1].col,
+		}
+	}
+
+	for _, b := range blocks {
+		first := sort.Search(len(boundaries), func(i int) bool {
+			return !boundaries[i].before(position{line: b.StartLine, col: b.StartCol})
+		})
+		last := sort.Search(len(boundaries), func(i int) bool {
+			return !boundaries[i].before(position{line: b.EndLine, col: b.EndCol})
+		})
+
+		for offset, statements := range splitStatements(b.NumStmt, boundaries[first:last+1]) {
+			part := &parts[first+offset]
+
+			if statements > part.NumStmt {
+				part.NumStmt = statements
+			}
+
+			if b.Count > part.Count {
+				part.Count = b.Count
+			}
+		}
+	}
+
+	result := make([]cover.ProfileBlock, 0, len(parts))
+
+	for _, part := range parts {
+		if part.NumStmt > 0 {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
+
+// dedupeBlocks sorts blocks by position and combines the blocks at the same position with strategy.
+func dedupeBlocks(blocks []cover.ProfileBlock, strategy MergeStrategy) []cover.ProfileBlock {
+	sorted := make([]cover.ProfileBlock, len(blocks))
+	copy(sorted, blocks)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return blockLess(sorted[i], sorted[j])
+	})
+
+	result := []cover.ProfileBlock{}
+
+	for _, b := range sorted {
+		b.Count = strategy.combine(0, b.Count)
+
+		if len(result) == 0 || !samePosition(result[len(result)-1], b) {
+			result = append(result, b)
+
+			continue
+		}
+
+		last := &result[len(result)-1]
+
+		if b.NumStmt > last.NumStmt {
+			last.NumStmt = b.NumStmt
+		}
+
+		last.Count = strategy.combine(last.Count, b.Count)
+	}
+
+	return result
+}
+
+// overlapping returns whether any of the sorted blocks starts before the end of the previous one.
+func overlapping(blocks []cover.ProfileBlock) bool {
+	for index := 1; index < len(blocks); index++ {
+		previous, current := blocks[index-1], blocks[index]
+
+		if (position{line: current.StartLine, col: current.StartCol}).before(
+			position{line: previous.EndLine, col: previous.EndCol},
+		) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blockBoundaries returns the sorted, unique start and end positions of the blocks.
+func blockBoundaries(blocks []cover.ProfileBlock) []position {
+	boundaries := make([]position, 0, 2*len(blocks))
+
+	for _, b := range blocks {
+		boundaries = append(boundaries,
+			position{line: b.StartLine, col: b.StartCol},
+			position{line: b.EndLine, col: b.EndCol},
+		)
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].before(boundaries[j])
+	})
+
+	result := boundaries[:0]
+
+	for _, boundary := range boundaries {
+		if len(result) == 0 || result[len(result)-1] != boundary {
+			result = append(result, boundary)
+		}
+	}
+
+	return result
+}
+
+// splitStatements distributes statements between the parts delimited by boundaries, proportionally to their
+// length, using the largest remainder method so the parts add up to statements.
+func splitStatements(statements int, boundaries []position) []int {
+	parts := len(boundaries) - 1
+	shares := make([]int, parts)
+	remainders := make([]int, parts)
+	lengths := make([]int, parts)
+	total := 0
+
+	if parts == 0 || statements <= 0 {
+		return shares
+	}
+
+	for index := range lengths {
+		// columns past columnsPerLine make offsets out of order, such lengths are ignored
+		if length := boundaries[index+1].offset() - boundaries[index].offset(); length > 0 {
+			lengths[index] = length
+			total += length
+		}
+	}
+
+	if total == 0 {
+		shares[0] = statements
+
+		return shares
+	}
+
+	assigned := 0
+
+	for index, length := range lengths {
+		shares[index] = statements * length / total
+		remainders[index] = statements * length % total
+		assigned += shares[index]
+	}
+
+	// positions too far apart overflow the lengths: the statements are then kept in the first part
+	if missing := statements - assigned; missing < 0 || missing > parts {
+		shares = make([]int, parts)
+		shares[0] = statements
+
+		return shares
+	}
+
+	order := make([]int, parts)
+	for index := range order {
+		order[index] = index
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+
+	for _, index := range order[:statements-assigned] {
+		shares[index]++
+	}
+
+	return shares
+}
+
+// offset returns a linear position, for measuring the length between positions.
+func (p position) offset() int {
+	return p.line*columnsPerLine + p.col
+}
@@ -0,0 +1,134 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func block(startLine, endLine, statements, count int) cover.ProfileBlock {
+	return cover.ProfileBlock{
+		StartLine: startLine, StartCol: 1, EndLine: endLine, EndCol: 1, NumStmt: statements, Count: count,
+	}
+}
+
+func TestNormalizeBlocks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mode     string
+		blocks   []cover.ProfileBlock
+		expected []cover.ProfileBlock
+	}{
+		{
+			name:     "sorts disjoint blocks",
+			mode:     gocovparser.ModeCount,
+			blocks:   []cover.ProfileBlock{block(5, 6, 1, 0), block(1, 3, 2, 1)},
+			expected: []cover.ProfileBlock{block(1, 3, 2, 1), block(5, 6, 1, 0)},
+		},
+		{
+			name:     "combines duplicates",
+			mode:     gocovparser.ModeCount,
+			blocks:   []cover.ProfileBlock{block(1, 3, 2, 1), block(1, 3, 2, 4)},
+			expected: []cover.ProfileBlock{block(1, 3, 2, 5)},
+		},
+		{
+			name:     "combines duplicates in set mode",
+			mode:     gocovparser.ModeSet,
+			blocks:   []cover.ProfileBlock{block(1, 3, 2, 1), block(1, 3, 2, 1)},
+			expected: []cover.ProfileBlock{block(1, 3, 2, 1)},
+		},
+		{
+			name:     "splits a block containing finer blocks",
+			mode:     gocovparser.ModeSet,
+			blocks:   []cover.ProfileBlock{block(1, 11, 5, 1), block(1, 5, 2, 0), block(5, 11, 3, 1)},
+			expected: []cover.ProfileBlock{block(1, 5, 2, 1), block(5, 11, 3, 1)},
+		},
+		{
+			name:     "splits partially overlapping blocks",
+			mode:     gocovparser.ModeCount,
+			blocks:   []cover.ProfileBlock{block(1, 7, 3, 2), block(4, 10, 3, 0)},
+			expected: []cover.ProfileBlock{block(1, 4, 2, 2), block(4, 7, 2, 2), block(7, 10, 1, 0)},
+		},
+		{
+			name:   "keeps empty blocks inside overlapping blocks",
+			mode:   gocovparser.ModeSet,
+			blocks: []cover.ProfileBlock{block(1, 7, 3, 1), block(4, 10, 3, 0), block(3, 3, 1, 1), block(2, 2, 0, 0)},
+			expected: []cover.ProfileBlock{
+				block(1, 4, 2, 1), block(2, 2, 0, 0), block(3, 3, 1, 1), block(4, 7, 2, 1), block(7, 10, 1, 0),
+			},
+		},
+		{
+			name:     "keeps inverted blocks inside overlapping blocks",
+			mode:     gocovparser.ModeSet,
+			blocks:   []cover.ProfileBlock{block(1, 7, 3, 1), block(4, 10, 3, 0), block(6, 2, 1, 1)},
+			expected: []cover.ProfileBlock{block(1, 4, 2, 1), block(4, 7, 2, 1), block(6, 2, 1, 1), block(7, 10, 1, 0)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// ACT
+			got := gocovparser.NormalizeBlocks(testCase.blocks, testCase.mode)
+
+			// ASSERT
+			assert.Equal(t, testCase.expected, got)
+		})
+	}
+}
+
+func TestParseWithBlockNormalization(t *testing.T) {
+	data := `mode: set
+github.com/owner/repo/pkg/a.go:1.1,11.1 5 1
+github.com/owner/repo/pkg/a.go:1.1,5.1 2 0
+github.com/owner/repo/pkg/a.go:5.1,11.1 3 1
+`
+
+	raw, err := gocovparser.Parse(data)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.Parse(data, gocovparser.WithBlockNormalization())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, 10, gocovparser.GetTotalCoverageBreakdown(raw).Statements)
+	assert.Equal(t, 5, gocovparser.GetTotalCoverageBreakdown(got).Statements)
+	assert.Equal(t, 5, gocovparser.GetTotalCoverageBreakdown(got).CoveredStatements)
+}
+
+func TestParseWithBlockNormalizationOfEmptyBlocks(t *testing.T) {
+	data := `mode: set
+github.com/owner/repo/pkg/a.go:1.1,5.1 2 1
+github.com/owner/repo/pkg/a.go:2.1,2.1 0 0
+github.com/owner/repo/pkg/a.go:3.1,3.1 1 1
+github.com/owner/repo/pkg/a.go:4.1,9.1 2 0
+`
+
+	// ACT
+	got, err := gocovparser.Parse(data, gocovparser.WithBlockNormalization())
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []cover.ProfileBlock{
+		block(1, 4, 2, 1), block(2, 2, 0, 0), block(3, 3, 1, 1), block(5, 9, 2, 0),
+	}, got[0].Blocks)
+}
+
+func TestNormalizeBlocksKeepsFixtureBlocks(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	for _, cov := range items {
+		// ACT
+		got := gocovparser.NormalizeBlocks(cov.Blocks, cov.Mode)
+
+		// ASSERT
+		assert.Equal(t, cov.Blocks, got, cov.FileName)
+	}
+}
@@ -0,0 +1,54 @@
+package gocovparser
+
+import "sort"
+
+// SortCoverage returns a copy of the items sorted by file name, so outputs built from them don't depend on the
+// order the items were parsed, merged or filtered in.
+func SortCoverage(items []Coverage) []Coverage {
+	sorted := make([]Coverage, len(items))
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].FileName < sorted[j].FileName
+	})
+
+	return sorted
+}
+
+// Groups returns the sorted group names of the result.
+func (r ParseGroupResult) Groups() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Keys returns the sorted keys of the group, or nil if the group is not in the result.
+func (r ParseGroupResult) Keys(group string) []string {
+	values, found := r[group]
+	if !found {
+		return nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Walk calls fn with the coverage of every key of every group, sorted by group name and then by key.
+func (r ParseGroupResult) Walk(fn func(group, key string, coverage float64)) {
+	for _, group := range r.Groups() {
+		for _, key := range r.Keys(group) {
+			fn(group, key, r[group][key])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reversed(items []gocovparser.Coverage) []gocovparser.Coverage {
+	result := make([]gocovparser.Coverage, 0, len(items))
+	for index := len(items) - 1; index >= 0; index-- {
+		result = append(result, items[index])
+	}
+
+	return result
+}
+
+func TestParseIgnoresLineOrder(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(CoverageFixture7(t)), "\n")
+	shuffled := []string{lines[0]}
+
+	for index := len(lines) - 1; index > 0; index-- {
+		shuffled = append(shuffled, lines[index])
+	}
+
+	expected, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.Parse(strings.Join(shuffled, "\n"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestSortCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	input := reversed(items)
+
+	// ACT
+	got := gocovparser.SortCoverage(input)
+
+	// ASSERT
+	assert.Equal(t, items, got)
+	assert.Equal(t, reversed(items), input)
+}
+
+func TestParseGroupResultOrdering(t *testing.T) {
+	result := gocovparser.ParseGroupResult{
+		"total":   {"total": 0.5},
+		"package": {"b": 0.2, "a": 0.1, "c": 0.3},
+	}
+
+	visited := []string{}
+
+	// ACT
+	result.Walk(func(group, key string, coverage float64) {
+		visited = append(visited, group+"/"+key)
+	})
+
+	// ASSERT
+	assert.Equal(t, []string{"package", "total"}, result.Groups())
+	assert.Equal(t, []string{"a", "b", "c"}, result.Keys("package"))
+	assert.Nil(t, result.Keys("missing"))
+	assert.Equal(t, []string{"package/a", "package/b", "package/c", "total/total"}, visited)
+}
+
+func TestResultsJSONIgnoresItemOrder(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(gocovparser.Results{Coverage: items})
+	require.NoError(t, err)
+
+	// ACT
+	got, err := json.Marshal(gocovparser.Results{Coverage: reversed(items)})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(got))
+}
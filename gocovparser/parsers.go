@@ -1,7 +1,11 @@
 package gocovparser
 
 import (
+	"fmt"
+	"path"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // FileParseGroup returns each line as it's own key.
@@ -16,9 +20,12 @@ var FileParseGroup = ParseGroup{
 var PackageParseGroup = ParseGroup{
 	Name: "package",
 	KeyFunc: func(filename string) string {
-		parts := strings.Split(filename, "/")
+		index := strings.LastIndexByte(filename, '/')
+		if index < 0 {
+			return ""
+		}
 
-		return strings.Join(parts[:len(parts)-1], "/")
+		return filename[:index]
 	},
 }
 
@@ -29,3 +36,113 @@ var TotalParseGroup = ParseGroup{
 		return "total"
 	},
 }
+
+// NewParseGroup returns a parse group keyed by keyFn, which receives the whole coverage item.
+func NewParseGroup(name string, keyFn func(Coverage) string) ParseGroup {
+	return ParseGroup{Name: name, CoverageKeyFunc: keyFn}
+}
+
+// Key returns the grouping key of the coverage item, using CoverageKeyFunc if set or KeyFunc otherwise.
+func (g ParseGroup) Key(cov Coverage) string {
+	if g.CoverageKeyFunc != nil {
+		return g.CoverageKeyFunc(cov)
+	}
+
+	return g.KeyFunc(cov.FileName)
+}
+
+// ByPackage returns a parse group keyed by the package import path.
+func ByPackage() ParseGroup {
+	return PackageParseGroup
+}
+
+// ByFile returns a parse group keyed by the file name.
+func ByFile() ParseGroup {
+	return FileParseGroup
+}
+
+// ByRepo returns a parse group keyed by host/owner/repo, or host/owner for module paths without a repo segment.
+// File names without a host (local modules) are keyed by their first path segment.
+func ByRepo() ParseGroup {
+	return ParseGroup{
+		Name: "repo",
+		KeyFunc: func(filename string) string {
+			location, ok := splitFileName(filename, nil)
+			if !ok {
+				return filename
+			}
+
+			if location.host == "" {
+				return strings.Split(filename, "/")[0]
+			}
+
+			return path.Join(location.host, location.owner, location.repo)
+		},
+	}
+}
+
+// ByOwner returns a parse group keyed by host/owner.
+// File names without a host (local modules) are keyed by their first path segment.
+func ByOwner() ParseGroup {
+	return ParseGroup{
+		Name: "owner",
+		KeyFunc: func(filename string) string {
+			location, ok := splitFileName(filename, nil)
+			if !ok {
+				return filename
+			}
+
+			if location.host == "" {
+				return strings.Split(filename, "/")[0]
+			}
+
+			return path.Join(location.host, location.owner)
+		},
+	}
+}
+
+// ByDirectory returns a parse group named "directory-<depth>" keyed by the first depth segments of the package path.
+// Packages with fewer segments than depth are keyed by their full path. A depth lower than 1 is treated as 1.
+func ByDirectory(depth int) ParseGroup {
+	if depth < 1 {
+		depth = 1
+	}
+
+	return ParseGroup{
+		Name: fmt.Sprintf("directory-%d", depth),
+		KeyFunc: func(filename string) string {
+			parts := strings.Split(path.Dir(filename), "/")
+			if len(parts) > depth {
+				parts = parts[:depth]
+			}
+
+			return strings.Join(parts, "/")
+		},
+	}
+}
+
+// ParseGroupByName returns the parse group called name: package, file, repo, owner, directory, codeowner,
+// classification or total. Depth is used by directory groups and codeowners is the CODEOWNERS file of codeowner
+// groups. Unknown names fail with ErrUnknownParseGroup.
+func ParseGroupByName(name string, depth int, codeowners string) (ParseGroup, error) {
+	switch name {
+	case "package":
+		return ByPackage(), nil
+	case "file":
+		return ByFile(), nil
+	case "repo":
+		return ByRepo(), nil
+	case "owner":
+		return ByOwner(), nil
+	case "directory":
+		return ByDirectory(depth), nil
+	case "codeowner":
+		return ByCodeOwner(codeowners)
+	case ClassificationParseGroupName:
+		return ClassificationParseGroup, nil
+	case "total":
+		return TotalParseGroup, nil
+	default:
+		return ParseGroup{}, errors.Wrapf(ErrUnknownParseGroup, "unknown group %q", name)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -173,3 +174,114 @@ func TestTotalParser4(t *testing.T) {
 	coverage := got["total"]["total"]
 	require.Equal(t, float64(195), math.Round(coverage*10000.0))
 }
+
+func TestGroupConstructorKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    gocovparser.ParseGroup
+		filename string
+		expected string
+	}{
+		{"package", gocovparser.ByPackage(), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann/go-cov-parser/gocovparser"},
+		{"file", gocovparser.ByFile(), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann/go-cov-parser/gocovparser/core.go"},
+		{"repo", gocovparser.ByRepo(), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann/go-cov-parser"},
+		{"repo", gocovparser.ByRepo(), "go.uber.org/zap/writer.go", "go.uber.org/zap"},
+		{"repo", gocovparser.ByRepo(), "main.go", "main.go"},
+		{"owner", gocovparser.ByOwner(), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann"},
+		{"owner", gocovparser.ByOwner(), "go.uber.org/zap/writer.go", "go.uber.org/zap"},
+		{"directory-2", gocovparser.ByDirectory(2), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann"},
+		{"directory-4", gocovparser.ByDirectory(4), "github.com/heynemann/go-cov-parser/gocovparser/core.go", "github.com/heynemann/go-cov-parser/gocovparser"},
+		{"directory-10", gocovparser.ByDirectory(10), "go.uber.org/zap/writer.go", "go.uber.org/zap"},
+		{"directory-1", gocovparser.ByDirectory(0), "go.uber.org/zap/writer.go", "go.uber.org"},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.name+" "+testcase.filename, func(t *testing.T) {
+			// ACT
+			got := testcase.group.KeyFunc(testcase.filename)
+
+			// ASSERT
+			require.Equal(t, testcase.name, testcase.group.Name)
+			require.Equal(t, testcase.expected, got)
+		})
+	}
+}
+
+func TestParseGroupByName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"package":        "package",
+		"file":           "file",
+		"repo":           "repo",
+		"owner":          "owner",
+		"directory":      "directory-2",
+		"classification": "classification",
+		"total":          "total",
+	} {
+		// ACT
+		got, err := gocovparser.ParseGroupByName(name, 2, "")
+
+		// ASSERT
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, got.Name, name)
+	}
+}
+
+func TestParseGroupByNameFailsForUnknownNames(t *testing.T) {
+	// ACT
+	_, err := gocovparser.ParseGroupByName("team", 1, "")
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrUnknownParseGroup)
+	assert.EqualError(t, err, `unknown group "team": unknown parse group`)
+}
+
+func TestRepoParser(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture2(t))
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverage(items, gocovparser.ByRepo(), gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got["repo"], 1)
+	require.Equal(t, got["total"]["total"], got["repo"]["github.cbhq.net/engineering/mongofle"])
+}
+
+func TestNewParseGroupReceivesCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture7(t))
+	require.NoError(t, err)
+
+	byBlockCount := gocovparser.NewParseGroup("blocks", func(cov gocovparser.Coverage) string {
+		if len(cov.Blocks) > 10 {
+			return "large"
+		}
+
+		return "small"
+	})
+
+	// ACT
+	got, err := gocovparser.GroupCoverage(items, byBlockCount)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got["blocks"], 2)
+	require.Contains(t, got["blocks"], "large")
+	require.Contains(t, got["blocks"], "small")
+}
+
+func TestParseGroupKeyPrefersCoverageKeyFunc(t *testing.T) {
+	cov := gocovparser.Coverage{FileName: "github.com/owner/repo/pkg/a.go", Repo: "repo"}
+	group := gocovparser.ParseGroup{
+		Name:            "repo",
+		KeyFunc:         func(string) string { return "file name" },
+		CoverageKeyFunc: func(cov gocovparser.Coverage) string { return cov.Repo },
+	}
+
+	// ACT
+	got := group.Key(cov)
+
+	// ASSERT
+	require.Equal(t, "repo", got)
+	require.Equal(t, "github.com/owner/repo/pkg", gocovparser.ByPackage().Key(cov))
+}
@@ -0,0 +1,47 @@
+package gocovparser
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CanonicalPath converts the backslashes of file names written on Windows (e.g. `pkg\core.go`) to slashes, as in
+// import paths. Parse canonicalizes file names, so grouping keys and exported paths use slashes.
+func CanonicalPath(fileName string) string {
+	return strings.ReplaceAll(fileName, `\`, "/")
+}
+
+// SourcePath returns the location of the source file of the coverage on disk: its LocalPath if set (e.g. for the
+// modules of a go.work workspace), or root joined with its Path otherwise.
+func SourcePath(cov Coverage, root string) string {
+	if cov.LocalPath != "" {
+		return cov.LocalPath
+	}
+
+	return filepath.Join(root, filepath.FromSlash(cov.Path))
+}
+
+// WithCaseInsensitivePaths merges file names that only differ in case (e.g. `Pkg/core.go` and `pkg/core.go` from
+// a case-insensitive file system), keeping the spelling read first.
+func WithCaseInsensitivePaths() ParseOption {
+	return func(opts *parseOptions) {
+		opts.caseInsensitive = true
+	}
+}
+
+// CaseInsensitiveGroup returns the group with its keys folded to lower case, so keys only differing in case (e.g.
+// packages of coverage collected on Windows and Linux) are grouped together.
+func CaseInsensitiveGroup(group ParseGroup) ParseGroup {
+	return NewParseGroup(group.Name, func(cov Coverage) string {
+		return strings.ToLower(group.Key(cov))
+	})
+}
+
+// pathKey returns the key file names are merged by.
+func (o parseOptions) pathKey(fileName string) string {
+	if o.caseInsensitive {
+		return strings.ToLower(fileName)
+	}
+
+	return fileName
+}
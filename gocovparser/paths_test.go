@@ -0,0 +1,72 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCanonicalizesSeparators(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse(`mode: count
+github.com\heynemann\repo\pkg\core.go:1.1,2.2 1 1
+github.com/heynemann/repo/pkg\core.go:1.1,2.2 1 2
+github.com/heynemann/repo/pkg/core.go:3.1,4.2 1 0
+`)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "github.com/heynemann/repo/pkg/core.go", got[0].FileName)
+	assert.Equal(t, "repo", got[0].Repo)
+	assert.Equal(t, "pkg/core.go", got[0].Path)
+	require.Len(t, got[0].Blocks, 2)
+	assert.Equal(t, 3, got[0].Blocks[0].Count)
+}
+
+func TestCanParseWithCaseInsensitivePaths(t *testing.T) {
+	data := "mode: set\ngithub.com/a/b/Pkg/core.go:1.1,2.2 1 1\ngithub.com/a/b/pkg/core.go:3.1,4.2 1 0\n"
+
+	// ACT
+	sensitive, err := gocovparser.Parse(data)
+	require.NoError(t, err)
+
+	insensitive, err := gocovparser.Parse(data, gocovparser.WithCaseInsensitivePaths())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, sensitive, 2)
+	require.Len(t, insensitive, 1)
+	assert.Equal(t, "github.com/a/b/Pkg/core.go", insensitive[0].FileName)
+	assert.Len(t, insensitive[0].Blocks, 2)
+}
+
+func TestCaseInsensitiveGroup(t *testing.T) {
+	items, err := gocovparser.Parse("mode: set\ngithub.com/a/b/Pkg/a.go:1.1,2.2 1 1\ngithub.com/a/b/pkg/b.go:1.1,2.2 1 0\n")
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GroupCoverage(items, gocovparser.CaseInsensitiveGroup(gocovparser.ByPackage()))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"github.com/a/b/pkg": 0.5}, got["package"])
+}
+
+func TestSourcePath(t *testing.T) {
+	cov := gocovparser.Coverage{Path: "pkg/core.go"}
+	local := gocovparser.Coverage{Path: "pkg/core.go", LocalPath: filepath.Join("work", "api", "pkg", "core.go")}
+
+	// ACT
+	got := gocovparser.SourcePath(cov, "root")
+	gotLocal := gocovparser.SourcePath(local, "root")
+
+	// ASSERT
+	assert.Equal(t, filepath.Join("root", "pkg", "core.go"), got)
+	assert.Equal(t, filepath.Join("work", "api", "pkg", "core.go"), gotLocal)
+}
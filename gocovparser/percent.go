@@ -0,0 +1,111 @@
+package gocovparser
+
+import "math"
+
+// tolerance added before rounding so binary representation errors (e.g. 79.95 stored as 79.94999...)
+// don't move a value to the lower step.
+const roundingTolerance = 1e-9
+
+// percentScale is the multiplier of percentages reported from 0 to 100.
+const percentScale = 100
+
+// RoundingMode is how percentages are rounded to their precision.
+type RoundingMode int
+
+const (
+	// RoundNone keeps percentages unrounded. This is the default.
+	RoundNone RoundingMode = iota
+
+	// RoundNearest rounds half away from zero.
+	RoundNearest
+
+	// RoundFloor rounds down, so a gate of 80% fails at 79.99%.
+	RoundFloor
+
+	// RoundCeil rounds up.
+	RoundCeil
+)
+
+// PercentOption configures how percentages are computed by breakdown and grouping functions.
+type PercentOption func(*percentOptions)
+
+type percentOptions struct {
+	mode      RoundingMode
+	precision int
+	scaled    bool
+}
+
+// WithRounding rounds percentages to precision decimal places using mode.
+// The precision applies to the reported scale (see WithPercentScale).
+func WithRounding(mode RoundingMode, precision int) PercentOption {
+	return func(opts *percentOptions) {
+		opts.mode = mode
+		opts.precision = precision
+	}
+}
+
+// WithPercentScale reports percentages from 0 to 100 instead of the default ratio from 0 to 1.
+func WithPercentScale() PercentOption {
+	return func(opts *percentOptions) {
+		opts.scaled = true
+	}
+}
+
+// FormatPercent applies the options to a ratio from 0 to 1, e.g. to format percentages computed elsewhere consistently.
+func FormatPercent(value float64, opts ...PercentOption) float64 {
+	return newPercentOptions(opts).format(value)
+}
+
+// Format returns a copy of the result with the options applied to every percentage.
+func (r ParseGroupResult) Format(opts ...PercentOption) ParseGroupResult {
+	options := newPercentOptions(opts)
+	result := make(ParseGroupResult, len(r))
+
+	for name, keys := range r {
+		result[name] = make(map[string]float64, len(keys))
+
+		for key, value := range keys {
+			result[name][key] = options.format(value)
+		}
+	}
+
+	return result
+}
+
+func newPercentOptions(opts []PercentOption) percentOptions {
+	options := percentOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func (o percentOptions) percentOf(covered, total int) float64 {
+	return o.format(percentOf(covered, total))
+}
+
+func (o percentOptions) format(value float64) float64 {
+	if o.scaled {
+		value *= percentScale
+	}
+
+	if o.mode == RoundNone {
+		return value
+	}
+
+	factor := math.Pow10(o.precision)
+	scaled := value * factor
+
+	switch o.mode {
+	case RoundFloor:
+		scaled = math.Floor(scaled + roundingTolerance)
+	case RoundCeil:
+		scaled = math.Ceil(scaled - roundingTolerance)
+	default:
+		scaled = math.Round(scaled + math.Copysign(roundingTolerance, scaled))
+	}
+
+	return scaled / factor
+}
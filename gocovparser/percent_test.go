@@ -0,0 +1,72 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanFormatPercent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		opts     []gocovparser.PercentOption
+		expected float64
+	}{
+		{"unrounded", 0.79951, nil, 0.79951},
+		{"scaled", 0.5, []gocovparser.PercentOption{gocovparser.WithPercentScale()}, 50},
+		{"nearest", 0.7995, []gocovparser.PercentOption{gocovparser.WithRounding(gocovparser.RoundNearest, 3)}, 0.8},
+		{"floor", 0.7995, []gocovparser.PercentOption{gocovparser.WithRounding(gocovparser.RoundFloor, 3)}, 0.799},
+		{"ceil", 0.7991, []gocovparser.PercentOption{gocovparser.WithRounding(gocovparser.RoundCeil, 3)}, 0.8},
+		{
+			"floor of an exact step",
+			0.7995,
+			[]gocovparser.PercentOption{gocovparser.WithPercentScale(), gocovparser.WithRounding(gocovparser.RoundFloor, 2)},
+			79.95,
+		},
+		{
+			"scaled nearest",
+			0.79951,
+			[]gocovparser.PercentOption{gocovparser.WithPercentScale(), gocovparser.WithRounding(gocovparser.RoundNearest, 1)},
+			80,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// ACT
+			got := gocovparser.FormatPercent(testCase.value, testCase.opts...)
+
+			// ASSERT
+			assert.InDelta(t, testCase.expected, got, 1e-12)
+		})
+	}
+}
+
+func TestBreakdownsApplyPercentOptions(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	opts := []gocovparser.PercentOption{
+		gocovparser.WithPercentScale(),
+		gocovparser.WithRounding(gocovparser.RoundFloor, 1),
+	}
+
+	// ACT
+	breakdown := gocovparser.GetTotalCoverageBreakdown(items, opts...)
+	files := gocovparser.GetFileBreakdowns(items, opts...)
+	byKey := gocovparser.GroupBy(items, func(gocovparser.Coverage) string { return "all" }, opts...)
+	grouped, err := gocovparser.GroupCoverage(items, gocovparser.TotalParseGroup)
+	require.NoError(t, err)
+
+	// ASSERT
+	assert.InDelta(t, 88.2, breakdown.Coverage, 1e-12)
+	assert.InDelta(t, 88.2, files[0].Coverage, 1e-12)
+	assert.InDelta(t, 88.2, byKey["all"].Percent, 1e-12)
+	assert.InDelta(t, 88.2, grouped.Format(opts...)["total"]["total"], 1e-12)
+	assert.InDelta(t, 88.2, items[0].Percent(opts...), 1e-12)
+}
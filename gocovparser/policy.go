@@ -0,0 +1,126 @@
+package gocovparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangedLinesGroupName is the group name used for changed lines coverage in policies.
+const ChangedLinesGroupName = "changed"
+
+// MinimumTotal returns a rule requiring the total coverage to be at least minimum.
+func MinimumTotal(minimum float64) PolicyRule {
+	return PolicyRule{Group: TotalParseGroup.Name, Key: "total", Minimum: minimum}
+}
+
+// MinimumChangedLines returns a rule requiring the coverage of changed lines to be at least minimum.
+// The result must include the group returned by DiffCoverageResult.Group under ChangedLinesGroupName.
+func MinimumChangedLines(minimum float64) PolicyRule {
+	return PolicyRule{Group: ChangedLinesGroupName, Key: "total", Minimum: minimum}
+}
+
+// Group returns the changed lines coverage per file name, plus the overall coverage under the "total" key.
+func (r DiffCoverageResult) Group() map[string]float64 {
+	group := make(map[string]float64, len(r.Files)+1)
+
+	for fileName, detail := range r.Files {
+		group[fileName] = detail.Percent
+	}
+
+	group["total"] = r.Total.Percent
+
+	return group
+}
+
+// CheckPolicy returns the violations of the policy in the grouped coverage result, sorted by group and key.
+// Rules for keys missing from the result are reported as violations with an empty Key.
+// The result has no statement counts, so the MaxUncovered and MinStatements predicates of the rules are only
+// evaluated by CheckPolicyDetailed.
+func CheckPolicy(result ParseGroupResult, policy Policy) []PolicyViolation {
+	detailed := make(DetailedGroupResult, len(result))
+
+	for group, keys := range result {
+		detailed[group] = make(map[string]GroupStats, len(keys))
+
+		for key, coverage := range keys {
+			detailed[group][key] = GroupStats{Percent: coverage}
+		}
+	}
+
+	return checkPolicy(detailed, policy, false)
+}
+
+// CheckPolicyDetailed returns the violations of the policy in the detailed grouped coverage result, as CheckPolicy
+// does, also evaluating the absolute predicates of the rules: keys with fewer statements than MinStatements are
+// ignored, and keys with more uncovered statements than MaxUncovered are violations.
+func CheckPolicyDetailed(result DetailedGroupResult, policy Policy) []PolicyViolation {
+	return checkPolicy(result, policy, true)
+}
+
+func checkPolicy(result DetailedGroupResult, policy Policy, absolute bool) []PolicyViolation {
+	violations := []PolicyViolation{}
+
+	for _, rule := range policy.Rules {
+		matched := false
+
+		for key, stats := range result[rule.Group] {
+			if !rule.matches(key) {
+				continue
+			}
+
+			matched = true
+
+			if absolute && stats.Statements < rule.MinStatements {
+				continue
+			}
+
+			if stats.Percent < rule.Minimum {
+				violations = append(violations, PolicyViolation{Rule: rule, Key: key, Coverage: stats.Percent})
+			}
+
+			uncovered := stats.Statements - stats.Covered
+			if absolute && rule.MaxUncovered > 0 && uncovered > rule.MaxUncovered {
+				violations = append(violations, PolicyViolation{
+					Rule:      rule,
+					Key:       key,
+					Coverage:  stats.Percent,
+					Uncovered: uncovered,
+				})
+			}
+		}
+
+		if !matched {
+			violations = append(violations, PolicyViolation{Rule: rule})
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Rule.Group != violations[j].Rule.Group {
+			return violations[i].Rule.Group < violations[j].Rule.Group
+		}
+
+		return violations[i].Key < violations[j].Key
+	})
+
+	return violations
+}
+
+func (r PolicyRule) matches(key string) bool {
+	return r.Key == "" || key == r.Key || strings.HasSuffix(key, "/"+r.Key)
+}
+
+// String describes the violation.
+func (v PolicyViolation) String() string {
+	if v.Key == "" {
+		return fmt.Sprintf("%s %q: no coverage found (minimum %.2f%%)", v.Rule.Group, v.Rule.Key, v.Rule.Minimum*100)
+	}
+
+	if v.Uncovered > 0 {
+		return fmt.Sprintf(
+			"%s %q: %d uncovered statements exceed maximum %d", v.Rule.Group, v.Key, v.Uncovered, v.Rule.MaxUncovered,
+		)
+	}
+
+	return fmt.Sprintf("%s %q: coverage %.2f%% is below minimum %.2f%%", v.Rule.Group, v.Key, v.Coverage*100, v.Rule.Minimum*100)
+}
@@ -0,0 +1,124 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanCheckPolicy(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture6(t))
+	require.NoError(t, err)
+
+	result, err := gocovparser.GroupCoverage(items, gocovparser.PackageParseGroup, gocovparser.TotalParseGroup)
+	require.NoError(t, err)
+
+	policy := gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{
+			gocovparser.MinimumTotal(0.9),
+			{Group: "package", Key: "internal/acl", Minimum: 0.95},
+			{Group: "package", Key: "internal/config", Minimum: 0.5},
+			{Group: "package", Key: "internal/unknown", Minimum: 0.5},
+		},
+	}
+
+	// ACT
+	got := gocovparser.CheckPolicy(result, policy)
+
+	// ASSERT
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "", got[0].Key)
+	assert.Equal(t, "internal/unknown", got[0].Rule.Key)
+	assert.Equal(t, `package "internal/unknown": no coverage found (minimum 50.00%)`, got[0].String())
+
+	assert.Equal(t, "github.cbhq.net/some_repo/internal/config", got[1].Key)
+	assert.EqualValues(t, 0.4294117647058823, got[1].Coverage)
+	assert.Equal(
+		t,
+		`package "github.cbhq.net/some_repo/internal/config": coverage 42.94% is below minimum 50.00%`,
+		got[1].String(),
+	)
+
+	assert.Equal(t, "total", got[2].Key)
+}
+
+func TestPolicyWithoutKeyAppliesToAllGroupKeys(t *testing.T) {
+	result := gocovparser.ParseGroupResult{
+		"package": {"a": 0.5, "b": 0.9, "c": 0.1},
+	}
+
+	// ACT
+	got := gocovparser.CheckPolicy(result, gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{{Group: "package", Minimum: 0.6}},
+	})
+
+	// ASSERT
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Key)
+	assert.Equal(t, "c", got[1].Key)
+}
+
+func TestCanCheckChangedLinesPolicy(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	diff := gocovparser.DiffCoverage(items, map[string][]gocovparser.LineRange{
+		"gocovparser/core.go": {{Start: 65, End: 87}},
+	})
+
+	result := gocovparser.ParseGroupResult{
+		gocovparser.ChangedLinesGroupName: diff.Group(),
+	}
+
+	// ACT
+	got := gocovparser.CheckPolicy(result, gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{gocovparser.MinimumChangedLines(0.85)},
+	})
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Equal(t, "total", got[0].Key)
+	assert.Less(t, got[0].Coverage, 0.85)
+}
+
+func TestCanCheckPolicyWithAbsolutePredicates(t *testing.T) {
+	result := gocovparser.DetailedGroupResult{
+		"package": {
+			"tiny":  {Statements: 4, Covered: 1, Percent: 0.25},
+			"large": {Statements: 200, Covered: 170, Percent: 0.85},
+			"huge":  {Statements: 1000, Covered: 900, Percent: 0.9},
+		},
+	}
+
+	// ACT
+	got := gocovparser.CheckPolicyDetailed(result, gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{
+			{Group: "package", Minimum: 0.8, MinStatements: 10},
+			{Group: "package", Key: "huge", MaxUncovered: 50},
+		},
+	})
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Equal(t, "huge", got[0].Key)
+	assert.Equal(t, 100, got[0].Uncovered)
+	assert.Equal(t, `package "huge": 100 uncovered statements exceed maximum 50`, got[0].String())
+}
+
+func TestCheckPolicyIgnoresAbsolutePredicates(t *testing.T) {
+	result := gocovparser.ParseGroupResult{"package": {"tiny": 0.25}}
+
+	// ACT
+	got := gocovparser.CheckPolicy(result, gocovparser.Policy{
+		Rules: []gocovparser.PolicyRule{{Group: "package", Minimum: 0.8, MinStatements: 10, MaxUncovered: 1}},
+	})
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Equal(t, 0, got[0].Uncovered)
+}
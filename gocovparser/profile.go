@@ -0,0 +1,227 @@
+package gocovparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+const (
+	modeLinePrefix = "mode: "
+
+	// byteOrderMark is written at the start of files by some Windows tools.
+	byteOrderMark = "\ufeff"
+
+	// maxLineLength bounds the length of a profile line, far above the length of any file name.
+	maxLineLength = 1 << 20
+
+	// maxNumberDigits keeps the numbers of block lines from overflowing an int.
+	maxNumberDigits = 18
+	decimalBase     = 10
+)
+
+// WithLenientParsing skips malformed lines and unparsable file names instead of failing, so coverage emitted by
+// mixed tooling can still be used. The valid coverage is returned along with a ParseErrors error listing the
+// skipped lines, so callers must check for ParseErrors before discarding the result.
+func WithLenientParsing() ParseOption {
+	return func(opts *parseOptions) {
+		opts.lenient = true
+	}
+}
+
+// blockField is a numeric field of a coverage block line and the separator preceding it.
+type blockField struct {
+	separator byte
+	value     *int
+}
+
+// readProfiles reads the coverage profiles in r, one per file name and sorted by it. File names are canonicalized
+// with CanonicalPath. Blank lines, byte order marks and carriage returns are ignored.
+//
+// Concatenated profiles (e.g. `cat */coverage.out`) repeat mode lines: each starts a segment, and the blocks of
+// every segment are merged in the mode of segmentsMode. Malformed lines, mode lines with an unknown mode and files
+// with inconsistent blocks fail unless lenient, in which case they are returned as skipped. Lines are read as bytes,
+// so only the names of new files are allocated.
+func readProfiles(r io.Reader, options parseOptions) ([]*cover.Profile, ParseErrors, error) {
+	lenient := options.lenient
+	files := make(map[string]*cover.Profile)
+
+	// names maps the file names as written in the profile to their profile, before canonicalization
+	names := make(map[string]*cover.Profile)
+	skipped := ParseErrors{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+	mode := ""
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(bytes.TrimPrefix(scanner.Bytes(), []byte(byteOrderMark)))
+		if len(line) == 0 {
+			continue
+		}
+
+		if bytes.HasPrefix(line, []byte(modeLinePrefix)) && len(line) > len(modeLinePrefix) {
+			lineMode := string(line[len(modeLinePrefix):])
+
+			switch {
+			case mode == "":
+				mode = lineMode
+			case lineMode != mode:
+				merged, ok := segmentsMode(mode, lineMode)
+				if ok {
+					mode = merged
+
+					break
+				}
+
+				inconsistent := errors.Wrapf(ErrInconsistentCoverageMode, "line %d: %q after %q", lineNumber, lineMode, mode)
+				if !lenient {
+					return nil, nil, inconsistent
+				}
+
+				skipped = append(skipped, inconsistent)
+			}
+
+			continue
+		}
+
+		name, block, ok := parseBlockLine(line)
+		if mode == "" || !ok {
+			malformed := ErrMalformedLine{Line: lineNumber, Content: string(line)}
+			if !lenient {
+				return nil, nil, malformed
+			}
+
+			skipped = append(skipped, malformed)
+
+			continue
+		}
+
+		profile, found := names[string(name)]
+		if !found {
+			fileName := CanonicalPath(string(name))
+			key := options.pathKey(fileName)
+
+			profile, found = files[key]
+			if !found {
+				profile = &cover.Profile{FileName: fileName, Mode: mode}
+				files[key] = profile
+			}
+
+			names[string(name)] = profile
+		}
+
+		profile.Blocks = append(profile.Blocks, block)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrapf(ErrInvalidCoverageData, err.Error())
+	}
+
+	sorted := make([]*cover.Profile, 0, len(files))
+	for _, profile := range files {
+		sorted = append(sorted, profile)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FileName < sorted[j].FileName
+	})
+
+	profiles := make([]*cover.Profile, 0, len(sorted))
+
+	for _, profile := range sorted {
+		blocks, err := mergeBlocks(profile.Blocks, mode, options.mergeStrategy)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse coverage for %q", profile.FileName)
+			if !lenient {
+				return nil, nil, err
+			}
+
+			skipped = append(skipped, err)
+
+			continue
+		}
+
+		profile.Mode = mode
+		profile.Blocks = blocks
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, skipped, nil
+}
+
+// segmentsMode returns the mode the segments of a concatenated profile in modes a and b are merged in: set if
+// either is, since counts can't be recovered from set segments, or else a, as count and atomic both count
+// executions. It returns false for unknown modes.
+func segmentsMode(a, b string) (string, bool) {
+	known := func(mode string) bool {
+		return mode == ModeSet || mode == ModeCount || mode == ModeAtomic
+	}
+
+	switch {
+	case !known(a) || !known(b):
+		return "", false
+	case a == ModeSet || b == ModeSet:
+		return ModeSet, true
+	default:
+		return a, true
+	}
+}
+
+// parseBlockLine parses a `name.go:line.column,line.column statements count` line, reading fields from the end
+// since file names may contain colons.
+func parseBlockLine(line []byte) ([]byte, cover.ProfileBlock, bool) {
+	block := cover.ProfileBlock{}
+	fields := [...]blockField{
+		{' ', &block.Count},
+		{' ', &block.NumStmt},
+		{'.', &block.EndCol},
+		{',', &block.EndLine},
+		{'.', &block.StartCol},
+		{':', &block.StartLine},
+	}
+	end := len(line)
+
+	for _, field := range fields {
+		start := bytes.LastIndexByte(line[:end], field.separator)
+		if start < 0 {
+			return nil, cover.ProfileBlock{}, false
+		}
+
+		value, ok := parseNumber(line[start+1 : end])
+		if !ok {
+			return nil, cover.ProfileBlock{}, false
+		}
+
+		*field.value = value
+		end = start
+	}
+
+	if end == 0 {
+		return nil, cover.ProfileBlock{}, false
+	}
+
+	return line[:end], block, true
+}
+
+// parseNumber parses the non negative decimal numbers of block lines, without allocating.
+func parseNumber(digits []byte) (int, bool) {
+	if len(digits) == 0 || len(digits) > maxNumberDigits {
+		return 0, false
+	}
+
+	value := 0
+
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return 0, false
+		}
+
+		value = value*decimalBase + int(digit-'0')
+	}
+
+	return value, true
+}
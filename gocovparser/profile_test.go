@@ -0,0 +1,234 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mixedCoverage = `mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:38.53,42.2 2 1
+warning: no packages being tested depend on matches for pattern ./...
+
+github.com/heynemann/go-cov-parser/gocovparser/core.go:45.60,47.20 2 0
+/abs/path/a.go:1.1,2.2 1 1
+`
+
+func TestParseFailsWithMalformedLine(t *testing.T) {
+	// ACT
+	_, err := gocovparser.Parse(mixedCoverage)
+
+	// ASSERT
+	require.Error(t, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+
+	malformed := gocovparser.ErrMalformedLine{}
+	require.True(t, errors.As(err, &malformed))
+	assert.Equal(t, 3, malformed.Line)
+	assert.Equal(t, "warning: no packages being tested depend on matches for pattern ./...", malformed.Content)
+}
+
+func TestParseFailsWithUnparsableFileName(t *testing.T) {
+	// ACT
+	_, err := gocovparser.Parse("mode: set\n/abs/path/a.go:1.1,2.2 1 1\n")
+
+	// ASSERT
+	assert.Equal(t, gocovparser.ErrUnparsableFileName{FileName: "/abs/path/a.go"}, err)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
+
+func TestParseFailsWithoutModeLine(t *testing.T) {
+	// ACT
+	_, err := gocovparser.Parse("github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1\n")
+
+	// ASSERT
+	assert.Equal(t, gocovparser.ErrMalformedLine{
+		Line:    1,
+		Content: "github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1",
+	}, err)
+}
+
+func TestCanParseLeniently(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse(mixedCoverage, gocovparser.WithLenientParsing())
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Len(t, got[0].Blocks, 2)
+
+	skipped := gocovparser.ParseErrors{}
+	require.True(t, errors.As(err, &skipped))
+	assert.Equal(t, gocovparser.ParseErrors{
+		gocovparser.ErrMalformedLine{Line: 3, Content: "warning: no packages being tested depend on matches for pattern ./..."},
+		gocovparser.ErrUnparsableFileName{FileName: "/abs/path/a.go"},
+	}, skipped)
+	assert.ErrorIs(t, err, gocovparser.ErrInvalidCoverageData)
+}
+
+func TestLenientParsingOfValidDataHasNoError(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse(CoverageFixture(t), gocovparser.WithLenientParsing())
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestCanParseFilesLeniently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mixed.out")
+	require.NoError(t, os.WriteFile(path, []byte(mixedCoverage), 0o600))
+
+	// ACT
+	got, err := gocovparser.ParseFiles([]string{path, path}, gocovparser.WithLenientParsing())
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+
+	skipped := gocovparser.ParseErrors{}
+	require.True(t, errors.As(err, &skipped))
+	assert.Len(t, skipped, 4)
+	assert.Contains(t, skipped[0].Error(), path)
+}
+
+func TestCanParseProfilesWithBOMAndCRLF(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse(
+		"\ufeffmode: set\r\ngithub.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1\r\n",
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "gocovparser/core.go", got[0].Path)
+}
+
+func TestCanParseConcatenatedProfiles(t *testing.T) {
+	// ACT
+	got, err := gocovparser.Parse(`mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 2
+`)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 3, got[0].Blocks[0].Count)
+}
+
+func TestCanParseConcatenatedProfilesWithDifferentModes(t *testing.T) {
+	// ACT
+	counted, countedErr := gocovparser.Parse(`mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 2
+mode: atomic
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 3
+`)
+	set, setErr := gocovparser.Parse(`mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 5
+github.com/heynemann/go-cov-parser/gocovparser/models.go:1.1,2.2 1 0
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1
+github.com/heynemann/go-cov-parser/gocovparser/models.go:1.1,2.2 1 1
+`)
+
+	// ASSERT
+	require.NoError(t, countedErr)
+	require.Len(t, counted, 1)
+	assert.Equal(t, gocovparser.ModeCount, counted[0].Mode)
+	assert.Equal(t, 5, counted[0].Blocks[0].Count)
+
+	require.NoError(t, setErr)
+	require.Len(t, set, 2)
+	assert.Equal(t, gocovparser.ModeSet, set[0].Mode)
+	assert.Equal(t, 1, set[0].Blocks[0].Count)
+	assert.Equal(t, gocovparser.ModeSet, set[1].Mode)
+	assert.Equal(t, 1, set[1].Blocks[0].Count)
+}
+
+func TestParseFailsWithUnknownModeLines(t *testing.T) {
+	profile := `mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1
+mode: sampled
+github.com/heynemann/go-cov-parser/gocovparser/core.go:3.1,4.2 1 0
+`
+
+	// ACT
+	_, err := gocovparser.Parse(profile)
+	got, lenientErr := gocovparser.Parse(profile, gocovparser.WithLenientParsing())
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrInconsistentCoverageMode)
+	assert.ErrorIs(t, lenientErr, gocovparser.ErrInconsistentCoverageMode)
+	require.Len(t, got, 1)
+	assert.Len(t, got[0].Blocks, 2)
+}
+
+func TestCanParseTruncatedProfilesLeniently(t *testing.T) {
+	profile := `mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:1.1,2.2 1 1
+github.com/heynemann/go-cov-parser/gocovparser/models.go:1.1,2.2 1 0
+github.com/heynemann/go-cov-parser/gocovparser/models.go:1.1,2.2 2 0
+github.com/heynemann/go-cov-parser/gocovparser/merge.go:1.1,2`
+
+	// ACT
+	got, err := gocovparser.Parse(profile, gocovparser.WithLenientParsing())
+
+	// ASSERT
+	require.Len(t, got, 1)
+	assert.Equal(t, "gocovparser/core.go", got[0].Path)
+
+	skipped := gocovparser.ParseErrors{}
+	require.True(t, errors.As(err, &skipped))
+	require.Len(t, skipped, 2)
+	assert.ErrorIs(t, skipped[0], gocovparser.ErrInvalidCoverageData)
+	assert.ErrorIs(t, skipped[1], gocovparser.ErrInconsistentCoverageBlocks)
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(mixedCoverage)
+	f.Add("\ufeffmode: set\r\nexample.com/a/b/c.go:1.1,2.2 1 1\r\n")
+	f.Add("mode: count\nexample.com/a/b/c.go:1.1,2.2 1 1\nmode: count\nexample.com/a/b/c.go:1.1,2.2 1 2\n")
+	f.Add("mode: set\nexample.com/a/b/c.go:1.1,2.2 1 1\nmode: atomic\nexample.com/a/b/c.go:1.1,2")
+	f.Add("mode: set\nc:\\a.go:1.1,2.2 1 1\nexample.com/a/b/c.go:3.1,2.2 1 1\n\x00\x00")
+	f.Add("mode: set\nexample.com/a/b/c.go:1.1,5.1 2 1\nexample.com/a/b/c.go:3.1,3.1 1 1\nexample.com/a/b/c.go:4.1,2.1 1 1\n")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		strict, strictErr := gocovparser.Parse(data)
+		lenient, lenientErr := gocovparser.Parse(data, gocovparser.WithLenientParsing())
+
+		if strictErr == nil {
+			require.NoError(t, lenientErr)
+			require.Equal(t, strict, lenient)
+		}
+
+		skipped := gocovparser.ParseErrors{}
+		if lenientErr != nil && !errors.As(lenientErr, &skipped) {
+			// only the input can fail lenient parsing, as a whole
+			require.ErrorIs(t, lenientErr, gocovparser.ErrInvalidCoverageData)
+		}
+
+		for _, cov := range lenient {
+			require.NotEmpty(t, cov.FileName)
+			require.NotEmpty(t, cov.Path)
+
+			for _, block := range cov.Blocks {
+				require.GreaterOrEqual(t, block.NumStmt, 0)
+				require.GreaterOrEqual(t, block.Count, 0)
+			}
+		}
+
+		normalized, normalizedErr := gocovparser.Parse(data, gocovparser.WithBlockNormalization())
+		if strictErr == nil {
+			require.NoError(t, normalizedErr)
+			require.Len(t, normalized, len(strict))
+		}
+	})
+}
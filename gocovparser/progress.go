@@ -0,0 +1,110 @@
+package gocovparser
+
+import (
+	"io"
+	"time"
+)
+
+// Operations reported in Progress.
+const (
+	OperationParse  = "parse"
+	OperationExport = "export"
+)
+
+// ProgressFunc receives the progress of long running operations, e.g. to draw a progress bar. It is called from
+// the goroutine running the operation, and should return quickly.
+type ProgressFunc func(Progress)
+
+// Logger receives log messages with their attributes as alternating keys and values. It is satisfied by
+// *slog.Logger of Go 1.21 and later.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+}
+
+// WithLogger logs parsing at debug level (files, bytes read, duration, skipped errors and cache hits) to logger.
+func WithLogger(logger Logger) ParseOption {
+	return func(opts *parseOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithProgress reports the bytes read and the files parsed to fn while parsing, and once more when done.
+func WithProgress(fn ProgressFunc) ParseOption {
+	return func(opts *parseOptions) {
+		opts.progress = fn
+	}
+}
+
+// debug logs to the logger of the options, if any.
+func (o parseOptions) debug(msg string, args ...any) {
+	if o.logger != nil {
+		o.logger.Debug(msg, args...)
+	}
+}
+
+// progressTracker counts the files and bytes of an operation and reports them.
+type progressTracker struct {
+	operation string
+	fn        ProgressFunc
+	start     time.Time
+	files     int
+	bytes     int64
+}
+
+// newProgressTracker starts tracking the operation, reporting its progress to fn if not nil.
+func newProgressTracker(operation string, fn ProgressFunc) *progressTracker {
+	return &progressTracker{operation: operation, fn: fn, start: time.Now()}
+}
+
+// reader counts the bytes read from r.
+func (t *progressTracker) reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, tracker: t}
+}
+
+func (t *progressTracker) read(n int) {
+	t.bytes += int64(n)
+	t.report(false)
+}
+
+func (t *progressTracker) file() {
+	t.files++
+	t.report(false)
+}
+
+func (t *progressTracker) done(files int) Progress {
+	t.files = files
+	t.report(true)
+
+	return t.snapshot(true)
+}
+
+func (t *progressTracker) report(done bool) {
+	if t.fn != nil {
+		t.fn(t.snapshot(done))
+	}
+}
+
+func (t *progressTracker) snapshot(done bool) Progress {
+	return Progress{
+		Operation: t.operation,
+		Files:     t.files,
+		Bytes:     t.bytes,
+		Elapsed:   time.Since(t.start),
+		Done:      done,
+	}
+}
+
+type progressReader struct {
+	r       io.Reader
+	tracker *progressTracker
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.tracker.read(n)
+	}
+
+	return n, err
+}
@@ -0,0 +1,77 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records each log as a `msg key=value ...` line.
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) {
+	l.record(msg, args)
+}
+
+func (l *recordingLogger) Info(msg string, args ...any) {
+	l.record(msg, args)
+}
+
+func (l *recordingLogger) record(msg string, args []any) {
+	line := msg
+	for i := 0; i+1 < len(args); i += 2 {
+		line += fmt.Sprintf(" %v=%v", args[i], args[i+1])
+	}
+
+	l.lines = append(l.lines, line)
+}
+
+func TestParseReportsProgress(t *testing.T) {
+	data := "mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 1\ngithub.com/a/b/d.go:1.1,2.2 1 0\n"
+	reports := []gocovparser.Progress{}
+
+	// ACT
+	_, err := gocovparser.Parse(data, gocovparser.WithProgress(func(progress gocovparser.Progress) {
+		reports = append(reports, progress)
+	}))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NotEmpty(t, reports)
+
+	last := reports[len(reports)-1]
+	assert.Equal(t, gocovparser.OperationParse, last.Operation)
+	assert.True(t, last.Done)
+	assert.Equal(t, 2, last.Files)
+	assert.EqualValues(t, len(data), last.Bytes)
+
+	for _, progress := range reports[:len(reports)-1] {
+		assert.False(t, progress.Done)
+		assert.LessOrEqual(t, progress.Files, 2)
+	}
+}
+
+func TestParseLogsAtDebugLevel(t *testing.T) {
+	logger := &recordingLogger{}
+	cache := gocovparser.NewMemoryCache()
+	data := "mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 1\n"
+
+	// ACT
+	for i := 0; i < 2; i++ {
+		_, err := gocovparser.Parse(data, gocovparser.WithLogger(logger), gocovparser.WithCache(cache))
+		require.NoError(t, err)
+	}
+
+	// ASSERT
+	logs := strings.Join(logger.lines, "\n")
+	assert.Contains(t, logs, "parsed coverage files=1 bytes=42")
+	assert.Contains(t, logs, "coverage cache hit")
+}
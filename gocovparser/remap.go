@@ -0,0 +1,187 @@
+package gocovparser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// PathRule rewrites a coverage file name, e.g. from the layout of a container to the layout of the host.
+type PathRule interface {
+	// RemapPath returns the rewritten file name, and whether the rule applies to the file name.
+	RemapPath(fileName string) (string, bool)
+}
+
+type prefixRule struct {
+	from string
+	to   string
+}
+
+var _ PathRule = (*prefixRule)(nil)
+
+// PrefixRule replaces the from prefix of file names with to, e.g. from "/go/src/" to "".
+func PrefixRule(from, to string) PathRule {
+	return &prefixRule{from: from, to: to}
+}
+
+func (r *prefixRule) RemapPath(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, r.from) {
+		return fileName, false
+	}
+
+	return r.to + strings.TrimPrefix(fileName, r.from), true
+}
+
+func (r *prefixRule) String() string {
+	return fmt.Sprintf("prefix %q => %q", r.from, r.to)
+}
+
+type regexRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+var _ PathRule = (*regexRule)(nil)
+
+// RegexRule replaces the matches of the pattern in file names with the replacement, which can refer to the groups
+// of the pattern as regexp.Regexp.ReplaceAllString does (e.g. `^/builds/[^/]+/(.*)$` with `example.com/org/$1`).
+// Like FileExcludeFilter, it panics if the pattern is not a valid regular expression.
+func RegexRule(pattern, replacement string) PathRule {
+	return &regexRule{pattern: regexp.MustCompile(pattern), replacement: replacement}
+}
+
+func (r *regexRule) RemapPath(fileName string) (string, bool) {
+	if !r.pattern.MatchString(fileName) {
+		return fileName, false
+	}
+
+	return r.pattern.ReplaceAllString(fileName, r.replacement), true
+}
+
+func (r *regexRule) String() string {
+	return fmt.Sprintf("regex %q => %q", r.pattern, r.replacement)
+}
+
+// PathRemapper rewrites coverage file names with the first of its rules applying to them.
+type PathRemapper struct {
+	rules []PathRule
+}
+
+// NewPathRemapper returns a remapper applying the first of the rules that applies to each file name.
+func NewPathRemapper(rules ...PathRule) *PathRemapper {
+	return &PathRemapper{rules: rules}
+}
+
+// Remap returns the file name rewritten by the first rule applying to it, or the file name when none applies.
+func (r *PathRemapper) Remap(fileName string) string {
+	for _, rule := range r.rules {
+		if remapped, ok := rule.RemapPath(fileName); ok {
+			return remapped
+		}
+	}
+
+	return fileName
+}
+
+// String describes the rules of the remapper.
+func (r *PathRemapper) String() string {
+	rules := make([]string, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, fmt.Sprint(rule))
+	}
+
+	return strings.Join(rules, "; ")
+}
+
+// WithPathRemapper rewrites the file names of the profile with the remapper before they are split in host, owner,
+// repo and path, so coverage collected in a container resolves to the files of the host checkout.
+// Blocks of file names remapped to the same file name are merged.
+func WithPathRemapper(remapper *PathRemapper) ParseOption {
+	return func(opts *parseOptions) {
+		opts.remapper = remapper
+	}
+}
+
+// RemapPaths rewrites the file names of parsed coverage with the remapper, as WithPathRemapper does while parsing,
+// splitting the rewritten file names again with the parse options (e.g. WithModuleRoot for the host checkout).
+func RemapPaths(items []Coverage, remapper *PathRemapper, opts ...ParseOption) ([]Coverage, error) {
+	options, err := newParseOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Coverage, 0, len(items))
+
+	for _, cov := range items {
+		fileName := remapper.Remap(cov.FileName)
+
+		location, ok := splitFileName(fileName, options.modules)
+		if !ok && options.lenientPaths {
+			location, ok = fileLocation{path: fileName}, true
+		}
+
+		if !ok {
+			return nil, ErrUnparsableFileName{FileName: fileName}
+		}
+
+		cov.FileName = fileName
+		cov.Host, cov.Owner, cov.Repo, cov.Path = location.host, location.owner, location.repo, location.path
+		cov.Module = location.module
+		cov.LocalPath = ""
+
+		if dir, found := options.moduleDirs[location.module]; found {
+			cov.LocalPath = filepath.Join(dir, filepath.FromSlash(location.path))
+		}
+
+		result = append(result, cov)
+	}
+
+	merged, err := MergeCoverageWithStrategy(options.mergeStrategy, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge remapped coverage")
+	}
+
+	return merged, nil
+}
+
+// remapProfiles rewrites the file names of the profiles, merging the blocks of profiles remapped to the same name.
+func remapProfiles(
+	profiles []*cover.Profile, remapper *PathRemapper, strategy MergeStrategy,
+) ([]*cover.Profile, error) {
+	files := make(map[string]*cover.Profile, len(profiles))
+
+	for _, profile := range profiles {
+		fileName := remapper.Remap(profile.FileName)
+
+		existing, found := files[fileName]
+		if !found {
+			profile.FileName = fileName
+			files[fileName] = profile
+
+			continue
+		}
+
+		blocks, err := mergeBlocks(append(existing.Blocks, profile.Blocks...), existing.Mode, strategy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge coverage remapped to %q", fileName)
+		}
+
+		existing.Blocks = blocks
+	}
+
+	result := make([]*cover.Profile, 0, len(files))
+	for _, profile := range files {
+		result = append(result, profile)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FileName < result[j].FileName
+	})
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathRemapperAppliesFirstMatchingRule(t *testing.T) {
+	remapper := gocovparser.NewPathRemapper(
+		gocovparser.PrefixRule("/go/src/", ""),
+		gocovparser.RegexRule(`^/builds/[^/]+/(.*)$`, "github.com/heynemann/$1"),
+		gocovparser.PrefixRule("/go/src/github.com/", "never/"),
+	)
+
+	// ACT & ASSERT
+	assert.Equal(t, "github.com/heynemann/a/b.go", remapper.Remap("/go/src/github.com/heynemann/a/b.go"))
+	assert.Equal(t, "github.com/heynemann/repo/c.go", remapper.Remap("/builds/job-42/repo/c.go"))
+	assert.Equal(t, "github.com/other/d.go", remapper.Remap("github.com/other/d.go"))
+}
+
+func TestCanParseWithPathRemapper(t *testing.T) {
+	remapper := gocovparser.NewPathRemapper(
+		gocovparser.PrefixRule("/app/", "github.com/heynemann/repo/"),
+		gocovparser.PrefixRule("/go/src/", ""),
+	)
+
+	// ACT
+	got, err := gocovparser.Parse(
+		"mode: count\n"+
+			"/app/a.go:1.1,2.2 1 1\n"+
+			"/go/src/github.com/heynemann/repo/a.go:1.1,2.2 1 2\n"+
+			"/go/src/github.com/heynemann/repo/a.go:3.1,4.2 2 0\n",
+		gocovparser.WithPathRemapper(remapper),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "github.com/heynemann/repo/a.go", got[0].FileName)
+	assert.Equal(t, "heynemann", got[0].Owner)
+	assert.Equal(t, "repo", got[0].Repo)
+	assert.Equal(t, "a.go", got[0].Path)
+	require.Len(t, got[0].Blocks, 2)
+	assert.Equal(t, 3, got[0].Blocks[0].Count)
+}
+
+func TestCacheKeyDependsOnPathRemapper(t *testing.T) {
+	cache := gocovparser.NewMemoryCache()
+	data := "mode: set\n/app/a.go:1.1,2.2 1 1\n"
+
+	// ACT
+	first, err := gocovparser.Parse(
+		data,
+		gocovparser.WithCache(cache),
+		gocovparser.WithPathRemapper(gocovparser.NewPathRemapper(gocovparser.PrefixRule("/app/", "github.com/a/b/"))),
+	)
+	require.NoError(t, err)
+
+	second, err := gocovparser.Parse(
+		data,
+		gocovparser.WithCache(cache),
+		gocovparser.WithPathRemapper(gocovparser.NewPathRemapper(gocovparser.PrefixRule("/app/", "github.com/c/d/"))),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/a/b/a.go", first[0].FileName)
+	assert.Equal(t, "github.com/c/d/a.go", second[0].FileName)
+}
+
+func TestRemapPaths(t *testing.T) {
+	items, err := gocovparser.Parse(
+		"mode: set\n/app/a.go:1.1,2.2 1 1\n/app/b.go:1.1,2.2 1 0\n",
+		gocovparser.WithLenientPaths(),
+	)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.RemapPaths(
+		items,
+		gocovparser.NewPathRemapper(gocovparser.RegexRule(`^/app/.*\.go$`, "example.dev/svc/main.go")),
+		gocovparser.WithModulePaths("example.dev/svc"),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "example.dev/svc/main.go", got[0].FileName)
+	assert.Equal(t, "example.dev/svc", got[0].Module)
+	assert.Equal(t, "main.go", got[0].Path)
+	assert.Len(t, got[0].Blocks, 1)
+	assert.Equal(t, 1, got[0].Blocks[0].Count)
+}
+
+func TestRemapPathsFailsOnUnparsableFileName(t *testing.T) {
+	items := []gocovparser.Coverage{{FileName: "github.com/a/b/c.go"}}
+
+	// ACT
+	_, err := gocovparser.RemapPaths(items, gocovparser.NewPathRemapper(gocovparser.PrefixRule("github.com/", "/")))
+
+	// ASSERT
+	assert.ErrorAs(t, err, &gocovparser.ErrUnparsableFileName{})
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"unicode/utf8"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const (
+	// approximate width in pixels of a character in 11px Verdana.
+	badgeCharWidth = 7
+
+	// horizontal padding in pixels around each side of a badge text.
+	badgePadding = 5
+
+	defaultGoodColor = "#4c1"
+	defaultWarnColor = "#dfb317"
+	defaultFailColor = "#e05d44"
+)
+
+// BadgeOptions configures the coverage badge.
+type BadgeOptions struct {
+	// Label shown on the left side of the badge. Defaults to "coverage".
+	Label string
+
+	// GoodThreshold and WarnThreshold are the minimum coverage (0 to 1) for the good and warning colors.
+	// Both default to the Markdown summary thresholds when zero.
+	GoodThreshold float64
+	WarnThreshold float64
+
+	// GoodColor, WarnColor and FailColor are the colors of the coverage side of the badge.
+	GoodColor string
+	WarnColor string
+	FailColor string
+}
+
+type badge struct {
+	Label        string
+	Message      string
+	Color        string
+	Width        int
+	LabelWidth   int
+	MessageWidth int
+	LabelX       int
+	MessageX     int
+}
+
+// GenerateBadge renders a shields.io style SVG badge with the total coverage of the breakdown.
+func GenerateBadge(breakdown gocovparser.OverallCoverageBreakdown, opts BadgeOptions) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/badge.svg.tmpl")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse badge template")
+	}
+
+	opts = opts.withDefaults()
+
+	b := badge{
+		Label:   opts.Label,
+		Message: percent(breakdown.Coverage),
+		Color:   opts.color(breakdown.Coverage),
+	}
+	b.LabelWidth = textWidth(b.Label)
+	b.MessageWidth = textWidth(b.Message)
+	b.Width = b.LabelWidth + b.MessageWidth
+	b.LabelX = b.LabelWidth / 2
+	b.MessageX = b.LabelWidth + b.MessageWidth/2
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, b); err != nil {
+		return nil, errors.Wrap(err, "failed to render badge")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (o BadgeOptions) withDefaults() BadgeOptions {
+	if o.Label == "" {
+		o.Label = "coverage"
+	}
+
+	if o.GoodThreshold == 0 && o.WarnThreshold == 0 {
+		o.GoodThreshold = defaultGoodThreshold
+		o.WarnThreshold = defaultWarnThreshold
+	}
+
+	if o.GoodColor == "" {
+		o.GoodColor = defaultGoodColor
+	}
+
+	if o.WarnColor == "" {
+		o.WarnColor = defaultWarnColor
+	}
+
+	if o.FailColor == "" {
+		o.FailColor = defaultFailColor
+	}
+
+	return o
+}
+
+func (o BadgeOptions) color(value float64) string {
+	switch {
+	case value >= o.GoodThreshold:
+		return o.GoodColor
+	case value >= o.WarnThreshold:
+		return o.WarnColor
+	default:
+		return o.FailColor
+	}
+}
+
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)*badgeCharWidth + 2*badgePadding
+}
@@ -0,0 +1,53 @@
+package report_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanGenerateBadge(t *testing.T) {
+	breakdown := gocovparser.OverallCoverageBreakdown{Coverage: 0.8823529411764706}
+
+	// ACT
+	got, err := report.GenerateBadge(breakdown, report.BadgeOptions{})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal(got, new(struct{})))
+
+	svg := string(got)
+	assert.Contains(t, svg, `aria-label="coverage: 88.2%"`)
+	assert.Contains(t, svg, `fill="#4c1"`)
+}
+
+func TestBadgeColorFollowsThresholds(t *testing.T) {
+	opts := report.BadgeOptions{
+		Label:         "tests & coverage",
+		GoodThreshold: 0.9,
+		WarnThreshold: 0.5,
+		WarnColor:     "orange",
+	}
+
+	testCases := map[float64]string{
+		0.95: `fill="#4c1"`,
+		0.6:  `fill="orange"`,
+		0.1:  `fill="#e05d44"`,
+	}
+
+	for coverage, expected := range testCases {
+		// ACT
+		got, err := report.GenerateBadge(gocovparser.OverallCoverageBreakdown{Coverage: coverage}, opts)
+
+		// ASSERT
+		require.NoError(t, err)
+		assert.Contains(t, string(got), expected)
+		assert.Contains(t, string(got), "tests &amp; coverage")
+	}
+}
@@ -0,0 +1,262 @@
+// Package report renders human readable coverage reports.
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// HTMLOptions configures the HTML report.
+type HTMLOptions struct {
+	// Title of the report page.
+	Title string
+
+	// SourceRoot is the directory the coverage Path of each file is relative to. Annotated source is only rendered
+	// for files found under it, or at their LocalPath (see gocovparser.SourcePath).
+	SourceRoot string
+
+	// Groups to show in the report navigation, e.g. per package or per team.
+	Groups []gocovparser.ParseGroup
+
+	// Templates are parsed after the default template, so their *.tmpl files can redefine its "style", "head",
+	// "header", "summary", "groups", "files", "sources" and "footer" blocks, or replace it entirely with a
+	// report.html.tmpl file.
+	Templates fs.FS
+
+	// Funcs are added to the functions available to the templates, besides percent.
+	Funcs template.FuncMap
+
+	// CSS is added after the default style, e.g. to brand the report. It is trusted and rendered as is.
+	CSS string
+
+	// Columns are added to the files table, e.g. with the owners or the open bugs of each file.
+	Columns []HTMLColumn
+}
+
+// HTMLColumn is an extra column of the files table of the HTML report.
+type HTMLColumn struct {
+	Header string
+
+	// Value returns the cell of the file, escaped when rendered.
+	Value func(cov gocovparser.Coverage) string
+}
+
+type htmlReport struct {
+	Title     string
+	CSS       template.CSS
+	Breakdown gocovparser.OverallCoverageBreakdown
+	Groups    []htmlGroup
+	Columns   []string
+	Files     []htmlFile
+}
+
+type htmlGroup struct {
+	Name string
+	Rows []htmlGroupRow
+}
+
+type htmlGroupRow struct {
+	Key      string
+	Coverage float64
+}
+
+type htmlFile struct {
+	FileName string
+	Anchor   string
+	Detail   gocovparser.GroupDetail
+	Columns  []string
+	Lines    []htmlLine
+}
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Class  string
+}
+
+// WriteHTML renders a single page report with a summary index, the configured groups and the annotated source of every file.
+func WriteHTML(w io.Writer, items []gocovparser.Coverage, opts HTMLOptions) error {
+	return WriteHTMLContext(context.Background(), w, items, opts)
+}
+
+// WriteHTMLContext renders the report as WriteHTML does, aborting once ctx is done.
+func WriteHTMLContext(ctx context.Context, w io.Writer, items []gocovparser.Coverage, opts HTMLOptions) error {
+	tmpl, err := parseHTMLTemplate(opts)
+	if err != nil {
+		return err
+	}
+
+	report, err := buildHTMLReport(ctx, items, opts)
+	if err != nil {
+		return err
+	}
+
+	err = tmpl.Execute(gocovparser.NewContextWriter(ctx, w), report)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return errors.Wrap(ctxErr, "html report aborted")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to render html report")
+	}
+
+	return nil
+}
+
+func parseHTMLTemplate(opts HTMLOptions) (*template.Template, error) {
+	funcs := template.FuncMap{"percent": percent}
+	for name, fn := range opts.Funcs {
+		funcs[name] = fn
+	}
+
+	tmpl, err := template.New("report.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.html.tmpl")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse report template")
+	}
+
+	if opts.Templates != nil {
+		tmpl, err = tmpl.ParseFS(opts.Templates, "*.tmpl")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse report template overrides")
+		}
+	}
+
+	return tmpl, nil
+}
+
+func buildHTMLReport(ctx context.Context, items []gocovparser.Coverage, opts HTMLOptions) (htmlReport, error) {
+	title := opts.Title
+	if title == "" {
+		title = "Coverage Report"
+	}
+
+	report := htmlReport{
+		Title:     title,
+		CSS:       template.CSS(opts.CSS),
+		Breakdown: gocovparser.GetTotalCoverageBreakdown(items),
+	}
+
+	for _, column := range opts.Columns {
+		report.Columns = append(report.Columns, column.Header)
+	}
+
+	grouped, err := gocovparser.GroupCoverage(items, opts.Groups...)
+	if err != nil {
+		return htmlReport{}, errors.Wrap(err, "failed to group coverage")
+	}
+
+	for _, group := range opts.Groups {
+		report.Groups = append(report.Groups, htmlGroup{
+			Name: group.Name,
+			Rows: sortedRows(grouped[group.Name]),
+		})
+	}
+
+	details := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return cov.FileName
+	})
+	lines := gocovparser.GetLineCoverage(items)
+
+	for index, cov := range gocovparser.SortCoverage(items) {
+		if err := ctx.Err(); err != nil {
+			return htmlReport{}, errors.Wrap(err, "html report aborted")
+		}
+
+		source, err := readSource(opts.SourceRoot, cov)
+		if err != nil {
+			return htmlReport{}, err
+		}
+
+		columns := make([]string, 0, len(opts.Columns))
+		for _, column := range opts.Columns {
+			columns = append(columns, column.Value(cov))
+		}
+
+		report.Files = append(report.Files, htmlFile{
+			FileName: cov.FileName,
+			Anchor:   fmt.Sprintf("file%d", index),
+			Detail:   details[cov.FileName],
+			Columns:  columns,
+			Lines:    annotate(source, lines[cov.FileName]),
+		})
+	}
+
+	return report, nil
+}
+
+func sortedRows(values map[string]float64) []htmlGroupRow {
+	rows := make([]htmlGroupRow, 0, len(values))
+	for key, value := range values {
+		rows = append(rows, htmlGroupRow{Key: key, Coverage: value})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Key < rows[j].Key
+	})
+
+	return rows
+}
+
+// readSource returns the lines of the source file, or nil if neither a source root nor the LocalPath of the file is
+// known or the file does not exist.
+func readSource(root string, cov gocovparser.Coverage) ([]string, error) {
+	if root == "" && cov.LocalPath == "" {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(gocovparser.SourcePath(cov, root))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read source of %q", cov.FileName)
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, nil
+}
+
+func annotate(source []string, coverage gocovparser.FileLineCoverage) []htmlLine {
+	lines := make([]htmlLine, 0, len(source))
+
+	for index, text := range source {
+		line := htmlLine{
+			Number: index + 1,
+			Text:   text,
+		}
+
+		if cov, found := coverage[line.Number]; found {
+			line.Class = cov.Status.String()
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func percent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value*100)
+}
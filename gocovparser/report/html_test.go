@@ -0,0 +1,149 @@
+package report_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reportFixture = `
+mode: set
+github.com/heynemann/go-cov-parser/pkg/thing.go:3.14,5.2 1 1
+github.com/heynemann/go-cov-parser/pkg/thing.go:7.14,9.2 1 0
+github.com/heynemann/go-cov-parser/other/missing.go:1.1,2.2 1 1
+`
+
+const reportSource = `package pkg
+
+func A() {
+	println("<a>")
+}
+
+func B() {
+	println("b")
+}
+`
+
+func TestCanWriteHTMLReport(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "thing.go"), []byte(reportSource), 0o600))
+
+	items, err := gocovparser.Parse(reportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = report.WriteHTML(&buf, items, report.HTMLOptions{
+		Title:      "My Report",
+		SourceRoot: root,
+		Groups:     []gocovparser.ParseGroup{gocovparser.PackageParseGroup},
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+
+	html := buf.String()
+	assert.Contains(t, html, "<title>My Report</title>")
+	assert.Contains(t, html, "Total coverage: <strong>66.7%</strong> (2/3 statements in 2 files)")
+	assert.Contains(t, html, "<h2>By package</h2>")
+	assert.Contains(t, html, "<td>github.com/heynemann/go-cov-parser/pkg</td><td>50.0%</td>")
+	assert.Contains(t, html, `<tr class="covered"><td class="number">4</td><td>	println(&#34;&lt;a&gt;&#34;)</td></tr>`)
+	assert.Contains(t, html, `<tr class="uncovered"><td class="number">8</td>`)
+	assert.Contains(t, html, `<tr class=""><td class="number">1</td><td>package pkg</td></tr>`)
+	assert.Contains(t, html, "<p>Source not available.</p>")
+}
+
+func TestHTMLReportWithoutSourceRoot(t *testing.T) {
+	items, err := gocovparser.Parse(reportFixture)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = report.WriteHTML(&buf, items, report.HTMLOptions{})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<title>Coverage Report</title>")
+	assert.NotContains(t, buf.String(), `class="source"`)
+}
+
+func TestWriteHTMLContextAbortsWhenCancelled(t *testing.T) {
+	items, err := gocovparser.Parse(reportFixture)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = report.WriteHTMLContext(ctx, &buf, items, report.HTMLOptions{})
+
+	// ASSERT
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, buf.String())
+}
+
+func TestHTMLReportWithThemeAndColumns(t *testing.T) {
+	items, err := gocovparser.Parse(reportFixture)
+	require.NoError(t, err)
+
+	overrides := fstest.MapFS{
+		"brand.tmpl": {Data: []byte(
+			`{{ define "header" }}<h1 class="brand">{{ shout .Title }}</h1>{{ end }}` +
+				`{{ define "footer" }}<footer>Acme</footer>{{ end }}`,
+		)},
+	}
+
+	var buf bytes.Buffer
+
+	// ACT
+	err = report.WriteHTML(&buf, items, report.HTMLOptions{
+		Title:     "My Report",
+		Templates: overrides,
+		Funcs:     template.FuncMap{"shout": strings.ToUpper},
+		CSS:       "h1 { color: #ff6600; }",
+		Columns: []report.HTMLColumn{{
+			Header: "Owners",
+			Value: func(cov gocovparser.Coverage) string {
+				return "@team/" + path.Base(path.Dir(cov.Path))
+			},
+		}},
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+
+	html := buf.String()
+	assert.Contains(t, html, `<h1 class="brand">MY REPORT</h1>`)
+	assert.Contains(t, html, "<footer>Acme</footer>")
+	assert.Contains(t, html, "<style>\nh1 { color: #ff6600; }\n</style>")
+	assert.Contains(t, html, "<th>Statements</th><th>Owners</th></tr>")
+	assert.Contains(t, html, "<td>1/2</td><td>@team/pkg</td></tr>")
+}
+
+func TestHTMLReportFailsForInvalidTemplateOverrides(t *testing.T) {
+	// ACT
+	err := report.WriteHTML(&bytes.Buffer{}, nil, report.HTMLOptions{
+		Templates: fstest.MapFS{"broken.tmpl": {Data: []byte(`{{ define "header" }}`)}},
+	})
+
+	// ASSERT
+	assert.Error(t, err)
+}
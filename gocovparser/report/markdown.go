@@ -0,0 +1,164 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+const (
+	defaultGoodThreshold = 0.8
+	defaultWarnThreshold = 0.6
+)
+
+// MarkdownOption configures the Markdown summary.
+type MarkdownOption func(*markdownOptions)
+
+type markdownOptions struct {
+	title         string
+	group         string
+	goodThreshold float64
+	warnThreshold float64
+	goodEmoji     string
+	warnEmoji     string
+	failEmoji     string
+
+	baseline          gocovparser.ParseGroupResult
+	baselineBreakdown *gocovparser.OverallCoverageBreakdown
+}
+
+// WithTitle sets the heading of the summary. Defaults to "Coverage Report".
+func WithTitle(title string) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.title = title
+	}
+}
+
+// WithGroup sets the name of the group listed in the table. Defaults to the package group.
+func WithGroup(name string) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.group = name
+	}
+}
+
+// WithThresholds sets the minimum coverage (0 to 1) for a row to be considered good or a warning.
+// Rows below warn are considered failing.
+func WithThresholds(good, warn float64) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.goodThreshold = good
+		opts.warnThreshold = warn
+	}
+}
+
+// WithEmojis sets the emojis used for good, warning and failing rows. Use empty strings to disable them.
+func WithEmojis(good, warn, fail string) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.goodEmoji = good
+		opts.warnEmoji = warn
+		opts.failEmoji = fail
+	}
+}
+
+// WithBaseline adds a delta column comparing the coverage against a baseline (e.g. the main branch).
+func WithBaseline(result gocovparser.ParseGroupResult, breakdown gocovparser.OverallCoverageBreakdown) MarkdownOption {
+	return func(opts *markdownOptions) {
+		opts.baseline = result
+		opts.baselineBreakdown = &breakdown
+	}
+}
+
+// RenderMarkdown renders a GitHub flavored Markdown summary of the coverage, intended for PR comments.
+func RenderMarkdown(
+	result gocovparser.ParseGroupResult,
+	breakdown gocovparser.OverallCoverageBreakdown,
+	opts ...MarkdownOption,
+) string {
+	options := markdownOptions{
+		title:         "Coverage Report",
+		group:         gocovparser.PackageParseGroup.Name,
+		goodThreshold: defaultGoodThreshold,
+		warnThreshold: defaultWarnThreshold,
+		goodEmoji:     "✅",
+		warnEmoji:     "⚠️",
+		failEmoji:     "❌",
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "## %s\n\n", options.title)
+
+	header := fmt.Sprintf("| %s | Coverage |", capitalize(options.group))
+	separator := "| --- | ---: |"
+
+	if options.baseline != nil {
+		header += " Delta |"
+		separator += " ---: |"
+	}
+
+	fmt.Fprintf(&builder, "%s\n%s\n", header, separator)
+
+	rows := result[options.group]
+
+	for _, key := range result.Keys(options.group) {
+		fmt.Fprintf(&builder, "| `%s` | %s |", key, options.formatCoverage(rows[key]))
+
+		if options.baseline != nil {
+			base, found := options.baseline[options.group][key]
+			builder.WriteString(" " + formatDelta(rows[key], base, found) + " |")
+		}
+
+		builder.WriteString("\n")
+	}
+
+	fmt.Fprintf(&builder, "| **Total** | **%s** |", options.formatCoverage(breakdown.Coverage))
+
+	if options.baselineBreakdown != nil {
+		builder.WriteString(" **" + formatDelta(breakdown.Coverage, options.baselineBreakdown.Coverage, true) + "** |")
+	}
+
+	fmt.Fprintf(
+		&builder,
+		"\n\n%d of %d statements covered in %d files.\n",
+		breakdown.CoveredStatements, breakdown.Statements, breakdown.Files,
+	)
+
+	return builder.String()
+}
+
+func (o markdownOptions) formatCoverage(value float64) string {
+	emoji := o.failEmoji
+
+	switch {
+	case value >= o.goodThreshold:
+		emoji = o.goodEmoji
+	case value >= o.warnThreshold:
+		emoji = o.warnEmoji
+	}
+
+	if emoji == "" {
+		return percent(value)
+	}
+
+	return fmt.Sprintf("%s %s", emoji, percent(value))
+}
+
+func formatDelta(value, base float64, found bool) string {
+	if !found {
+		return "new"
+	}
+
+	return fmt.Sprintf("%+.1f%%", (value-base)*100)
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+
+	return strings.ToUpper(value[:1]) + value[1:]
+}
@@ -0,0 +1,65 @@
+package report_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanRenderMarkdown(t *testing.T) {
+	result := gocovparser.ParseGroupResult{
+		"package": {
+			"github.com/a/b/pkg2": 0.5,
+			"github.com/a/b/pkg1": 0.9,
+			"github.com/a/b/pkg3": 0.7,
+		},
+	}
+	breakdown := gocovparser.OverallCoverageBreakdown{Files: 3, Statements: 10, CoveredStatements: 7, Coverage: 0.7}
+
+	// ACT
+	got := report.RenderMarkdown(result, breakdown)
+
+	// ASSERT
+	assert.Equal(t, `## Coverage Report
+
+| Package | Coverage |
+| --- | ---: |
+| `+"`github.com/a/b/pkg1`"+` | ✅ 90.0% |
+| `+"`github.com/a/b/pkg2`"+` | ❌ 50.0% |
+| `+"`github.com/a/b/pkg3`"+` | ⚠️ 70.0% |
+| **Total** | **⚠️ 70.0%** |
+
+7 of 10 statements covered in 3 files.
+`, got)
+}
+
+func TestCanRenderMarkdownWithDeltas(t *testing.T) {
+	result := gocovparser.ParseGroupResult{
+		"file": {"a.go": 0.5, "b.go": 1},
+	}
+	baseline := gocovparser.ParseGroupResult{
+		"file": {"a.go": 0.75},
+	}
+
+	// ACT
+	got := report.RenderMarkdown(
+		result,
+		gocovparser.OverallCoverageBreakdown{Coverage: 0.6},
+		report.WithTitle("PR Coverage"),
+		report.WithGroup("file"),
+		report.WithEmojis("", "", ""),
+		report.WithThresholds(0.9, 0.5),
+		report.WithBaseline(baseline, gocovparser.OverallCoverageBreakdown{Coverage: 0.5}),
+	)
+
+	// ASSERT
+	assert.Contains(t, got, "## PR Coverage\n")
+	assert.Contains(t, got, "| File | Coverage | Delta |\n| --- | ---: | ---: |\n")
+	assert.Contains(t, got, "| `a.go` | 50.0% | -25.0% |\n")
+	assert.Contains(t, got, "| `b.go` | 100.0% | new |\n")
+	assert.Contains(t, got, "| **Total** | **60.0%** | **+10.0%** |\n")
+}
@@ -0,0 +1,30 @@
+package report
+
+import (
+	"context"
+	"io"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+// WriteMultiRepoHTML renders the coverage of every repository of the report as WriteHTML does, listing the
+// coverage by repository before the configured groups. SourceRoot should hold the checkouts of the repositories
+// by coverage path, if any.
+func WriteMultiRepoHTML(ctx context.Context, w io.Writer, repos *gocovparser.MultiRepoReport, opts HTMLOptions) error {
+	opts.Groups = append([]gocovparser.ParseGroup{repos.ParseGroup()}, opts.Groups...)
+
+	return WriteHTMLContext(ctx, w, repos.Coverage(), opts)
+}
+
+// RenderMultiRepoMarkdown renders a Markdown summary of the coverage by repository, with the organization wide
+// total, as RenderMarkdown does.
+func RenderMultiRepoMarkdown(repos *gocovparser.MultiRepoReport, opts ...MarkdownOption) (string, error) {
+	result, err := repos.GroupCoverage()
+	if err != nil {
+		return "", err
+	}
+
+	opts = append([]MarkdownOption{WithGroup(gocovparser.RepositoryParseGroupName)}, opts...)
+
+	return RenderMarkdown(result, repos.Total(), opts...), nil
+}
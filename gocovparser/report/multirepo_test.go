@@ -0,0 +1,53 @@
+package report_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multiRepoReport(t *testing.T) *gocovparser.MultiRepoReport {
+	t.Helper()
+
+	repos := gocovparser.NewMultiRepoReport()
+
+	for label, data := range map[string]string{
+		"billing": "mode: set\ngithub.com/org/billing/a.go:1.1,2.2 1 1\n",
+		"search":  "mode: set\ngithub.com/org/search/a.go:1.1,2.2 1 0\n",
+	} {
+		items, err := gocovparser.Parse(data)
+		require.NoError(t, err)
+		require.NoError(t, repos.Add(label, items))
+	}
+
+	return repos
+}
+
+func TestCanRenderMultiRepoMarkdown(t *testing.T) {
+	// ACT
+	got, err := report.RenderMultiRepoMarkdown(multiRepoReport(t), report.WithEmojis("", "", ""))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, got, "| Repository | Coverage |\n| --- | ---: |\n| `billing` | 100.0% |\n| `search` | 0.0% |\n")
+	assert.Contains(t, got, "| **Total** | **50.0%** |\n\n1 of 2 statements covered in 2 files.\n")
+}
+
+func TestCanWriteMultiRepoHTML(t *testing.T) {
+	var buf bytes.Buffer
+
+	// ACT
+	err := report.WriteMultiRepoHTML(context.Background(), &buf, multiRepoReport(t), report.HTMLOptions{})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "<h2>By repository</h2>")
+	assert.Contains(t, buf.String(), "<tr><td>billing</td><td>100.0%</td></tr>")
+}
@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTrendWidth  = 400
+	defaultTrendHeight = 100
+	defaultTrendColor  = "#4c1"
+
+	// padding in pixels around the chart line, leaving room for the last value label.
+	trendPadding = 16
+)
+
+// TrendChartOptions configures the trend chart.
+type TrendChartOptions struct {
+	// Title of the chart, used for accessibility. Defaults to "coverage trend".
+	Title string
+
+	// Width and Height of the chart in pixels. Default to 400x100.
+	Width  int
+	Height int
+
+	// Color of the line and Background of the chart. Default to green on a transparent background.
+	Color      string
+	Background string
+
+	// AutoScale zooms the vertical axis to the range of the values instead of 0% to 100%.
+	AutoScale bool
+}
+
+type trendChart struct {
+	Title      string
+	Width      int
+	Height     int
+	Color      string
+	Background string
+	Points     string
+	LastX      string
+	LastY      string
+	Label      string
+	LabelX     int
+	LabelY     string
+}
+
+// RenderTrendChart renders an SVG line chart of the coverage points (see history.Trend and history.GroupTrend).
+// Points are evenly spaced in the order given, one per snapshot.
+func RenderTrendChart(points []history.TrendPoint, opts TrendChartOptions) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/trend.svg.tmpl")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse trend chart template")
+	}
+
+	opts = opts.withDefaults()
+
+	chart := trendChart{
+		Title:      opts.Title,
+		Width:      opts.Width,
+		Height:     opts.Height,
+		Color:      opts.Color,
+		Background: opts.Background,
+	}
+
+	if len(points) > 0 {
+		chart.plot(points, opts.AutoScale)
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, chart); err != nil {
+		return nil, errors.Wrap(err, "failed to render trend chart")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (o TrendChartOptions) withDefaults() TrendChartOptions {
+	if o.Title == "" {
+		o.Title = "coverage trend"
+	}
+
+	if o.Width <= 0 {
+		o.Width = defaultTrendWidth
+	}
+
+	if o.Height <= 0 {
+		o.Height = defaultTrendHeight
+	}
+
+	if o.Color == "" {
+		o.Color = defaultTrendColor
+	}
+
+	if o.Background == "" {
+		o.Background = "none"
+	}
+
+	return o
+}
+
+// plot computes the line coordinates of the points within the chart padding.
+func (c *trendChart) plot(points []history.TrendPoint, autoScale bool) {
+	low, high := 0.0, 1.0
+
+	if autoScale {
+		low, high = points[0].Coverage, points[0].Coverage
+
+		for _, point := range points {
+			if point.Coverage < low {
+				low = point.Coverage
+			}
+
+			if point.Coverage > high {
+				high = point.Coverage
+			}
+		}
+	}
+
+	plotWidth := float64(c.Width - 2*trendPadding)
+	plotHeight := float64(c.Height - 2*trendPadding)
+	coordinates := make([]string, 0, len(points))
+
+	var x, y float64
+
+	for index, point := range points {
+		x = trendPadding + plotWidth/2
+		if len(points) > 1 {
+			x = trendPadding + plotWidth*float64(index)/float64(len(points)-1)
+		}
+
+		y = trendPadding + plotHeight/2
+		if high > low {
+			y = trendPadding + plotHeight*(1-(point.Coverage-low)/(high-low))
+		}
+
+		coordinates = append(coordinates, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	c.Points = strings.Join(coordinates, " ")
+	c.LastX = fmt.Sprintf("%.1f", x)
+	c.LastY = fmt.Sprintf("%.1f", y)
+	c.Label = percent(points[len(points)-1].Coverage)
+	c.LabelX = c.Width - trendPadding/4
+	c.LabelY = fmt.Sprintf("%.1f", y-trendPadding/4)
+}
@@ -0,0 +1,54 @@
+package report_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/history"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanRenderTrendChart(t *testing.T) {
+	points := []history.TrendPoint{
+		{Commit: "a", Coverage: 0.5},
+		{Commit: "b", Coverage: 0.25},
+		{Commit: "c", Coverage: 0.75},
+	}
+
+	// ACT
+	got, err := report.RenderTrendChart(points, report.TrendChartOptions{Width: 232, Height: 132})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal(got, new(struct{})))
+
+	svg := string(got)
+	assert.Contains(t, svg, `points="16.0,66.0 116.0,91.0 216.0,41.0"`)
+	assert.Contains(t, svg, ">75.0%</text>")
+	assert.Contains(t, svg, `aria-label="coverage trend"`)
+}
+
+func TestTrendChartCanAutoScale(t *testing.T) {
+	points := []history.TrendPoint{{Coverage: 0.8}, {Coverage: 0.9}}
+
+	// ACT
+	got, err := report.RenderTrendChart(points, report.TrendChartOptions{Width: 132, Height: 132, AutoScale: true})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `points="16.0,116.0 116.0,16.0"`)
+}
+
+func TestTrendChartWithoutPoints(t *testing.T) {
+	// ACT
+	got, err := report.RenderTrendChart(nil, report.TrendChartOptions{})
+
+	// ASSERT
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal(got, new(struct{})))
+	assert.NotContains(t, string(got), "polyline")
+}
@@ -0,0 +1,129 @@
+package gocovparser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"sort"
+)
+
+// GetRiskReport weights the coverage of every function by its cyclomatic complexity, reading the source files from
+// their SourcePath under sourceRoot, and ranks the functions by risk, keeping the first limit ones. A limit lower
+// than 1 keeps every function. Functions without statements and statements outside of functions are ignored.
+func GetRiskReport(items []Coverage, sourceRoot string, limit int, opts ...PercentOption) (RiskReport, error) {
+	options := newPercentOptions(opts)
+	functions := []FunctionRisk{}
+
+	var covered, total, weightedCovered, weightedTotal int
+
+	for _, cov := range items {
+		funcs, err := findFuncs(SourcePath(cov, sourceRoot))
+		if err != nil {
+			return RiskReport{}, err
+		}
+
+		for _, fn := range funcs {
+			risk := functionRisk(cov, fn)
+			if risk.Statements == 0 {
+				continue
+			}
+
+			covered += risk.Covered
+			total += risk.Statements
+			weightedCovered += risk.Covered * risk.Complexity
+			weightedTotal += risk.Statements * risk.Complexity
+
+			risk.Coverage = options.percentOf(risk.Covered, risk.Statements)
+			functions = append(functions, risk)
+		}
+	}
+
+	return RiskReport{
+		Coverage:             options.percentOf(covered, total),
+		RiskAdjustedCoverage: options.percentOf(weightedCovered, weightedTotal),
+		Functions:            rankRisks(functions, limit),
+	}, nil
+}
+
+// functionRisk counts the statements of the function in the coverage of its file.
+func functionRisk(cov Coverage, fn funcExtent) FunctionRisk {
+	risk := FunctionRisk{
+		Function:   fmt.Sprintf("%s.%s", path.Dir(cov.FileName), fn.name),
+		FileName:   cov.FileName,
+		Path:       cov.Path,
+		Lines:      LineRange{Start: fn.startLine, End: fn.endLine},
+		Complexity: fn.complexity,
+	}
+
+	for _, b := range cov.Blocks {
+		if !fn.contains(b) {
+			continue
+		}
+
+		risk.Statements += b.NumStmt
+
+		if b.Count > 0 { // is covered
+			risk.Covered += b.NumStmt
+		}
+	}
+
+	// the risk is unscaled and unrounded, whatever the percent options of the coverage
+	risk.Risk = float64(risk.Complexity*(risk.Statements-risk.Covered)) / float64(risk.Statements)
+
+	return risk
+}
+
+// rankRisks sorts functions by decreasing risk, then by complexity, file and line, and keeps the first limit ones.
+func rankRisks(functions []FunctionRisk, limit int) []FunctionRisk {
+	sort.SliceStable(functions, func(i, j int) bool {
+		if functions[i].Risk != functions[j].Risk {
+			return functions[i].Risk > functions[j].Risk
+		}
+
+		if functions[i].Complexity != functions[j].Complexity {
+			return functions[i].Complexity > functions[j].Complexity
+		}
+
+		if functions[i].FileName != functions[j].FileName {
+			return functions[i].FileName < functions[j].FileName
+		}
+
+		return functions[i].Lines.Start < functions[j].Lines.Start
+	})
+
+	if limit > 0 && len(functions) > limit {
+		functions = functions[:limit]
+	}
+
+	return functions
+}
+
+// cyclomaticComplexity returns 1 plus the number of decision points of the function: conditions, loops, non
+// default cases and boolean operators, as counted by gocyclo.
+func cyclomaticComplexity(fn *ast.FuncDecl) int {
+	complexity := 1
+
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+
+		return true
+	})
+
+	return complexity
+}
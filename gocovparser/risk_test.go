@@ -0,0 +1,121 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const riskSource = `package pkg
+
+func Simple() int {
+	return 1
+}
+
+func Branchy(a, b int) int {
+	if a > 0 && b > 0 {
+		return 1
+	}
+
+	for i := 0; i < a; i++ {
+		switch i {
+		case 1:
+			return 2
+		case 2, 3:
+			return 3
+		default:
+		}
+	}
+
+	return 0
+}
+`
+
+func TestGetRiskReport(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "risk.go"), []byte(riskSource), 0o600))
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/owner/repo/pkg/risk.go:3.20,5.2 1 1
+github.com/owner/repo/pkg/risk.go:7.32,8.21 1 1
+github.com/owner/repo/pkg/risk.go:8.21,10.3 1 1
+github.com/owner/repo/pkg/risk.go:12.2,12.26 1 1
+github.com/owner/repo/pkg/risk.go:12.26,13.12 1 0
+github.com/owner/repo/pkg/risk.go:14.10,15.12 1 0
+github.com/owner/repo/pkg/risk.go:16.13,17.12 1 0
+github.com/owner/repo/pkg/risk.go:22.2,22.10 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GetRiskReport(items, root, 0)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got.Functions, 2)
+
+	branchy := got.Functions[0]
+	assert.Equal(t, "github.com/owner/repo/pkg.Branchy", branchy.Function)
+	assert.Equal(t, gocovparser.LineRange{Start: 7, End: 23}, branchy.Lines)
+	assert.Equal(t, 6, branchy.Complexity)
+	assert.Equal(t, 7, branchy.Statements)
+	assert.Equal(t, 3, branchy.Covered)
+	assert.InDelta(t, 3.0/7, branchy.Coverage, 1e-9)
+	assert.InDelta(t, 6*4.0/7, branchy.Risk, 1e-9)
+
+	simple := got.Functions[1]
+	assert.Equal(t, 1, simple.Complexity)
+	assert.InDelta(t, 0, simple.Risk, 1e-9)
+
+	assert.InDelta(t, 4.0/8, got.Coverage, 1e-9)
+	assert.InDelta(t, (1+3*6.0)/(1+7*6.0), got.RiskAdjustedCoverage, 1e-9)
+}
+
+func TestGetRiskReportKeepsLimit(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "risk.go"), []byte(riskSource), 0o600))
+
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/owner/repo/pkg/risk.go:3.20,5.2 1 0
+github.com/owner/repo/pkg/risk.go:7.32,8.21 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.GetRiskReport(items, root, 1)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got.Functions, 1)
+	assert.Equal(t, "github.com/owner/repo/pkg.Branchy", got.Functions[0].Function)
+	assert.InDelta(t, 6, got.Functions[0].Risk, 1e-9)
+}
+
+func TestGetRiskReportReadsLocalPaths(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "risk.go")
+	require.NoError(t, os.WriteFile(local, []byte(riskSource), 0o600))
+
+	items, err := gocovparser.Parse("mode: set\ngithub.com/owner/repo/pkg/risk.go:3.20,5.2 1 1\n")
+	require.NoError(t, err)
+
+	items[0].LocalPath = local
+
+	// ACT
+	got, err := gocovparser.GetRiskReport(items, t.TempDir(), 0)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got.Functions, 1)
+	assert.Equal(t, "github.com/owner/repo/pkg.Simple", got.Functions[0].Function)
+	assert.InDelta(t, 1, got.Coverage, 1e-9)
+}
@@ -0,0 +1,358 @@
+package server
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxUploadSize is the size limit of uploaded profiles, unless set in CollectorOptions.
+	DefaultMaxUploadSize = 64 << 20
+
+	uploadsPath  = "/api/uploads"
+	reportsPath  = "/api/reports"
+	bearerPrefix = "Bearer "
+)
+
+// CollectorOptions configures the collector.
+type CollectorOptions struct {
+	// Groups served by the API. Defaults to the package and file groups.
+	Groups []gocovparser.ParseGroup
+
+	// ParseOptions used when parsing uploaded profiles, e.g. gocovparser.WithLenientParsing.
+	ParseOptions []gocovparser.ParseOption
+
+	// Token uploads must send as `Authorization: Bearer <token>`, if set. Queries are not authenticated.
+	Token string
+
+	// MaxUploadSize is the size limit of uploaded profiles in bytes, after decompression. Defaults to
+	// DefaultMaxUploadSize.
+	MaxUploadSize int64
+
+	// Logger receives info logs of the stored uploads, if set.
+	Logger gocovparser.Logger
+}
+
+// Collector is an http.Handler collecting the coverage profiles uploaded by CI jobs into reports, and serving
+// queries on them, as a lightweight in-house coverage service:
+//
+//	POST /api/uploads?repo=org/repo&commit=sha[&branch=main][&job=unit]  stores the profile of the body
+//	GET  /api/reports[?repo=org/repo]                                     lists the reports, oldest first
+//	GET  /api/total?repo=org/repo[&commit=sha|&branch=main]               total coverage
+//	GET  /api/groups/{group}?repo=org/repo[&commit=sha|&branch=main]      coverage by the keys of the group
+//	GET  /api/files/{path}?repo=org/repo[&commit=sha|&branch=main]        coverage and lines of the file
+//
+// Uploaded profiles may be gzipped, with a `Content-Encoding: gzip` header. Profiles uploaded for the same commit
+// (e.g. by sharded test jobs) are merged, except that a job uploading again (e.g. when retried) replaces its
+// previous upload. Uploads that can't be merged with the report of the commit are rejected and leave it untouched.
+// Repositories and commits must be valid path segments, e.g. not `..`. Queries without a commit use the latest
+// report of the repository, or of the branch if given.
+type Collector struct {
+	storage Storage
+	opts    CollectorOptions
+	mux     *http.ServeMux
+
+	// mu serializes uploads, so concurrent uploads for a commit are all merged
+	mu sync.Mutex
+}
+
+var _ http.Handler = (*Collector)(nil)
+
+// NewCollector returns a collector storing reports in storage.
+func NewCollector(storage Storage, opts CollectorOptions) *Collector {
+	if len(opts.Groups) == 0 {
+		opts.Groups = []gocovparser.ParseGroup{gocovparser.PackageParseGroup, gocovparser.FileParseGroup}
+	}
+
+	if opts.MaxUploadSize <= 0 {
+		opts.MaxUploadSize = DefaultMaxUploadSize
+	}
+
+	c := &Collector{storage: storage, opts: opts, mux: http.NewServeMux()}
+
+	c.mux.HandleFunc(uploadsPath, c.serveUpload)
+	c.mux.HandleFunc(reportsPath, c.serveReports)
+	c.mux.HandleFunc("/api/total", c.serveTotal)
+	c.mux.HandleFunc(groupsPrefix, c.serveGroup)
+	c.mux.HandleFunc(filesPrefix, c.serveFile)
+
+	return c
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+func (c *Collector) serveUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	if !c.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid upload token"))
+
+		return
+	}
+
+	query := r.URL.Query()
+	repo, commit := query.Get("repo"), query.Get("commit")
+
+	if err := validateReportKey(repo, commit); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(ErrInvalidUpload, err.Error()))
+
+		return
+	}
+
+	items, err := c.parseUpload(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(ErrInvalidUpload, err.Error()))
+
+		return
+	}
+
+	report, err := c.store(r, repo, commit, query.Get("branch"), query.Get("job"), items)
+
+	switch {
+	case errors.Is(err, ErrInvalidUpload):
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Info(
+			"stored coverage upload",
+			"repo", repo, "commit", commit, "job", query.Get("job"), "files", len(items),
+		)
+	}
+
+	writeJSON(w, http.StatusCreated, newJSONReport(report))
+}
+
+func (c *Collector) authorized(r *http.Request) bool {
+	if c.opts.Token == "" {
+		return true
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), bearerPrefix)
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(c.opts.Token)) == 1
+}
+
+// parseUpload parses the profile in the request body, decompressing it if gzipped.
+func (c *Collector) parseUpload(r *http.Request) ([]gocovparser.Coverage, error) {
+	var body io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid gzip body")
+		}
+		defer reader.Close()
+
+		body = reader
+	}
+
+	limited := &io.LimitedReader{R: body, N: c.opts.MaxUploadSize + 1}
+
+	items, err := gocovparser.ParseReaderContext(r.Context(), limited, c.opts.ParseOptions...)
+	if limited.N == 0 {
+		return nil, errors.Errorf("profile larger than %d bytes", c.opts.MaxUploadSize)
+	}
+
+	return items, err
+}
+
+// store adds the uploaded coverage to the report of the commit. The upload of a job replaces its previous upload
+// (e.g. when the job is retried), uploads without job are merged.
+func (c *Collector) store(
+	r *http.Request, repo, commit, branch, job string, items []gocovparser.Coverage,
+) (Report, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report, err := c.storage.Load(r.Context(), repo, commit)
+
+	switch {
+	case errors.Is(err, ErrReportNotFound):
+		report = Report{Repo: repo, Commit: commit}
+	case err != nil:
+		return Report{}, err
+	}
+
+	// the uploads and jobs are copied, so a rejected upload leaves the stored report untouched
+	uploads := make(map[string][]gocovparser.Coverage, len(report.Uploads)+1)
+	for name, upload := range report.Uploads {
+		uploads[name] = upload
+	}
+
+	jobs := append([]string{}, report.Jobs...)
+
+	switch _, found := uploads[job]; {
+	case job == "" && found:
+		items, err = gocovparser.MergeCoverage(uploads[job], items)
+		if err != nil {
+			return Report{}, errors.Wrap(ErrInvalidUpload, err.Error())
+		}
+	case job != "" && !found:
+		jobs = append(jobs, job)
+	}
+
+	uploads[job] = items
+
+	coverage, err := mergeUploads(uploads)
+	if err != nil {
+		return Report{}, errors.Wrap(ErrInvalidUpload, err.Error())
+	}
+
+	report.Uploads, report.Jobs, report.Coverage = uploads, jobs, coverage
+
+	if branch != "" {
+		report.Branch = branch
+	}
+
+	report.Timestamp = time.Now().UTC()
+
+	return report, c.storage.Save(r.Context(), report)
+}
+
+// mergeUploads merges the uploads of the jobs of a report, by job name.
+func mergeUploads(uploads map[string][]gocovparser.Coverage) ([]gocovparser.Coverage, error) {
+	jobs := make([]string, 0, len(uploads))
+	for job := range uploads {
+		jobs = append(jobs, job)
+	}
+
+	sort.Strings(jobs)
+
+	items := make([][]gocovparser.Coverage, 0, len(jobs))
+	for _, job := range jobs {
+		items = append(items, uploads[job])
+	}
+
+	return gocovparser.MergeCoverage(items...)
+}
+
+func (c *Collector) serveReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := c.storage.Reports(r.Context(), r.URL.Query().Get("repo"))
+
+	switch {
+	case errors.Is(err, ErrInvalidReportKey):
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	result := make([]jsonReport, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, newJSONReport(report))
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// report returns the report of the repository and commit of the query, or the latest one of the repository or
+// of the branch of the query.
+func (c *Collector) report(r *http.Request) (Report, error) {
+	query := r.URL.Query()
+	repo, commit, branch := query.Get("repo"), query.Get("commit"), query.Get("branch")
+
+	if commit != "" {
+		if err := validateReportKey(repo, commit); err != nil {
+			return Report{}, err
+		}
+
+		return c.storage.Load(r.Context(), repo, commit)
+	}
+
+	if !validRepo(repo) {
+		return Report{}, errors.Wrapf(ErrInvalidReportKey, "repository %q", repo)
+	}
+
+	reports, err := c.storage.Reports(r.Context(), repo)
+	if err != nil {
+		return Report{}, err
+	}
+
+	for index := len(reports) - 1; index >= 0; index-- {
+		if reports[index].Repo == repo && (branch == "" || reports[index].Branch == branch) {
+			return reports[index], nil
+		}
+	}
+
+	return Report{}, errors.Wrapf(ErrReportNotFound, "repository %q", repo)
+}
+
+// serveReportOf writes the error of the report, or calls serve with it.
+func (c *Collector) serveReportOf(w http.ResponseWriter, r *http.Request, serve func(Report)) {
+	report, err := c.report(r)
+
+	switch {
+	case errors.Is(err, ErrReportNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrInvalidReportKey):
+		writeError(w, http.StatusBadRequest, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		serve(report)
+	}
+}
+
+func (c *Collector) serveTotal(w http.ResponseWriter, r *http.Request) {
+	c.serveReportOf(w, r, func(report Report) {
+		writeJSON(w, http.StatusOK, gocovparser.GetTotalCoverageBreakdown(report.Coverage))
+	})
+}
+
+func (c *Collector) serveGroup(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, groupsPrefix)
+
+	for _, group := range c.opts.Groups {
+		if group.Name == name {
+			c.serveReportOf(w, r, func(report Report) {
+				writeJSON(w, http.StatusOK, groupDetails(report.Coverage, group))
+			})
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, errors.Errorf("unknown group %q", name))
+}
+
+func (c *Collector) serveFile(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, filesPrefix)
+
+	c.serveReportOf(w, r, func(report Report) {
+		for _, cov := range report.Coverage {
+			if cov.Path == path || cov.FileName == path {
+				writeJSON(w, http.StatusOK, newJSONFile(cov))
+
+				return
+			}
+		}
+
+		writeError(w, http.StatusNotFound, errors.Errorf("unknown file %q", path))
+	})
+}
@@ -0,0 +1,254 @@
+package server_test
+
+//revive:disable:add-constant
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shardFixture = `mode: set
+github.com/heynemann/go-cov-parser/pkg/a.go:7.10,9.2 1 1
+`
+
+func upload(t *testing.T, handler http.Handler, target, profile string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, target, strings.NewReader(profile)))
+
+	return recorder
+}
+
+func TestCollectorStoresUploads(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+
+	// ACT
+	recorder := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&branch=main&job=unit", serverFixture)
+
+	// ASSERT
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	body := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "org/repo", body["repo"])
+	assert.Equal(t, "abc", body["commit"])
+	assert.Equal(t, "main", body["branch"])
+	assert.EqualValues(t, 2, body["files"])
+	assert.EqualValues(t, 0.75, body["coverage"])
+}
+
+func TestCollectorMergesUploadsOfACommit(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=1", serverFixture).Code)
+
+	// ACT
+	recorder := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=2", shardFixture)
+	total, body := get(t, collector, "/api/total?repo=org/repo&commit=abc")
+
+	// ASSERT
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"jobs":["1","2"]`)
+	assert.Equal(t, http.StatusOK, total.Code)
+	assert.EqualValues(t, 1, body["coverage"])
+}
+
+func TestCollectorReplacesUploadsOfRetriedJobs(t *testing.T) {
+	const count = "mode: count\ngithub.com/heynemann/go-cov-parser/pkg/a.go:7.10,9.2 1 2\n"
+
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=unit", count).Code)
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=abc", count).Code)
+
+	// ACT
+	recorder := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=unit", count)
+	file, body := get(t, collector, "/api/files/pkg/a.go?repo=org/repo&commit=abc")
+
+	// ASSERT
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"jobs":["unit"]`)
+	assert.Equal(t, http.StatusOK, file.Code)
+	assert.Equal(t, map[string]interface{}{"hits": 4.0, "status": "covered"}, body["lines"].(map[string]interface{})["7"])
+}
+
+func TestCollectorRejectsUploadsThatCantBeMerged(t *testing.T) {
+	const (
+		unit  = "mode: set\ngithub.com/heynemann/go-cov-parser/pkg/a.go:1.1,2.1 2 1\n"
+		integ = "mode: set\ngithub.com/heynemann/go-cov-parser/pkg/a.go:1.1,2.1 3 1\n"
+	)
+
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=unit", unit).Code)
+
+	// ACT
+	rejected := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=integ", integ)
+	accepted := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc&job=e2e", unit)
+
+	// ASSERT
+	assert.Equal(t, http.StatusBadRequest, rejected.Code)
+	assert.Equal(t, http.StatusCreated, accepted.Code)
+	assert.Contains(t, accepted.Body.String(), `"jobs":["unit","e2e"]`)
+}
+
+func TestCollectorRejectsKeysOutsideOfStorage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	collector := server.NewCollector(server.NewDirStorage(dir), server.CollectorOptions{})
+
+	// ACT
+	codes := []int{}
+	for _, query := range []string{
+		"repo=..&commit=escaped", "repo=org/..&commit=abc", "repo=.&commit=abc",
+		"repo=org/repo&commit=..", "repo=org/repo&commit=a/b",
+	} {
+		codes = append(codes, upload(t, collector, "/api/uploads?"+query, serverFixture).Code)
+	}
+
+	total, _ := get(t, collector, "/api/total?repo=..&commit=escaped")
+	reports, _ := get(t, collector, "/api/groups/package?repo=..")
+
+	// ASSERT
+	for _, code := range codes {
+		assert.Equal(t, http.StatusBadRequest, code)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, total.Code)
+	assert.Equal(t, http.StatusBadRequest, reports.Code)
+
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = server.NewDirStorage(dir).Load(context.Background(), "..", "escaped")
+	assert.ErrorIs(t, err, server.ErrInvalidReportKey)
+}
+
+func TestCollectorServesLatestReportOfBranch(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=a&branch=main", serverFixture).Code)
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=b&branch=dev", shardFixture).Code)
+
+	// ACT
+	latest, latestBody := get(t, collector, "/api/total?repo=org/repo")
+	main, mainBody := get(t, collector, "/api/groups/package?repo=org/repo&branch=main")
+	file, fileBody := get(t, collector, "/api/files/pkg/a.go?repo=org/repo&commit=a")
+	missing, _ := get(t, collector, "/api/total?repo=other/repo")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, latest.Code)
+	assert.EqualValues(t, 1, latestBody["statements"])
+	assert.Equal(t, http.StatusOK, main.Code)
+	assert.Contains(t, mainBody, "github.com/heynemann/go-cov-parser/other")
+	assert.Equal(t, http.StatusOK, file.Code)
+	assert.Equal(t, "pkg/a.go", fileBody["path"])
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestCollectorListsReports(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/repo&commit=a", serverFixture).Code)
+	require.Equal(t, http.StatusCreated, upload(t, collector, "/api/uploads?repo=org/other&commit=b", shardFixture).Code)
+
+	recorder := httptest.NewRecorder()
+
+	// ACT
+	collector.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/reports?repo=org/repo", nil))
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	reports := []map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &reports))
+	require.Len(t, reports, 1)
+	assert.Equal(t, "a", reports[0]["commit"])
+}
+
+func TestCollectorAcceptsGzippedUploads(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{})
+
+	var body bytes.Buffer
+
+	writer := gzip.NewWriter(&body)
+	_, err := writer.Write([]byte(serverFixture))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/uploads?repo=org/repo&commit=abc", &body)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	recorder := httptest.NewRecorder()
+
+	// ACT
+	collector.ServeHTTP(recorder, req)
+
+	// ASSERT
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestCollectorRejectsInvalidUploads(t *testing.T) {
+	collector := server.NewCollector(server.NewMemoryStorage(), server.CollectorOptions{
+		Token:         "secret",
+		MaxUploadSize: int64(len(serverFixture)),
+	})
+
+	authorized := func(target, profile string) int {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(profile))
+		req.Header.Set("Authorization", "Bearer secret")
+
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	// ACT
+	unauthorized := upload(t, collector, "/api/uploads?repo=org/repo&commit=abc", serverFixture).Code
+	missingCommit := authorized("/api/uploads?repo=org/repo", serverFixture)
+	malformed := authorized("/api/uploads?repo=org/repo&commit=abc", "not coverage")
+	tooLarge := authorized("/api/uploads?repo=org/repo&commit=abc", serverFixture+shardFixture)
+	accepted := authorized("/api/uploads?repo=org/repo&commit=abc", serverFixture)
+
+	// ASSERT
+	assert.Equal(t, http.StatusUnauthorized, unauthorized)
+	assert.Equal(t, http.StatusBadRequest, missingCommit)
+	assert.Equal(t, http.StatusBadRequest, malformed)
+	assert.Equal(t, http.StatusBadRequest, tooLarge)
+	assert.Equal(t, http.StatusCreated, accepted)
+}
+
+func TestDirStoragePersistsReports(t *testing.T) {
+	dir := t.TempDir()
+	items, err := gocovparser.Parse(serverFixture)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	report := server.Report{Repo: "org/repo", Commit: "abc", Branch: "main", Coverage: items}
+	require.NoError(t, server.NewDirStorage(dir).Save(ctx, report))
+
+	// ACT
+	storage := server.NewDirStorage(dir)
+	loaded, loadErr := storage.Load(ctx, "org/repo", "abc")
+	reports, reportsErr := storage.Reports(ctx, "")
+	_, missingErr := storage.Load(ctx, "org/repo", "def")
+
+	// ASSERT
+	require.NoError(t, loadErr)
+	require.NoError(t, reportsErr)
+	assert.Equal(t, report.Branch, loaded.Branch)
+	assert.Equal(t, gocovparser.GetTotalCoverageBreakdown(items), gocovparser.GetTotalCoverageBreakdown(loaded.Coverage))
+	require.Len(t, reports, 1)
+	assert.Equal(t, "abc", reports[0].Commit)
+	assert.ErrorIs(t, missingErr, server.ErrReportNotFound)
+}
@@ -0,0 +1,12 @@
+package server
+
+import "errors"
+
+// ErrReportNotFound happens when the report of a repository or commit is requested before any upload.
+var ErrReportNotFound = errors.New("report not found - no coverage was uploaded")
+
+// ErrInvalidUpload happens when an upload misses its repository or commit, or its profile can't be parsed.
+var ErrInvalidUpload = errors.New("invalid upload - unable to store coverage")
+
+// ErrInvalidReportKey happens when a repository or commit can't name a report, e.g. `..` or a commit with slashes.
+var ErrInvalidReportKey = errors.New("invalid repository or commit - unable to name report")
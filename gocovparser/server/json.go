@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+)
+
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+type jsonDetail struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+type jsonRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type jsonLine struct {
+	Hits   int    `json:"hits"`
+	Status string `json:"status"`
+}
+
+type jsonFile struct {
+	FileName          string              `json:"fileName"`
+	Path              string              `json:"path"`
+	Statements        int                 `json:"statements"`
+	CoveredStatements int                 `json:"coveredStatements"`
+	Coverage          float64             `json:"coverage"`
+	UncoveredRanges   []jsonRange         `json:"uncoveredRanges"`
+	Lines             map[string]jsonLine `json:"lines"`
+}
+
+func newJSONFile(cov gocovparser.Coverage) jsonFile {
+	file := jsonFile{
+		FileName:          cov.FileName,
+		Path:              cov.Path,
+		Statements:        cov.TotalStatements(),
+		CoveredStatements: cov.CoveredStatements(),
+		Coverage:          cov.Percent(),
+		UncoveredRanges:   []jsonRange{},
+		Lines:             make(map[string]jsonLine),
+	}
+
+	for _, r := range cov.UncoveredRanges() {
+		file.UncoveredRanges = append(file.UncoveredRanges, jsonRange(r))
+	}
+
+	for line, coverage := range gocovparser.GetLineCoverage([]gocovparser.Coverage{cov})[cov.FileName] {
+		file.Lines[strconv.Itoa(line)] = jsonLine{Hits: coverage.Hits, Status: coverage.Status.String()}
+	}
+
+	return file
+}
+
+type jsonReport struct {
+	Repo              string    `json:"repo"`
+	Commit            string    `json:"commit"`
+	Branch            string    `json:"branch,omitempty"`
+	Jobs              []string  `json:"jobs,omitempty"`
+	Timestamp         time.Time `json:"timestamp"`
+	Files             int       `json:"files"`
+	Statements        int       `json:"statements"`
+	CoveredStatements int       `json:"coveredStatements"`
+	Coverage          float64   `json:"coverage"`
+}
+
+func newJSONReport(report Report) jsonReport {
+	breakdown := gocovparser.GetTotalCoverageBreakdown(report.Coverage)
+
+	return jsonReport{
+		Repo:              report.Repo,
+		Commit:            report.Commit,
+		Branch:            report.Branch,
+		Jobs:              report.Jobs,
+		Timestamp:         report.Timestamp,
+		Files:             breakdown.Files,
+		Statements:        breakdown.Statements,
+		CoveredStatements: breakdown.CoveredStatements,
+		Coverage:          breakdown.Coverage,
+	}
+}
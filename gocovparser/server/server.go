@@ -0,0 +1,239 @@
+// Package server serves coverage reports, a JSON API and Prometheus metrics over HTTP, re-parsing the coverage
+// file when it changes. Its Collector collects the coverage uploaded by CI jobs of many repositories instead.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/heynemann/go-cov-parser/gocovparser/export"
+	"github.com/heynemann/go-cov-parser/gocovparser/report"
+	"github.com/pkg/errors"
+)
+
+const (
+	groupsPrefix = "/api/groups/"
+	filesPrefix  = "/api/files/"
+)
+
+// Options configures the server.
+type Options struct {
+	// Title of the HTML report.
+	Title string
+
+	// SourceRoot is the directory coverage paths are relative to, used to annotate sources in the HTML report.
+	SourceRoot string
+
+	// Groups served by the API and shown in the report. Defaults to the package and file groups.
+	Groups []gocovparser.ParseGroup
+
+	// ParseOptions used when parsing the coverage file, e.g. gocovparser.WithCache to skip parsing a rewritten but
+	// unchanged profile again.
+	ParseOptions []gocovparser.ParseOption
+
+	// Logger receives debug logs of the parsing of the coverage file, if set.
+	Logger gocovparser.Logger
+}
+
+// Server is an http.Handler serving the coverage file at its path.
+type Server struct {
+	path string
+	opts Options
+	mux  *http.ServeMux
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	items   []gocovparser.Coverage
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// New returns a server for the coverage file at path. The file is parsed on the first request
+// and parsed again whenever its modification time or size change.
+func New(path string, opts Options) *Server {
+	if len(opts.Groups) == 0 {
+		opts.Groups = []gocovparser.ParseGroup{gocovparser.PackageParseGroup, gocovparser.FileParseGroup}
+	}
+
+	s := &Server{path: path, opts: opts, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("/", s.serveReport)
+	s.mux.HandleFunc("/api/total", s.serveTotal)
+	s.mux.HandleFunc("/metrics", s.serveMetrics)
+	s.mux.HandleFunc(groupsPrefix, s.serveGroup)
+	s.mux.HandleFunc(filesPrefix, s.serveFile)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// coverage returns the parsed coverage, parsing the file again if it changed since it was last parsed.
+func (s *Server) coverage() ([]gocovparser.Coverage, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read coverage file %q", s.path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.items != nil && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.items, nil
+	}
+
+	opts := s.opts.ParseOptions
+	if s.opts.Logger != nil {
+		opts = append([]gocovparser.ParseOption{gocovparser.WithLogger(pathLogger{s.opts.Logger, s.path})}, opts...)
+	}
+
+	items, err := gocovparser.ParseFile(s.path, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.items, s.modTime, s.size = items, info.ModTime(), info.Size()
+
+	return items, nil
+}
+
+func (s *Server) serveReport(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	items, err := s.coverage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	err = report.WriteHTML(w, items, report.HTMLOptions{
+		Title:      s.opts.Title,
+		SourceRoot: s.opts.SourceRoot,
+		Groups:     s.opts.Groups,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func (s *Server) serveTotal(w http.ResponseWriter, _ *http.Request) {
+	items, err := s.coverage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gocovparser.GetTotalCoverageBreakdown(items))
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	items, err := s.coverage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", export.PrometheusContentType)
+
+	_ = export.WritePrometheusMetrics(w, items)
+}
+
+func (s *Server) serveGroup(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, groupsPrefix)
+
+	for _, group := range s.opts.Groups {
+		if group.Name != name {
+			continue
+		}
+
+		items, err := s.coverage()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, groupDetails(items, group))
+
+		return
+	}
+
+	writeError(w, http.StatusNotFound, errors.Errorf("unknown group %q", name))
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, filesPrefix)
+
+	items, err := s.coverage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	for _, cov := range items {
+		if cov.Path == path || cov.FileName == path {
+			writeJSON(w, http.StatusOK, newJSONFile(cov))
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, errors.Errorf("unknown file %q", path))
+}
+
+// groupDetails returns the coverage of each key of the group.
+func groupDetails(items []gocovparser.Coverage, group gocovparser.ParseGroup) map[string]jsonDetail {
+	details := gocovparser.GroupBy(items, func(cov gocovparser.Coverage) string {
+		return group.Key(cov)
+	})
+
+	result := make(map[string]jsonDetail, len(details))
+	for key, detail := range details {
+		result[key] = jsonDetail(detail)
+	}
+
+	return result
+}
+
+// pathLogger adds the path of the coverage file to the logs of its parsing.
+type pathLogger struct {
+	logger gocovparser.Logger
+	path   string
+}
+
+func (l pathLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, append([]interface{}{"path", l.path}, args...)...)
+}
+
+func (l pathLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, append([]interface{}{"path", l.path}, args...)...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, jsonError{Error: err.Error()})
+}
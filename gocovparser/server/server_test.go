@@ -0,0 +1,140 @@
+package server_test
+
+//revive:disable:add-constant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serverFixture = `mode: set
+github.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1
+github.com/heynemann/go-cov-parser/pkg/a.go:7.10,9.2 1 0
+github.com/heynemann/go-cov-parser/other/b.go:3.10,5.2 2 1
+`
+
+func newServer(t *testing.T) (*server.Server, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	require.NoError(t, os.WriteFile(path, []byte(serverFixture), 0o600))
+
+	return server.New(path, server.Options{Title: "Dashboard"}), path
+}
+
+func get(t *testing.T, handler http.Handler, target string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+	body := map[string]interface{}{}
+	if recorder.Header().Get("Content-Type") == "application/json" {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	}
+
+	return recorder, body
+}
+
+func TestServesHTMLReport(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// ACT
+	recorder, _ := get(t, handler, "/")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Dashboard")
+}
+
+func TestServesTotal(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// ACT
+	recorder, body := get(t, handler, "/api/total")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, 4, body["statements"])
+	assert.EqualValues(t, 0.75, body["coverage"])
+}
+
+func TestServesGroups(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// ACT
+	recorder, body := get(t, handler, "/api/groups/package")
+	unknown, _ := get(t, handler, "/api/groups/unknown")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, map[string]interface{}{
+		"covered": 1.0, "total": 2.0, "percent": 0.5,
+	}, body["github.com/heynemann/go-cov-parser/pkg"])
+	assert.Equal(t, http.StatusNotFound, unknown.Code)
+}
+
+func TestServesFiles(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// ACT
+	recorder, body := get(t, handler, "/api/files/pkg/a.go")
+	unknown, _ := get(t, handler, "/api/files/pkg/missing.go")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "pkg/a.go", body["path"])
+	assert.EqualValues(t, 0.5, body["coverage"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"start": 7.0, "end": 9.0}}, body["uncoveredRanges"])
+	assert.Equal(t, http.StatusNotFound, unknown.Code)
+}
+
+func TestReparsesChangedCoverageFile(t *testing.T) {
+	handler, path := newServer(t)
+
+	_, before := get(t, handler, "/api/total")
+	require.EqualValues(t, 0.75, before["coverage"])
+
+	require.NoError(t, os.WriteFile(path, []byte("mode: set\ngithub.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1\n"), 0o600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	// ACT
+	_, after := get(t, handler, "/api/total")
+
+	// ASSERT
+	assert.EqualValues(t, 1, after["coverage"])
+}
+
+func TestFailsWhenCoverageFileIsMissing(t *testing.T) {
+	handler := server.New(filepath.Join(t.TempDir(), "missing.out"), server.Options{})
+
+	// ACT
+	recorder, body := get(t, handler, "/api/total")
+
+	// ASSERT
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, body["error"], "missing.out")
+}
+
+func TestServesPrometheusMetrics(t *testing.T) {
+	handler, _ := newServer(t)
+
+	// ACT
+	recorder, _ := get(t, handler, "/metrics")
+
+	// ASSERT
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "gocovparser_coverage_ratio 0.75\n")
+	assert.Contains(t, recorder.Body.String(), `gocovparser_package_statements{package="github.com/heynemann/go-cov-parser/pkg"} 2`)
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+// reportExtension is the extension of the files of the reports stored by NewDirStorage.
+const reportExtension = ".json"
+
+// Report is the coverage of a commit of a repository, merged from the profiles uploaded by its CI jobs.
+type Report struct {
+	Repo      string                 `json:"repo"`
+	Commit    string                 `json:"commit"`
+	Branch    string                 `json:"branch,omitempty"`
+	Jobs      []string               `json:"jobs,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Coverage  []gocovparser.Coverage `json:"coverage"`
+
+	// Uploads holds the coverage uploaded by each job, merged into Coverage. Uploads without job are under "".
+	Uploads map[string][]gocovparser.Coverage `json:"uploads,omitempty"`
+}
+
+// Storage persists the reports of a Collector. Implementations must be safe for concurrent use.
+type Storage interface {
+	// Save stores the report, replacing the report of the same repository and commit.
+	Save(ctx context.Context, report Report) error
+
+	// Load returns the report of the commit of the repository, or ErrReportNotFound.
+	Load(ctx context.Context, repo, commit string) (Report, error)
+
+	// Reports returns the reports of the repository, or of every repository if repo is blank, oldest first.
+	Reports(ctx context.Context, repo string) ([]Report, error)
+}
+
+type memoryStorage struct {
+	mu      sync.RWMutex
+	reports map[string]map[string]Report
+}
+
+var _ Storage = (*memoryStorage)(nil)
+
+// NewMemoryStorage returns a storage holding reports in memory, e.g. for tests or short lived collectors.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{reports: map[string]map[string]Report{}}
+}
+
+func (s *memoryStorage) Save(_ context.Context, report Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.reports[report.Repo]; !found {
+		s.reports[report.Repo] = map[string]Report{}
+	}
+
+	s.reports[report.Repo][report.Commit] = report
+
+	return nil
+}
+
+func (s *memoryStorage) Load(_ context.Context, repo, commit string) (Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report, found := s.reports[repo][commit]
+	if !found {
+		return Report{}, errors.Wrapf(ErrReportNotFound, "commit %q of %q", commit, repo)
+	}
+
+	return report, nil
+}
+
+func (s *memoryStorage) Reports(_ context.Context, repo string) ([]Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reports := []Report{}
+
+	for name, commits := range s.reports {
+		if repo != "" && name != repo {
+			continue
+		}
+
+		for _, report := range commits {
+			reports = append(reports, report)
+		}
+	}
+
+	return sortedReports(reports), nil
+}
+
+type dirStorage struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+var _ Storage = (*dirStorage)(nil)
+
+// NewDirStorage returns a storage writing each report as a JSON file under dir, in a directory per repository.
+// Directories are created on the first save.
+func NewDirStorage(dir string) Storage {
+	return &dirStorage{dir: dir}
+}
+
+// path returns the file of the report, failing for keys that would resolve outside of the storage directory.
+func (s *dirStorage) path(repo, commit string) (string, error) {
+	if err := validateReportKey(repo, commit); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, url.PathEscape(repo), url.PathEscape(commit)+reportExtension)
+	if !within(s.dir, path) {
+		return "", errors.Wrapf(ErrInvalidReportKey, "commit %q of %q", commit, repo)
+	}
+
+	return path, nil
+}
+
+// within returns whether path is under dir.
+func within(dir, path string) bool {
+	relative, err := filepath.Rel(dir, path)
+
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// validateReportKey fails with ErrInvalidReportKey unless the repository is made of valid segments, e.g. org/repo,
+// and the commit is a valid segment.
+func validateReportKey(repo, commit string) error {
+	if !validRepo(repo) {
+		return errors.Wrapf(ErrInvalidReportKey, "repository %q", repo)
+	}
+
+	if !validSegment(commit) {
+		return errors.Wrapf(ErrInvalidReportKey, "commit %q", commit)
+	}
+
+	return nil
+}
+
+func validRepo(repo string) bool {
+	for _, segment := range strings.Split(repo, "/") {
+		if !validSegment(segment) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validSegment returns whether the value names a single path segment: not blank, `.` or `..`, and without slashes.
+func validSegment(value string) bool {
+	return value != "" && value != "." && value != ".." && !strings.ContainsAny(value, `/\`)
+}
+
+func (s *dirStorage) Save(_ context.Context, report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize report")
+	}
+
+	path, err := s.path(report.Repo, report.Commit)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create report directory for %q", report.Repo)
+	}
+
+	// reports are written aside and renamed, so readers never see a partial report
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write report %q", path)
+	}
+
+	return errors.Wrapf(os.Rename(tmp, path), "failed to write report %q", path)
+}
+
+func (s *dirStorage) Load(_ context.Context, repo, commit string) (Report, error) {
+	path, err := s.path(repo, commit)
+	if err != nil {
+		return Report{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report, err := readReport(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Report{}, errors.Wrapf(ErrReportNotFound, "commit %q of %q", commit, repo)
+	}
+
+	return report, err
+}
+
+func (s *dirStorage) Reports(_ context.Context, repo string) ([]Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pattern := filepath.Join(s.dir, "*", "*"+reportExtension)
+	if repo != "" {
+		if !validRepo(repo) {
+			return nil, errors.Wrapf(ErrInvalidReportKey, "repository %q", repo)
+		}
+
+		pattern = filepath.Join(s.dir, url.PathEscape(repo), "*"+reportExtension)
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list reports in %q", s.dir)
+	}
+
+	reports := make([]Report, 0, len(paths))
+
+	for _, path := range paths {
+		report, err := readReport(path)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return sortedReports(reports), nil
+}
+
+func readReport(path string) (Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Report{}, errors.Wrapf(err, "failed to read report %q", path)
+	}
+
+	report := Report{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, errors.Wrapf(err, "invalid report %q", path)
+	}
+
+	return report, nil
+}
+
+// sortedReports sorts reports by time, then by repository and commit for reports uploaded at the same time.
+func sortedReports(reports []Report) []Report {
+	sort.Slice(reports, func(i, j int) bool {
+		if !reports[i].Timestamp.Equal(reports[j].Timestamp) {
+			return reports[i].Timestamp.Before(reports[j].Timestamp)
+		}
+
+		if reports[i].Repo != reports[j].Repo {
+			return reports[i].Repo < reports[j].Repo
+		}
+
+		return reports[i].Commit < reports[j].Commit
+	})
+
+	return reports
+}
@@ -0,0 +1,130 @@
+package gocovparser
+
+import "github.com/pkg/errors"
+
+// tagStatement is a block of statements of a file and whether each tagged run covered it.
+type tagStatement struct {
+	statements    int
+	coveredFirst  bool
+	coveredSecond bool
+}
+
+// TagCoverage labels coverage results with the build tag (or any other label, e.g. "unit" or "integration")
+// the tests were run with, to compare them with CompareTags.
+func TagCoverage(tag string, items []Coverage) TaggedCoverage {
+	return TaggedCoverage{Tag: tag, Items: items}
+}
+
+// CompareTags computes the statements covered only by the first tagged run, only by the second, by both and by
+// either, per file and overall, e.g. to quantify what integration tests add over unit tests.
+// Blocks are matched by position, so both runs must come from the same sources. Statements only built with one of
+// the tags (e.g. files with a `//go:build linux` constraint) count as not covered by the other.
+func CompareTags(first, second TaggedCoverage) (TagComparison, error) {
+	files := make(map[string]map[blockPosition]*tagStatement)
+
+	add := func(items []Coverage, covered func(*tagStatement)) error {
+		for _, cov := range items {
+			blocks, found := files[cov.FileName]
+			if !found {
+				blocks = make(map[blockPosition]*tagStatement)
+				files[cov.FileName] = blocks
+			}
+
+			for _, block := range cov.Blocks {
+				position := positionOf(block)
+
+				statement, found := blocks[position]
+				if !found {
+					statement = &tagStatement{statements: block.NumStmt}
+					blocks[position] = statement
+				}
+
+				if statement.statements != block.NumStmt {
+					return errors.Wrapf(
+						ErrInconsistentCoverageBlocks,
+						"block %d.%d,%d.%d of %q has %d and %d statements",
+						block.StartLine, block.StartCol, block.EndLine, block.EndCol, cov.FileName,
+						statement.statements, block.NumStmt,
+					)
+				}
+
+				if block.Count > 0 {
+					covered(statement)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := add(first.Items, func(s *tagStatement) { s.coveredFirst = true }); err != nil {
+		return TagComparison{}, errors.Wrapf(err, "failed to compare coverage tagged %q", first.Tag)
+	}
+
+	if err := add(second.Items, func(s *tagStatement) { s.coveredSecond = true }); err != nil {
+		return TagComparison{}, errors.Wrapf(err, "failed to compare coverage tagged %q", second.Tag)
+	}
+
+	result := TagComparison{
+		First:  first.Tag,
+		Second: second.Tag,
+		Files:  make(map[string]TagOverlap, len(files)),
+	}
+
+	total := tagCounts{}
+
+	for fileName, blocks := range files {
+		counts := tagCounts{}
+
+		for _, statement := range blocks {
+			counts.add(statement)
+			total.add(statement)
+		}
+
+		result.Files[fileName] = counts.overlap()
+	}
+
+	result.Total = total.overlap()
+
+	return result, nil
+}
+
+// tagCounts accumulates the statement counts of a TagOverlap.
+type tagCounts struct {
+	total, first, second, onlyFirst, onlySecond, both, either int
+}
+
+func (c *tagCounts) add(statement *tagStatement) {
+	c.total += statement.statements
+
+	switch {
+	case statement.coveredFirst && statement.coveredSecond:
+		c.first += statement.statements
+		c.second += statement.statements
+		c.both += statement.statements
+		c.either += statement.statements
+	case statement.coveredFirst:
+		c.first += statement.statements
+		c.onlyFirst += statement.statements
+		c.either += statement.statements
+	case statement.coveredSecond:
+		c.second += statement.statements
+		c.onlySecond += statement.statements
+		c.either += statement.statements
+	}
+}
+
+func (c tagCounts) overlap() TagOverlap {
+	detail := func(covered int) GroupDetail {
+		return GroupDetail{Covered: covered, Total: c.total, Percent: percentOf(covered, c.total)}
+	}
+
+	return TagOverlap{
+		First:        detail(c.first),
+		Second:       detail(c.second),
+		OnlyFirst:    detail(c.onlyFirst),
+		OnlySecond:   detail(c.onlySecond),
+		Intersection: detail(c.both),
+		Union:        detail(c.either),
+	}
+}
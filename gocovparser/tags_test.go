@@ -0,0 +1,68 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanCompareTags(t *testing.T) {
+	unit, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,11.2 2 1
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,21.2 3 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:30.1,31.2 1 1
+`)
+	require.NoError(t, err)
+
+	integration, err := gocovparser.Parse(`
+mode: count
+github.com/heynemann/go-cov-parser/gocovparser/core.go:10.1,11.2 2 0
+github.com/heynemann/go-cov-parser/gocovparser/core.go:20.1,21.2 3 4
+github.com/heynemann/go-cov-parser/gocovparser/core.go:30.1,31.2 1 2
+github.com/heynemann/go-cov-parser/gocovparser/linux.go:1.1,2.2 4 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.CompareTags(
+		gocovparser.TagCoverage("unit", unit),
+		gocovparser.TagCoverage("integration", integration),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, "unit", got.First)
+	assert.Equal(t, "integration", got.Second)
+
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 3, Total: 10, Percent: 0.3}, got.Total.First)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 8, Total: 10, Percent: 0.8}, got.Total.Second)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 2, Total: 10, Percent: 0.2}, got.Total.OnlyFirst)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 7, Total: 10, Percent: 0.7}, got.Total.OnlySecond)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 1, Total: 10, Percent: 0.1}, got.Total.Intersection)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 10, Total: 10, Percent: 1}, got.Total.Union)
+
+	require.Len(t, got.Files, 2)
+	linux := got.Files["github.com/heynemann/go-cov-parser/gocovparser/linux.go"]
+	assert.Equal(t, 0, linux.First.Covered)
+	assert.Equal(t, 4, linux.OnlySecond.Covered)
+	assert.Equal(t, 4, linux.Union.Total)
+}
+
+func TestCompareTagsFailsOnInconsistentBlocks(t *testing.T) {
+	first, err := gocovparser.Parse("mode: set\nexample.com/a/b/c.go:1.1,2.2 2 1\n")
+	require.NoError(t, err)
+
+	second, err := gocovparser.Parse("mode: set\nexample.com/a/b/c.go:1.1,2.2 3 1\n")
+	require.NoError(t, err)
+
+	// ACT
+	_, err = gocovparser.CompareTags(gocovparser.TagCoverage("a", first), gocovparser.TagCoverage("b", second))
+
+	// ASSERT
+	assert.ErrorIs(t, err, gocovparser.ErrInconsistentCoverageBlocks)
+}
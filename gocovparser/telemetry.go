@@ -0,0 +1,181 @@
+package gocovparser
+
+import (
+	"context"
+	"time"
+)
+
+// InstrumentationName is the name tracers are requested with from the TracerProvider of Telemetry.
+const InstrumentationName = "github.com/heynemann/go-cov-parser/gocovparser"
+
+// Spans started by the instrumented operations.
+const (
+	SpanParse         = "gocovparser.parse"
+	SpanGroupCoverage = "gocovparser.group_coverage"
+	SpanExport        = "gocovparser.export"
+	SpanUpload        = "gocovparser.upload"
+)
+
+// Metrics recorded by the instrumented operations, with the name of their span as AttributeOperation.
+const (
+	// MetricDuration is the duration of operations, in seconds.
+	MetricDuration = "gocovparser.duration"
+
+	// MetricFiles is the number of files parsed, grouped, exported or uploaded by operations.
+	MetricFiles = "gocovparser.files"
+
+	// MetricBytes is the number of bytes read or written by operations.
+	MetricBytes = "gocovparser.bytes"
+)
+
+// Attributes set on spans and metrics.
+const (
+	AttributeOperation = "gocovparser.operation"
+	AttributeFiles     = "gocovparser.files"
+	AttributeBytes     = "gocovparser.bytes"
+	AttributeFormat    = "gocovparser.format"
+	AttributeService   = "gocovparser.service"
+	AttributeGroups    = "gocovparser.groups"
+	AttributeError     = "error"
+)
+
+// Attribute is a key and value set on spans and metrics. Values are strings, string slices, bools, ints,
+// int64s or float64s.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// TracerProvider returns the tracers spans are started with. Its methods mirror the OpenTelemetry API, so an
+// OpenTelemetry trace.TracerProvider is plugged in with a thin adapter.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a traced operation.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Meter records the metrics of operations, e.g. in OpenTelemetry histograms named after the metric.
+type Meter interface {
+	Record(ctx context.Context, metric string, value float64, attrs ...Attribute)
+}
+
+// Telemetry instruments parsing (see WithTelemetry), grouping, exporting and uploading with spans and metrics.
+// Operations are not traced without a TracerProvider, and not measured without a Meter.
+type Telemetry struct {
+	TracerProvider TracerProvider
+	Meter          Meter
+}
+
+// WithTelemetry traces the parsing in a SpanParse span and records its metrics. Parsing with a context (see
+// ParseReaderContext) makes the span a child of the span of the context.
+func WithTelemetry(telemetry Telemetry) ParseOption {
+	return func(opts *parseOptions) {
+		opts.telemetry = telemetry
+	}
+}
+
+// withContext sets the context parsing is traced in.
+func withContext(ctx context.Context) ParseOption {
+	return func(opts *parseOptions) {
+		opts.ctx = ctx
+	}
+}
+
+// Operation is an operation instrumented by Telemetry.Start.
+type Operation struct {
+	ctx   context.Context
+	name  string
+	start time.Time
+	span  Span
+	meter Meter
+}
+
+// Start starts an operation, in a span named spanName if traced. The returned context holds the span, to trace
+// nested operations. The operation must be ended with End.
+func (t Telemetry) Start(ctx context.Context, spanName string) (context.Context, *Operation) {
+	operation := &Operation{ctx: ctx, name: spanName, start: time.Now(), meter: t.Meter}
+
+	if t.TracerProvider != nil {
+		ctx, operation.span = t.TracerProvider.Tracer(InstrumentationName).Start(ctx, spanName)
+	}
+
+	return ctx, operation
+}
+
+// End ends the operation, setting attrs on its span and recording err if not nil. Its duration is recorded in
+// MetricDuration, and the AttributeFiles and AttributeBytes attributes in MetricFiles and MetricBytes.
+func (o *Operation) End(err error, attrs ...Attribute) {
+	if o.span != nil {
+		o.span.SetAttributes(attrs...)
+
+		if err != nil {
+			o.span.RecordError(err)
+		}
+
+		o.span.End()
+	}
+
+	if o.meter == nil {
+		return
+	}
+
+	metricAttrs := []Attribute{{Key: AttributeOperation, Value: o.name}, {Key: AttributeError, Value: err != nil}}
+
+	o.meter.Record(o.ctx, MetricDuration, time.Since(o.start).Seconds(), metricAttrs...)
+
+	for _, attr := range attrs {
+		metric := ""
+
+		switch attr.Key {
+		case AttributeFiles:
+			metric = MetricFiles
+		case AttributeBytes:
+			metric = MetricBytes
+		default:
+			continue
+		}
+
+		if value, ok := numberOf(attr.Value); ok {
+			o.meter.Record(o.ctx, metric, value, metricAttrs...)
+		}
+	}
+}
+
+// GroupCoverage groups coverage as GroupCoverageContext does, in a SpanGroupCoverage span.
+func (t Telemetry) GroupCoverage(ctx context.Context, items []Coverage, groups ...ParseGroup) (ParseGroupResult, error) {
+	ctx, operation := t.Start(ctx, SpanGroupCoverage)
+
+	result, err := GroupCoverageContext(ctx, items, groups...)
+
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+
+	operation.End(err, Attribute{Key: AttributeFiles, Value: len(items)}, Attribute{Key: AttributeGroups, Value: names})
+
+	return result, err
+}
+
+func numberOf(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
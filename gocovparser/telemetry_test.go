@@ -0,0 +1,127 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parentKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	errs   []error
+	ended  bool
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Tracer(string) gocovparser.Tracer {
+	return t
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, gocovparser.Span) {
+	parent, _ := ctx.Value(parentKey{}).(string)
+	span := &recordedSpan{name: spanName, parent: parent, attrs: map[string]any{}}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, parentKey{}, spanName), span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...gocovparser.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+type recordingMeter struct {
+	values map[string]float64
+}
+
+func (m *recordingMeter) Record(_ context.Context, metric string, value float64, _ ...gocovparser.Attribute) {
+	m.values[metric] += value
+}
+
+func TestParseWithTelemetry(t *testing.T) {
+	data := "mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 1\ngithub.com/a/b/d.go:1.1,2.2 1 0\n"
+	tracer := &recordingTracer{}
+	meter := &recordingMeter{values: map[string]float64{}}
+	ctx := context.WithValue(context.Background(), parentKey{}, "ci")
+
+	// ACT
+	_, err := gocovparser.ParseContext(ctx, data, gocovparser.WithTelemetry(gocovparser.Telemetry{
+		TracerProvider: tracer,
+		Meter:          meter,
+	}))
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+
+	span := tracer.spans[0]
+	assert.Equal(t, gocovparser.SpanParse, span.name)
+	assert.Equal(t, "ci", span.parent)
+	assert.True(t, span.ended)
+	assert.Empty(t, span.errs)
+	assert.Equal(t, 2, span.attrs[gocovparser.AttributeFiles])
+	assert.EqualValues(t, len(data), span.attrs[gocovparser.AttributeBytes])
+
+	assert.Equal(t, 2.0, meter.values[gocovparser.MetricFiles])
+	assert.Equal(t, float64(len(data)), meter.values[gocovparser.MetricBytes])
+	assert.Contains(t, meter.values, gocovparser.MetricDuration)
+}
+
+func TestParseWithTelemetryRecordsErrors(t *testing.T) {
+	tracer := &recordingTracer{}
+
+	// ACT
+	_, err := gocovparser.Parse("not coverage", gocovparser.WithTelemetry(gocovparser.Telemetry{TracerProvider: tracer}))
+
+	// ASSERT
+	require.Error(t, err)
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, []error{err}, tracer.spans[0].errs)
+}
+
+func TestGroupCoverageWithTelemetry(t *testing.T) {
+	items, err := gocovparser.Parse("mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 1\n")
+	require.NoError(t, err)
+
+	tracer := &recordingTracer{}
+	telemetry := gocovparser.Telemetry{TracerProvider: tracer}
+
+	// ACT
+	result, err := telemetry.GroupCoverage(context.Background(), items, gocovparser.TotalParseGroup)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, result["total"]["total"])
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, gocovparser.SpanGroupCoverage, tracer.spans[0].name)
+	assert.Equal(t, []string{"total"}, tracer.spans[0].attrs[gocovparser.AttributeGroups])
+}
+
+func TestTelemetryWithoutProviders(t *testing.T) {
+	// ACT
+	_, err := gocovparser.Parse("mode: set\ngithub.com/a/b/c.go:1.1,2.2 1 1\n", gocovparser.WithTelemetry(gocovparser.Telemetry{}))
+
+	// ASSERT
+	require.NoError(t, err)
+}
@@ -0,0 +1,69 @@
+package gocovparser
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// BuildCoverageTree aggregates coverage in a tree of repositories, directories and files.
+// Children are sorted by name. Files without a repository are placed directly under the root.
+func BuildCoverageTree(items []Coverage, opts ...PercentOption) *CoverageNode {
+	root := &CoverageNode{}
+	nodes := map[string]*CoverageNode{"": root}
+
+	// child returns the child of parent named name, creating it if needed.
+	child := func(parent *CoverageNode, name string) *CoverageNode {
+		nodePath := path.Join(parent.Path, name)
+
+		node, found := nodes[nodePath]
+		if !found {
+			node = &CoverageNode{Name: name, Path: nodePath}
+			nodes[nodePath] = node
+			parent.Children = append(parent.Children, node)
+		}
+
+		return node
+	}
+
+	for _, cov := range items {
+		covered, total := statementTotals(cov.Blocks)
+		node := root
+
+		node.add(covered, total)
+
+		if repo := path.Join(cov.Host, cov.Owner, cov.Repo); repo != "" {
+			node = child(node, repo)
+			node.add(covered, total)
+		}
+
+		for _, name := range strings.Split(cov.Path, "/") {
+			node = child(node, name)
+			node.add(covered, total)
+		}
+
+		node.IsFile = true
+	}
+
+	root.finish(newPercentOptions(opts))
+
+	return root
+}
+
+func (n *CoverageNode) add(covered, total int) {
+	n.Detail.Covered += covered
+	n.Detail.Total += total
+}
+
+// finish computes the percentages and sorts the children of the subtree.
+func (n *CoverageNode) finish(options percentOptions) {
+	n.Detail.Percent = options.percentOf(n.Detail.Covered, n.Detail.Total)
+
+	sort.Slice(n.Children, func(i, j int) bool {
+		return n.Children[i].Name < n.Children[j].Name
+	})
+
+	for _, child := range n.Children {
+		child.finish(options)
+	}
+}
@@ -0,0 +1,60 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanBuildCoverageTree(t *testing.T) {
+	items, err := gocovparser.Parse(`
+mode: set
+github.com/heynemann/go-cov-parser/pkg/b.go:3.10,4.12 1 1
+github.com/heynemann/go-cov-parser/pkg/b.go:4.12,6.3 3 0
+github.com/heynemann/go-cov-parser/pkg/sub/c.go:3.10,5.2 2 1
+github.com/heynemann/go-cov-parser/main.go:3.10,5.2 2 0
+local/tool.go:3.10,5.2 1 1
+`)
+	require.NoError(t, err)
+
+	// ACT
+	root := gocovparser.BuildCoverageTree(items)
+
+	// ASSERT
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 4, Total: 9, Percent: 4.0 / 9}, root.Detail)
+	require.Len(t, root.Children, 2)
+
+	repo := root.Children[0]
+	assert.Equal(t, "github.com/heynemann/go-cov-parser", repo.Name)
+	assert.Equal(t, 8, repo.Detail.Total)
+	require.Len(t, repo.Children, 2)
+
+	assert.Equal(t, "main.go", repo.Children[0].Name)
+	assert.True(t, repo.Children[0].IsFile)
+
+	pkg := repo.Children[1]
+	assert.Equal(t, "github.com/heynemann/go-cov-parser/pkg", pkg.Path)
+	assert.False(t, pkg.IsFile)
+	assert.Equal(t, gocovparser.GroupDetail{Covered: 3, Total: 6, Percent: 0.5}, pkg.Detail)
+	require.Len(t, pkg.Children, 2)
+	assert.Equal(t, "b.go", pkg.Children[0].Name)
+	assert.Equal(t, "sub", pkg.Children[1].Name)
+	assert.EqualValues(t, 1, pkg.Children[1].Detail.Percent)
+
+	local := root.Children[1]
+	assert.Equal(t, "local", local.Name)
+	require.Len(t, local.Children, 1)
+	assert.Equal(t, "local/tool.go", local.Children[0].Path)
+}
+
+func TestCoverageTreeOfEmptyCoverage(t *testing.T) {
+	// ACT
+	root := gocovparser.BuildCoverageTree(nil)
+
+	// ASSERT
+	assert.Equal(t, &gocovparser.CoverageNode{}, root)
+}
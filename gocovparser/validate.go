@@ -0,0 +1,62 @@
+package gocovparser
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// ValidateAgainstSource checks that the coverage matches the source files (their SourcePath under rootDir), so
+// annotated reports are not built from a stale profile: every file must exist and every block must start and end
+// within the lines and columns of the file.
+func ValidateAgainstSource(items []Coverage, rootDir string) (StalenessReport, error) {
+	report := StalenessReport{Stale: []StaleFile{}}
+
+	for _, cov := range SortCoverage(items) {
+		report.Files++
+
+		contents, err := os.ReadFile(SourcePath(cov, rootDir))
+		if errors.Is(err, os.ErrNotExist) {
+			report.Stale = append(report.Stale, StaleFile{FileName: cov.FileName, Path: cov.Path, Missing: true})
+
+			continue
+		}
+
+		if err != nil {
+			return StalenessReport{}, errors.Wrapf(err, "failed to read source of %q", cov.FileName)
+		}
+
+		lines := bytes.Split(bytes.TrimSuffix(contents, []byte("\n")), []byte("\n"))
+		stale := StaleFile{FileName: cov.FileName, Path: cov.Path, Lines: len(lines)}
+
+		for _, b := range cov.Blocks {
+			if !fitsInSource(b, lines) {
+				stale.InvalidBlocks = append(stale.InvalidBlocks, b)
+			}
+		}
+
+		if len(stale.InvalidBlocks) > 0 {
+			report.Stale = append(report.Stale, stale)
+		}
+	}
+
+	return report, nil
+}
+
+// IsStale returns whether any validated file does not match its source.
+func (r StalenessReport) IsStale() bool {
+	return len(r.Stale) > 0
+}
+
+// fitsInSource returns whether the block starts and ends within the lines of the source. Columns are byte offsets
+// starting at 1, and may point right after the last byte of a line.
+func fitsInSource(b cover.ProfileBlock, lines [][]byte) bool {
+	if b.StartLine < 1 || b.EndLine > len(lines) || b.StartLine > b.EndLine {
+		return false
+	}
+
+	return b.StartCol >= 1 && b.StartCol <= len(lines[b.StartLine-1])+1 &&
+		b.EndCol >= 1 && b.EndCol <= len(lines[b.EndLine-1])+1
+}
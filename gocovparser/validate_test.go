@@ -0,0 +1,76 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+const validateSource = `package pkg
+
+func A() {
+	println("a")
+}
+`
+
+func TestValidateAgainstSource(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte(validateSource), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "b.go"), []byte(validateSource), 0o600))
+
+	items, err := gocovparser.Parse(`mode: set
+github.com/owner/repo/pkg/a.go:3.10,5.2 1 1
+github.com/owner/repo/pkg/b.go:3.10,5.2 1 1
+github.com/owner/repo/pkg/b.go:4.2,4.40 1 1
+github.com/owner/repo/pkg/b.go:7.10,9.2 1 0
+github.com/owner/repo/pkg/missing.go:1.1,2.2 1 0
+`)
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ValidateAgainstSource(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.True(t, got.IsStale())
+	assert.Equal(t, gocovparser.StalenessReport{
+		Files: 3,
+		Stale: []gocovparser.StaleFile{
+			{
+				FileName: "github.com/owner/repo/pkg/b.go",
+				Path:     "pkg/b.go",
+				Lines:    5,
+				InvalidBlocks: []cover.ProfileBlock{
+					{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 40, NumStmt: 1, Count: 1},
+					{StartLine: 7, StartCol: 10, EndLine: 9, EndCol: 2, NumStmt: 1, Count: 0},
+				},
+			},
+			{FileName: "github.com/owner/repo/pkg/missing.go", Path: "pkg/missing.go", Missing: true},
+		},
+	}, got)
+}
+
+func TestValidateAgainstSourceOfUpToDateFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte(validateSource), 0o600))
+
+	items, err := gocovparser.Parse("mode: set\ngithub.com/owner/repo/pkg/a.go:3.10,5.2 1 1\n")
+	require.NoError(t, err)
+
+	// ACT
+	got, err := gocovparser.ValidateAgainstSource(items, root)
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.False(t, got.IsStale())
+	assert.Equal(t, 1, got.Files)
+}
@@ -0,0 +1,159 @@
+package gocovparser
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/cover"
+)
+
+// BlockVisitor is called by WalkBlocks with each block and the file it belongs to. Return ErrSkipFile to skip the
+// rest of the file, ErrStopWalk to stop walking, or any other error to stop walking with it.
+type BlockVisitor func(file Coverage, block cover.ProfileBlock) error
+
+// LineVisitor is called by WalkLines with each line with statements and the file it belongs to. Its errors are
+// handled as those of a BlockVisitor.
+type LineVisitor func(file Coverage, line int, coverage LineCoverage) error
+
+// WalkBlocks calls fn with every block of the items, by file name and then by block position, without copying the
+// items or their blocks. It returns the error of fn, unless ErrSkipFile or ErrStopWalk.
+//
+// Items of the same file are visited in the order they are given: merge them first (see MergeCoverage) to visit
+// each file once.
+func WalkBlocks(items []Coverage, fn BlockVisitor) error {
+	var order []int
+
+	return walkFiles(items, func(file Coverage) error {
+		order = walkOrder(order, len(file.Blocks), func(i, j int) bool {
+			return blockLess(file.Blocks[i], file.Blocks[j])
+		})
+
+		for _, index := range order {
+			if err := fn(file, file.Blocks[index]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// WalkLines calls fn with the coverage of every line with statements of the items, by file name and then by line
+// number. Lines are computed for each item as GetLineCoverage does, and items are visited as by WalkBlocks.
+func WalkLines(items []Coverage, fn LineVisitor) error {
+	lines := &fileLines{}
+
+	return walkFiles(items, func(file Coverage) error {
+		lines.reset(file.Blocks)
+
+		for offset, seen := range lines.seen {
+			if !seen {
+				continue
+			}
+
+			if err := fn(file, lines.first+offset, lines.coverage[offset]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// walkFiles calls fn with each item by file name, handling the ErrSkipFile and ErrStopWalk errors of visitors.
+func walkFiles(items []Coverage, fn func(file Coverage) error) error {
+	order := walkOrder(nil, len(items), func(i, j int) bool {
+		return items[i].FileName < items[j].FileName
+	})
+
+	for _, index := range order {
+		err := fn(items[index])
+
+		switch {
+		case err == nil, errors.Is(err, ErrSkipFile):
+		case errors.Is(err, ErrStopWalk):
+			return nil
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkOrder returns the indexes of n elements stably sorted by less, reusing the indexes slice.
+func walkOrder(indexes []int, n int, less func(i, j int) bool) []int {
+	indexes = indexes[:0]
+	for index := 0; index < n; index++ {
+		indexes = append(indexes, index)
+	}
+
+	for index := 1; index < n; index++ {
+		if less(index, index-1) {
+			sort.SliceStable(indexes, func(i, j int) bool {
+				return less(indexes[i], indexes[j])
+			})
+
+			break
+		}
+	}
+
+	return indexes
+}
+
+// fileLines is the line coverage of a file, indexed by line number from its first line with statements. Its
+// slices are reused from file to file.
+type fileLines struct {
+	first    int
+	seen     []bool
+	coverage []LineCoverage
+}
+
+func (l *fileLines) reset(blocks []cover.ProfileBlock) {
+	first, last := 0, -1
+
+	for _, b := range blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+
+		if last < first || b.StartLine < first {
+			first = b.StartLine
+		}
+
+		if b.EndLine > last {
+			last = b.EndLine
+		}
+	}
+
+	size := last - first + 1
+	if size < 0 {
+		size = 0
+	}
+
+	l.first = first
+
+	if cap(l.seen) < size {
+		l.seen, l.coverage = make([]bool, size), make([]LineCoverage, size)
+	} else {
+		l.seen, l.coverage = l.seen[:size], l.coverage[:size]
+
+		for offset := range l.seen {
+			l.seen[offset], l.coverage[offset] = false, LineCoverage{}
+		}
+	}
+
+	strategy := MergeDefault.lines()
+
+	for _, b := range blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+
+		for line := b.StartLine; line <= b.EndLine; line++ {
+			offset := line - first
+			l.coverage[offset] = mergeLine(l.coverage[offset], l.seen[offset], b.Count, strategy)
+			l.seen[offset] = true
+		}
+	}
+}
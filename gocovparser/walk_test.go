@@ -0,0 +1,112 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func walkFixture() []gocovparser.Coverage {
+	return []gocovparser.Coverage{
+		{FileName: "pkg/b.go", Blocks: []cover.ProfileBlock{
+			{StartLine: 5, EndLine: 6, NumStmt: 1, Count: 1},
+		}},
+		{FileName: "pkg/a.go", Blocks: []cover.ProfileBlock{
+			{StartLine: 10, EndLine: 11, NumStmt: 1, Count: 0},
+			{StartLine: 1, EndLine: 2, NumStmt: 2, Count: 3},
+			{StartLine: 2, EndLine: 2, NumStmt: 0, Count: 0},
+		}},
+	}
+}
+
+func TestWalkBlocksInFileAndPositionOrder(t *testing.T) {
+	visited := []string{}
+
+	// ACT
+	err := gocovparser.WalkBlocks(walkFixture(), func(file gocovparser.Coverage, block cover.ProfileBlock) error {
+		visited = append(visited, fmt.Sprintf("%s:%d", file.FileName, block.StartLine))
+
+		return nil
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pkg/a.go:1", "pkg/a.go:2", "pkg/a.go:10", "pkg/b.go:5"}, visited)
+}
+
+func TestWalkBlocksStopsEarly(t *testing.T) {
+	items := walkFixture()
+	failure := errors.New("analysis failed")
+	count := func(stopWith error) (int, error) {
+		visits := 0
+		err := gocovparser.WalkBlocks(items, func(gocovparser.Coverage, cover.ProfileBlock) error {
+			visits++
+
+			return stopWith
+		})
+
+		return visits, err
+	}
+
+	// ACT
+	skipped, skipErr := count(gocovparser.ErrSkipFile)
+	stopped, stopErr := count(gocovparser.ErrStopWalk)
+	failed, failErr := count(failure)
+
+	// ASSERT
+	assert.Equal(t, 2, skipped)
+	require.NoError(t, skipErr)
+	assert.Equal(t, 1, stopped)
+	require.NoError(t, stopErr)
+	assert.Equal(t, 1, failed)
+	assert.ErrorIs(t, failErr, failure)
+}
+
+func TestWalkLinesMatchesLineCoverage(t *testing.T) {
+	items, err := gocovparser.Parse(CoverageFixture(t))
+	require.NoError(t, err)
+
+	expected := gocovparser.GetLineCoverage(items)
+	got := map[string]gocovparser.FileLineCoverage{}
+	previous := 0
+
+	// ACT
+	err = gocovparser.WalkLines(items, func(file gocovparser.Coverage, line int, coverage gocovparser.LineCoverage) error {
+		assert.Greater(t, line, previous)
+		previous = line
+
+		if got[file.FileName] == nil {
+			got[file.FileName] = gocovparser.FileLineCoverage{}
+		}
+
+		got[file.FileName][line] = coverage
+
+		return nil
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestWalkLinesSkipsFiles(t *testing.T) {
+	visited := []int{}
+
+	// ACT
+	err := gocovparser.WalkLines(walkFixture(), func(_ gocovparser.Coverage, line int, _ gocovparser.LineCoverage) error {
+		visited = append(visited, line)
+
+		return gocovparser.ErrSkipFile
+	})
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 5}, visited)
+}
@@ -0,0 +1,207 @@
+// Package watch re-parses coverage files and GOCOVERDIR directories when they change.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/pkg/errors"
+)
+
+const defaultDebounce = 100 * time.Millisecond
+
+// Update is the coverage parsed after a change, or the error that prevented parsing it.
+type Update struct {
+	Coverage  []gocovparser.Coverage
+	Breakdown gocovparser.OverallCoverageBreakdown
+	Err       error
+}
+
+// Option configures a Watcher.
+type Option func(*options)
+
+type options struct {
+	debounce     time.Duration
+	parseOptions []gocovparser.ParseOption
+}
+
+// WithDebounce sets how long to wait for changes to settle before parsing again, as `go test` writes
+// several files per run. Defaults to 100ms.
+func WithDebounce(debounce time.Duration) Option {
+	return func(opts *options) {
+		opts.debounce = debounce
+	}
+}
+
+// WithParseOptions sets the options used to parse the coverage.
+func WithParseOptions(parseOptions ...gocovparser.ParseOption) Option {
+	return func(opts *options) {
+		opts.parseOptions = append(opts.parseOptions, parseOptions...)
+	}
+}
+
+// Watcher delivers the merged coverage of its paths on every change.
+type Watcher struct {
+	files   []string
+	dirs    []string
+	opts    options
+	watcher *fsnotify.Watcher
+	updates chan Update
+	done    chan struct{}
+	close   sync.Once
+}
+
+// NewWatcher watches paths, which are coverage files or GOCOVERDIR directories. The current coverage
+// is delivered right away, and again after each change. Close the watcher to stop it.
+func NewWatcher(paths []string, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		opts:    options{debounce: defaultDebounce},
+		updates: make(chan Update),
+		done:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file watcher")
+	}
+
+	w.watcher = watcher
+
+	for _, path := range paths {
+		watched := filepath.Dir(path)
+
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			w.dirs = append(w.dirs, filepath.Clean(path))
+			watched = path
+		} else {
+			w.files = append(w.files, filepath.Clean(path))
+		}
+
+		// files are watched through their directory, so they can be replaced (e.g. written and renamed)
+		if err := watcher.Add(watched); err != nil {
+			watcher.Close()
+
+			return nil, errors.Wrapf(err, "failed to watch %q", path)
+		}
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Updates returns the channel updates are delivered on. It is closed when the watcher is closed.
+func (w *Watcher) Updates() <-chan Update {
+	return w.updates
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	err := error(nil)
+
+	w.close.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+
+	return errors.Wrap(err, "failed to close file watcher")
+}
+
+func (w *Watcher) run() {
+	defer close(w.updates)
+
+	w.send(w.parse())
+
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if w.relevant(event) {
+				fire = time.After(w.opts.debounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			w.send(Update{Err: errors.Wrap(err, "failed to watch coverage")})
+		case <-fire:
+			fire = nil
+
+			w.send(w.parse())
+		}
+	}
+}
+
+func (w *Watcher) send(update Update) {
+	select {
+	case w.updates <- update:
+	case <-w.done:
+	}
+}
+
+// relevant returns whether the event changes a watched file or happens in a watched directory.
+func (w *Watcher) relevant(event fsnotify.Event) bool {
+	name := filepath.Clean(event.Name)
+
+	for _, file := range w.files {
+		if name == file {
+			return true
+		}
+	}
+
+	for _, dir := range w.dirs {
+		if filepath.Dir(name) == dir {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *Watcher) parse() Update {
+	results := [][]gocovparser.Coverage{}
+
+	if len(w.files) > 0 {
+		items, err := gocovparser.ParseFiles(w.files, w.opts.parseOptions...)
+		if err != nil {
+			return Update{Err: err}
+		}
+
+		results = append(results, items)
+	}
+
+	for _, dir := range w.dirs {
+		items, err := gocovparser.ParseCovDataDir(dir, w.opts.parseOptions...)
+		if err != nil {
+			return Update{Err: err}
+		}
+
+		results = append(results, items)
+	}
+
+	merged, err := gocovparser.MergeCoverage(results...)
+	if err != nil {
+		return Update{Err: err}
+	}
+
+	return Update{
+		Coverage:  merged,
+		Breakdown: gocovparser.GetTotalCoverageBreakdown(merged),
+	}
+}
@@ -0,0 +1,100 @@
+package watch_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heynemann/go-cov-parser/gocovparser/watch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const watchTimeout = 5 * time.Second
+
+func nextUpdate(t *testing.T, watcher *watch.Watcher) watch.Update {
+	t.Helper()
+
+	select {
+	case update, ok := <-watcher.Updates():
+		require.True(t, ok, "updates channel closed")
+
+		return update
+	case <-time.After(watchTimeout):
+		require.FailNow(t, "timed out waiting for a coverage update")
+	}
+
+	return watch.Update{}
+}
+
+func TestWatcherDeliversCoverageOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	require.NoError(t, os.WriteFile(path, []byte("mode: set\ngithub.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 0\n"), 0o600))
+
+	watcher, err := watch.NewWatcher([]string{path}, watch.WithDebounce(10*time.Millisecond))
+	require.NoError(t, err)
+
+	defer watcher.Close()
+
+	initial := nextUpdate(t, watcher)
+	require.NoError(t, initial.Err)
+	assert.EqualValues(t, 0, initial.Breakdown.Coverage)
+
+	// ACT
+	require.NoError(t, os.WriteFile(path, []byte("mode: set\ngithub.com/heynemann/go-cov-parser/pkg/a.go:3.10,5.2 1 1\n"), 0o600))
+	got := nextUpdate(t, watcher)
+
+	// ASSERT
+	require.NoError(t, got.Err)
+	require.Len(t, got.Coverage, 1)
+	assert.EqualValues(t, 1, got.Breakdown.Coverage)
+}
+
+func TestWatcherReportsParseErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	require.NoError(t, os.WriteFile(path, []byte("not coverage\n"), 0o600))
+
+	watcher, err := watch.NewWatcher([]string{path})
+	require.NoError(t, err)
+
+	defer watcher.Close()
+
+	// ACT
+	got := nextUpdate(t, watcher)
+
+	// ASSERT
+	assert.Error(t, got.Err)
+}
+
+func TestClosingWatcherClosesUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	require.NoError(t, os.WriteFile(path, []byte("mode: set\n"), 0o600))
+
+	watcher, err := watch.NewWatcher([]string{path})
+	require.NoError(t, err)
+
+	// ACT
+	require.NoError(t, watcher.Close())
+	require.NoError(t, watcher.Close())
+
+	// ASSERT
+	select {
+	case _, ok := <-watcher.Updates():
+		for ok {
+			_, ok = <-watcher.Updates()
+		}
+	case <-time.After(watchTimeout):
+		require.FailNow(t, "updates channel was not closed")
+	}
+}
+
+func TestWatcherFailsForMissingDirectory(t *testing.T) {
+	// ACT
+	_, err := watch.NewWatcher([]string{filepath.Join(t.TempDir(), "missing", "coverage.out")})
+
+	// ASSERT
+	assert.Error(t, err)
+}
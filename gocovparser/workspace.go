@@ -0,0 +1,100 @@
+package gocovparser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithGoWork reads the modules used by the go.work file at goWorkPath, so file names of every module of the
+// workspace are split at their module boundary, as WithGoMod does, and get their Module and LocalPath.
+func WithGoWork(goWorkPath string) ParseOption {
+	return func(opts *parseOptions) {
+		opts.goWorkFiles = append(opts.goWorkFiles, goWorkPath)
+	}
+}
+
+// FindGoWork returns the path of the go.work file in dir or in its closest parent directory.
+func FindGoWork(dir string) (string, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve directory %q", dir)
+	}
+
+	for {
+		goWorkPath := filepath.Join(current, "go.work")
+
+		info, err := os.Stat(goWorkPath)
+		if err == nil && !info.IsDir() {
+			return goWorkPath, nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", errors.Wrapf(ErrGoWorkNotFound, "searched from %q", dir)
+		}
+
+		current = parent
+	}
+}
+
+// ReadGoWork returns the modules used by the go.work file at goWorkPath, reading the module path of each used
+// directory from its go.mod file. Directories are resolved relative to the go.work file.
+func ReadGoWork(goWorkPath string) ([]Module, error) {
+	file, err := os.Open(goWorkPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open go.work file %q", goWorkPath)
+	}
+	defer file.Close()
+
+	dirs := []string{}
+	inUseBlock := false
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if index := strings.Index(line, "//"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+
+		switch {
+		case inUseBlock && line == ")":
+			inUseBlock = false
+		case inUseBlock && line != "":
+			dirs = append(dirs, line)
+		case line == "use (" || line == "use(":
+			inUseBlock = true
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, strings.TrimSpace(strings.TrimPrefix(line, "use ")))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read go.work file %q", goWorkPath)
+	}
+
+	modules := make([]Module, 0, len(dirs))
+
+	for _, dir := range dirs {
+		if unquoted, err := strconv.Unquote(dir); err == nil {
+			dir = unquoted
+		}
+
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(goWorkPath), filepath.FromSlash(dir))
+		}
+
+		path, err := ReadModulePath(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read module used by %q", goWorkPath)
+		}
+
+		modules = append(modules, Module{Path: path, Dir: dir})
+	}
+
+	return modules, nil
+}
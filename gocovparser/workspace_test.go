@@ -0,0 +1,82 @@
+package gocovparser_test
+
+//revive:disable:add-constant
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heynemann/go-cov-parser/gocovparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWorkspace(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "api"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "tools", "lint"), 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "go.work"),
+		[]byte("go 1.19\n\nuse (\n\t./api // service\n\t\"./tools/lint\"\n)\n"),
+		0o600,
+	))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "api", "go.mod"), []byte("module example.dev/mono/api\n"), 0o600))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "tools", "lint", "go.mod"), []byte("module example.dev/mono/tools/lint\n"), 0o600,
+	))
+
+	return root
+}
+
+func TestReadGoWork(t *testing.T) {
+	root := writeWorkspace(t)
+
+	// ACT
+	got, err := gocovparser.ReadGoWork(filepath.Join(root, "go.work"))
+
+	// ASSERT
+	require.NoError(t, err)
+	assert.Equal(t, []gocovparser.Module{
+		{Path: "example.dev/mono/api", Dir: filepath.Join(root, "api")},
+		{Path: "example.dev/mono/tools/lint", Dir: filepath.Join(root, "tools", "lint")},
+	}, got)
+}
+
+func TestCanParseUsingGoWork(t *testing.T) {
+	root := writeWorkspace(t)
+
+	// ACT
+	got, err := gocovparser.Parse(
+		"mode: set\nexample.dev/mono/api/handler/a.go:1.1,2.2 1 1\nexample.dev/mono/tools/lint/b.go:1.1,2.2 1 0\n",
+		gocovparser.WithGoWork(filepath.Join(root, "go.work")),
+	)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "example.dev/mono/api", got[0].Module)
+	assert.Equal(t, "handler/a.go", got[0].Path)
+	assert.Equal(t, filepath.Join(root, "api", "handler", "a.go"), got[0].LocalPath)
+	assert.Equal(t, "example.dev/mono/tools/lint", got[1].Module)
+	assert.Equal(t, "b.go", got[1].Path)
+	assert.Equal(t, filepath.Join(root, "tools", "lint", "b.go"), got[1].LocalPath)
+}
+
+func TestGoWorkFailures(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := gocovparser.FindGoWork(root)
+	assert.ErrorIs(t, err, gocovparser.ErrGoWorkNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.work"), []byte("use ./missing\n"), 0o600))
+
+	got, err := gocovparser.FindGoWork(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "go.work"), got)
+
+	_, err = gocovparser.Parse(CoverageFixture(t), gocovparser.WithGoWork(got))
+	require.Error(t, err)
+}